@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type ApprovalAction string
+
+const (
+	// ApprovalActionProposed is recorded when a Draft is moved to Proposed
+	ApprovalActionProposed ApprovalAction = "Proposed"
+	// ApprovalActionApproved is recorded when a Proposed revision is Published
+	ApprovalActionApproved ApprovalAction = "Approved"
+)
+
+// ApprovalRecordSpec captures a single decision taken by the approval
+// controller, together with the inputs the decision was based on.
+type ApprovalRecordSpec struct {
+	// PackageRevision is the name of the PackageRevision the decision was taken for
+	PackageRevision string `json:"packageRevision"`
+	// PackageName of the PackageRevision
+	// +optional
+	PackageName string `json:"packageName,omitempty"`
+	// RepositoryName of the PackageRevision
+	// +optional
+	RepositoryName string `json:"repositoryName,omitempty"`
+	// WorkspaceName of the PackageRevision
+	// +optional
+	WorkspaceName string `json:"workspaceName,omitempty"`
+	// Revision of the PackageRevision at the time of the decision
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// Action taken by the controller
+	// +kubebuilder:validation:Enum=Proposed;Approved
+	Action ApprovalAction `json:"action"`
+	// Policy is the approval policy that was evaluated
+	Policy string `json:"policy"`
+	// ReadinessGates is the state of the readiness gates of the PackageRevision
+	// +optional
+	ReadinessGates []ReadinessGateState `json:"readinessGates,omitempty"`
+	// PackageVariant is the state of the PackageVariant owning the
+	// PackageRevision, not set if the PackageRevision is not owned by a
+	// PackageVariant
+	// +optional
+	PackageVariant *PackageVariantState `json:"packageVariant,omitempty"`
+	// Delay is the approval delay that was applied, if any
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+	// MaintenanceWindow is the maintenance window that was applied, if any
+	// +optional
+	MaintenanceWindow string `json:"maintenanceWindow,omitempty"`
+	// MaintenanceWindowTimezone is the timezone of the maintenance window
+	// +optional
+	MaintenanceWindowTimezone string `json:"maintenanceWindowTimezone,omitempty"`
+	// Actor is the component that took the decision
+	Actor string `json:"actor"`
+	// Timestamp is the time the decision was taken
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// ReadinessGateState is the state of a readiness gate condition
+type ReadinessGateState struct {
+	// ConditionType of the readiness gate
+	ConditionType string `json:"conditionType"`
+	// Status of the condition, Unknown if the condition was not set
+	Status string `json:"status"`
+	// Reason of the condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message of the condition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PackageVariantState is the state of the Ready condition of a PackageVariant
+type PackageVariantState struct {
+	// Name of the PackageVariant
+	Name string `json:"name"`
+	// Ready is the status of the Ready condition, Unknown if the condition
+	// was not set
+	Ready string `json:"ready"`
+	// Reason of the Ready condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message of the Ready condition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="PACKAGEREVISION",type="string",JSONPath=".spec.packageRevision"
+// +kubebuilder:printcolumn:name="ACTION",type="string",JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="POLICY",type="string",JSONPath=".spec.policy"
+// +kubebuilder:printcolumn:name="TIMESTAMP",type="date",JSONPath=".spec.timestamp"
+
+// ApprovalRecord is the Schema for the approvalrecords API. It is a durable
+// audit record of a decision taken by the approval controller.
+type ApprovalRecord struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec ApprovalRecordSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApprovalRecordList contains a list of ApprovalRecords
+type ApprovalRecordList struct {
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Items           []ApprovalRecord `json:"items" yaml:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApprovalRecord{}, &ApprovalRecordList{})
+}
+
+// ApprovalRecord type metadata.
+var (
+	ApprovalRecordKind             = reflect.TypeOf(ApprovalRecord{}).Name()
+	ApprovalRecordGroupKind        = schema.GroupKind{Group: GroupVersion.Group, Kind: ApprovalRecordKind}.String()
+	ApprovalRecordKindAPIVersion   = ApprovalRecordKind + "." + GroupVersion.String()
+	ApprovalRecordGroupVersionKind = GroupVersion.WithKind(ApprovalRecordKind)
+)
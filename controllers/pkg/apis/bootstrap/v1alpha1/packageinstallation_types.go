@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ConditionTypeApplied is True when all the resources of the package are
+	// applied to all the clusters the package is installed on
+	ConditionTypeApplied = "Applied"
+	// ConditionTypeReady is True when all the resources of the package are
+	// Current on all the clusters the package is installed on
+	ConditionTypeReady = "Ready"
+)
+
+type ApplyResult string
+
+const (
+	// ApplyResultApplied is recorded when the apply changed the object
+	ApplyResultApplied ApplyResult = "Applied"
+	// ApplyResultUnchanged is recorded when the object was already up to date
+	ApplyResultUnchanged ApplyResult = "Unchanged"
+	// ApplyResultFailed is recorded when the object could not be applied
+	ApplyResultFailed ApplyResult = "Failed"
+)
+
+// ResourceStatus is the status of an object on the cluster, computed from its
+// status the way kstatus does
+type ResourceStatus string
+
+const (
+	// ResourceStatusCurrent means the object is fully reconciled
+	ResourceStatusCurrent ResourceStatus = "Current"
+	// ResourceStatusInProgress means the object is being reconciled
+	ResourceStatusInProgress ResourceStatus = "InProgress"
+	// ResourceStatusFailed means the object failed to reconcile
+	ResourceStatusFailed ResourceStatus = "Failed"
+	// ResourceStatusTerminating means the object is being deleted
+	ResourceStatusTerminating ResourceStatus = "Terminating"
+	// ResourceStatusNotFound means the object is not on the cluster
+	ResourceStatusNotFound ResourceStatus = "NotFound"
+	// ResourceStatusUnknown means the status of the object is not known
+	ResourceStatusUnknown ResourceStatus = "Unknown"
+)
+
+// PackageInstallationSpec identifies the PackageRevision installed on the
+// clusters
+type PackageInstallationSpec struct {
+	// PackageRevision is the name of the installed PackageRevision
+	PackageRevision string `json:"packageRevision"`
+	// RepositoryName of the PackageRevision
+	// +optional
+	RepositoryName string `json:"repositoryName,omitempty"`
+	// PackageName of the PackageRevision
+	// +optional
+	PackageName string `json:"packageName,omitempty"`
+	// Revision of the PackageRevision
+	// +optional
+	Revision int `json:"revision,omitempty"`
+}
+
+// PackageInstallationStatus is the result of the installation of the package
+// on each cluster
+type PackageInstallationStatus struct {
+	// Conditions aggregate the results of all the clusters
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Clusters is the result of the installation per cluster
+	// +optional
+	Clusters []ClusterStatus `json:"clusters,omitempty"`
+}
+
+// ClusterStatus is the result of the installation of the package on a cluster
+type ClusterStatus struct {
+	// Cluster is the name of the cluster
+	Cluster string `json:"cluster"`
+	// Revision of the PackageRevision that was installed
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// Resources is the result per resource of the package
+	// +optional
+	Resources []ResourceResult `json:"resources,omitempty"`
+	// Message explains why the package is not installed on the cluster,
+	// e.g. the cluster is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceResult is the result of the apply of a resource and the status of
+// the object on the cluster
+type ResourceResult struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Result of the apply
+	// +kubebuilder:validation:Enum=Applied;Unchanged;Failed
+	Result ApplyResult `json:"result"`
+	// Status of the object on the cluster
+	// +kubebuilder:validation:Enum=Current;InProgress;Failed;Terminating;NotFound;Unknown
+	Status ResourceStatus `json:"status"`
+	// Message explains the result or the status
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="PACKAGEREVISION",type="string",JSONPath=".spec.packageRevision"
+// +kubebuilder:printcolumn:name="APPLIED",type="string",JSONPath=".status.conditions[?(@.type=='Applied')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PackageInstallation is the Schema for the packageinstallations API. It
+// reports the installation of a bootstrap PackageRevision on the clusters, and
+// has the name of the PackageRevision.
+type PackageInstallation struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   PackageInstallationSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status PackageInstallationStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PackageInstallationList contains a list of PackageInstallations
+type PackageInstallationList struct {
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Items           []PackageInstallation `json:"items" yaml:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PackageInstallation{}, &PackageInstallationList{})
+}
+
+// PackageInstallation type metadata.
+var (
+	PackageInstallationKind             = reflect.TypeOf(PackageInstallation{}).Name()
+	PackageInstallationGroupKind        = schema.GroupKind{Group: GroupVersion.Group, Kind: PackageInstallationKind}.String()
+	PackageInstallationKindAPIVersion   = PackageInstallationKind + "." + GroupVersion.String()
+	PackageInstallationGroupVersionKind = GroupVersion.WithKind(PackageInstallationKind)
+)
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GitServerSpec defines a git server Repositories and Tokens can be
+// reconciled against
+type GitServerSpec struct {
+	// URL of the git server
+	URL string `json:"url"`
+	// Provider is the type of the git server
+	// +kubebuilder:validation:Enum=gitea;gitlab;github
+	// +kubebuilder:default=gitea
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// SecretRef references the Secret holding the credentials to connect to
+	// the git server: the username and password keys, or the token key
+	SecretRef corev1.SecretReference `json:"secretRef"`
+	// AllowedNamespaces lists the namespaces of the Repositories and Tokens
+	// that can use the git server, "*" allowing all namespaces. When empty
+	// only the namespace of the SecretRef is allowed.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// AllowsNamespace returns true if the Repositories and Tokens of the
+// namespace can use the git server
+func (r *GitServer) AllowsNamespace(namespace string) bool {
+	if len(r.Spec.AllowedNamespaces) == 0 {
+		return namespace == r.Spec.SecretRef.Namespace
+	}
+	for _, allowed := range r.Spec.AllowedNamespaces {
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="PROVIDER",type="string",JSONPath=".spec.provider"
+
+// GitServer is the Schema for the gitservers API. Repositories and Tokens
+// select the GitServer they are reconciled against with the
+// infra.nephio.org/git-server annotation.
+type GitServer struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec GitServerSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GitServerList contains a list of GitServers
+type GitServerList struct {
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Items           []GitServer `json:"items" yaml:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitServer{}, &GitServerList{})
+}
+
+// GitServer type metadata.
+var (
+	GitServerKind             = reflect.TypeOf(GitServer{}).Name()
+	GitServerGroupKind        = schema.GroupKind{Group: GroupVersion.Group, Kind: GitServerKind}.String()
+	GitServerKindAPIVersion   = GitServerKind + "." + GroupVersion.String()
+	GitServerGroupVersionKind = GroupVersion.WithKind(GitServerKind)
+)
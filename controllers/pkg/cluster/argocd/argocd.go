@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package argocd provides the clients of the clusters registered in Argo CD
+// with a cluster secret.
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// SecretTypeLabel labels the secrets Argo CD stores its clusters in with
+	// the value "cluster"
+	SecretTypeLabel = "argocd.argoproj.io/secret-type"
+	secretTypeValue = "cluster"
+)
+
+// clusterConfig is the config of an Argo CD cluster secret
+type clusterConfig struct {
+	Username           string          `json:"username,omitempty"`
+	Password           string          `json:"password,omitempty"`
+	BearerToken        string          `json:"bearerToken,omitempty"`
+	TLSClientConfig    tlsClientConfig `json:"tlsClientConfig"`
+	AWSAuthConfig      json.RawMessage `json:"awsAuthConfig,omitempty"`
+	ExecProviderConfig json.RawMessage `json:"execProviderConfig,omitempty"`
+}
+
+type tlsClientConfig struct {
+	Insecure   bool   `json:"insecure"`
+	ServerName string `json:"serverName,omitempty"`
+	CertData   []byte `json:"certData,omitempty"`
+	KeyData    []byte `json:"keyData,omitempty"`
+	CAData     []byte `json:"caData,omitempty"`
+}
+
+type ArgoCD struct {
+	Secret *corev1.Secret
+}
+
+// New returns the client of the cluster of an Argo CD cluster secret, false
+// if the secret is not an Argo CD cluster secret
+func New(secret *corev1.Secret) (*ArgoCD, bool) {
+	if secret.GetLabels()[SecretTypeLabel] != secretTypeValue {
+		return nil, false
+	}
+	return &ArgoCD{Secret: secret}, true
+}
+
+func (r *ArgoCD) GetClusterName() string {
+	if r.Secret == nil {
+		return ""
+	}
+	return string(r.Secret.Data["name"])
+}
+
+// GetClusterClient returns the client of the cluster, the cluster is ready
+// when its API server is ready
+func (r *ArgoCD) GetClusterClient(ctx context.Context) (resource.APIPatchingApplicator, bool, error) {
+	identity := kubeconfig.ClusterIdentity("argocd", r.Secret.GetNamespace(), r.GetClusterName())
+	config, err := r.restConfig()
+	if err != nil {
+		return resource.APIPatchingApplicator{}, false, err
+	}
+	if !kubeconfig.IsReachable(ctx, config) {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, nil
+	}
+	return kubeconfig.NewClusterClient(ctx, identity, config)
+}
+
+// restConfig returns the rest config of the cluster secret, the clusters
+// authenticated with AWS IAM or an exec provider are not supported
+func (r *ArgoCD) restConfig() (*rest.Config, error) {
+	server := string(r.Secret.Data["server"])
+	if server == "" {
+		return nil, fmt.Errorf("argocd cluster secret %s has no server", r.Secret.GetName())
+	}
+	cfg := clusterConfig{}
+	if err := json.Unmarshal(r.Secret.Data["config"], &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config in argocd cluster secret %s: %w", r.Secret.GetName(), err)
+	}
+	if len(cfg.AWSAuthConfig) > 0 || len(cfg.ExecProviderConfig) > 0 {
+		return nil, errors.New("argocd clusters with awsAuthConfig or execProviderConfig are not supported")
+	}
+	return &rest.Config{
+		Host:        server,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		BearerToken: cfg.BearerToken,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure:   cfg.TLSClientConfig.Insecure,
+			ServerName: cfg.TLSClientConfig.ServerName,
+			CertData:   cfg.TLSClientConfig.CertData,
+			KeyData:    cfg.TLSClientConfig.KeyData,
+			CAData:     cfg.TLSClientConfig.CAData,
+		},
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestConfig(t *testing.T) {
+	newSecret := func(config string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-edge01",
+				Labels: map[string]string{SecretTypeLabel: "cluster"},
+			},
+			Data: map[string][]byte{
+				"name":   []byte("edge01"),
+				"server": []byte("https://edge01.example.com:6443"),
+				"config": []byte(config),
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		config      string
+		expectedErr bool
+	}{
+		"BearerToken": {
+			config: `{"bearerToken":"t","tlsClientConfig":{"insecure":false,"caData":"Y2E="}}`,
+		},
+		"ExecProvider": {
+			config:      `{"execProviderConfig":{"command":"argocd-k8s-auth"},"tlsClientConfig":{"insecure":false}}`,
+			expectedErr: true,
+		},
+		"Invalid": {
+			config:      `{`,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, ok := New(newSecret(tc.config))
+			require.True(t, ok)
+			require.Equal(t, "edge01", a.GetClusterName())
+			config, err := a.restConfig()
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "https://edge01.example.com:6443", config.Host)
+			require.Equal(t, "t", config.BearerToken)
+			require.Equal(t, "ca", string(config.CAData))
+		})
+	}
+
+	_, ok := New(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{SecretTypeLabel: "repository"}}})
+	require.False(t, ok)
+}
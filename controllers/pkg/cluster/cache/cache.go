@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache caches the clients of the remote clusters, so the reconcilers
+// of the manager share one client per cluster instead of building a client,
+// with its REST mapper and discovery, on every reconcile.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcluster "sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Cache caches the clients of the remote clusters by cluster identity. The
+// client of a cluster is rebuilt when its credentials change, and dropped
+// when the cluster is invalidated, e.g. when it is not ready.
+type Cache struct {
+	m       sync.Mutex
+	clients map[string]*entry
+	// builds shares the build of the client of a cluster between the
+	// concurrent gets of the cluster, the clients are built without the lock
+	builds singleflight.Group
+	// ctx is the context of the informers, set when the cache is started
+	ctx       context.Context
+	informers bool
+	scheme    *runtime.Scheme
+	newClient func(config *rest.Config, options client.Options) (client.Client, error)
+}
+
+type entry struct {
+	hash   string
+	client client.Client
+	stop   context.CancelFunc
+}
+
+// New returns an empty cache
+func New() *Cache {
+	return &Cache{
+		clients:   map[string]*entry{},
+		newClient: client.New,
+	}
+}
+
+var defaultCache = New()
+
+// Default returns the cache shared by all the reconcilers of the manager
+func Default() *Cache {
+	return defaultCache
+}
+
+// WithInformers backs the clients with informer caches for the reads, once
+// the cache is started, and sets the scheme of the clients. Without
+// informers every read goes to the API server of the remote cluster.
+func (r *Cache) WithInformers(informers bool, scheme *runtime.Scheme) *Cache {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.informers = informers
+	r.scheme = scheme
+	return r
+}
+
+// Start runs the informers of the remote clusters until the context is done,
+// the cache is added to the manager as a runnable
+func (r *Cache) Start(ctx context.Context) error {
+	r.m.Lock()
+	r.ctx = ctx
+	r.m.Unlock()
+
+	<-ctx.Done()
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	for identity, e := range r.clients {
+		e.stop()
+		delete(r.clients, identity)
+	}
+	r.ctx = nil
+	return nil
+}
+
+// Get returns the client of the cluster with the identity, built from the
+// rest config if the cache has no client for the cluster or the credentials
+// of the cluster changed. The client is built without holding the lock of the
+// cache, so a cluster that is slow to answer does not block the gets of the
+// other clusters.
+func (r *Cache) Get(ctx context.Context, identity string, config *rest.Config) (client.Client, error) {
+	hash := configHash(config)
+	r.m.Lock()
+	if e, ok := r.clients[identity]; ok && e.hash == hash {
+		r.m.Unlock()
+		return e.client, nil
+	}
+	informerCtx, scheme := r.ctx, r.scheme
+	if !r.informers {
+		informerCtx = nil
+	}
+	r.m.Unlock()
+
+	c, err, _ := r.builds.Do(identity+"/"+hash, func() (any, error) {
+		e, err := newEntry(ctx, informerCtx, scheme, r.newClient, config)
+		if err != nil {
+			return nil, err
+		}
+		e.hash = hash
+		r.m.Lock()
+		defer r.m.Unlock()
+		if old, ok := r.clients[identity]; ok {
+			if old.hash == hash {
+				// built by a get that completed before this one started
+				e.stop()
+				return old.client, nil
+			}
+			log.FromContext(ctx).Info("cluster credentials changed, rebuilding client", "cluster", identity)
+			old.stop()
+		}
+		r.clients[identity] = e
+		return e.client, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.(client.Client), nil
+}
+
+// Invalidate drops the client of the cluster with the identity
+func (r *Cache) Invalidate(identity string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if e, ok := r.clients[identity]; ok {
+		e.stop()
+		delete(r.clients, identity)
+	}
+}
+
+// Len returns the number of cached clients
+func (r *Cache) Len() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.clients)
+}
+
+// newEntry builds the client of the cluster, backed by informers running
+// until the informer context is done, or reading from the API server if the
+// informer context is nil
+func newEntry(ctx, informerCtx context.Context, scheme *runtime.Scheme, newClient func(config *rest.Config, options client.Options) (client.Client, error), config *rest.Config) (*entry, error) {
+	if informerCtx == nil {
+		c, err := newClient(config, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, err
+		}
+		return &entry{client: c, stop: func() {}}, nil
+	}
+	cl, err := ctrlcluster.New(config, clusterOptions(scheme))
+	if err != nil {
+		return nil, err
+	}
+	informerCtx, stop := context.WithCancel(informerCtx)
+	go func() {
+		if err := cl.Start(informerCtx); err != nil {
+			log.FromContext(ctx).Error(err, "cannot run cluster informers", "host", config.Host)
+		}
+	}()
+	if !cl.GetCache().WaitForCacheSync(ctx) {
+		stop()
+		return nil, ctx.Err()
+	}
+	return &entry{client: cl.GetClient(), stop: stop}, nil
+}
+
+// uncached are the kinds the informer backed clients read from the API
+// server: the reconcilers get the secrets and namespaces before they create or
+// update them, a stale cache would fail the create with AlreadyExists or
+// overwrite a newer secret
+var uncached = []client.Object{&corev1.Secret{}, &corev1.Namespace{}}
+
+func clusterOptions(scheme *runtime.Scheme) ctrlcluster.Option {
+	return func(o *ctrlcluster.Options) {
+		if scheme != nil {
+			o.Scheme = scheme
+		}
+		o.Client.Cache = &client.CacheOptions{DisableFor: uncached}
+	}
+}
+
+// configHash returns the hash of the endpoint and credentials of the rest
+// config, a rotated kubeconfig changes the hash
+func configHash(config *rest.Config) string {
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(config.Host),
+		[]byte(config.APIPath),
+		[]byte(config.Username),
+		[]byte(config.Password),
+		[]byte(config.BearerToken),
+		[]byte(config.BearerTokenFile),
+		[]byte(config.ServerName),
+		config.CertData,
+		[]byte(config.CertFile),
+		config.KeyData,
+		[]byte(config.KeyFile),
+		config.CAData,
+		[]byte(config.CAFile),
+	} {
+		// the length prefix keeps the fields apart
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(field))))
+		h.Write(field)
+	}
+	if config.Insecure {
+		h.Write([]byte{1})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
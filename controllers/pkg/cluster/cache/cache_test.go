@@ -0,0 +1,147 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlcluster "sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	built := 0
+	c := New()
+	c.newClient = func(_ *rest.Config, _ client.Options) (client.Client, error) {
+		built++
+		return fake.NewClientBuilder().Build(), nil
+	}
+	edge01 := &rest.Config{Host: "https://edge01:6443", BearerToken: "t1"}
+	edge02 := &rest.Config{Host: "https://edge02:6443", BearerToken: "t1"}
+
+	// the client of a cluster is built once
+	c1, err := c.Get(ctx, "capi/default/edge01", edge01)
+	require.NoError(t, err)
+	got, err := c.Get(ctx, "capi/default/edge01", rest.CopyConfig(edge01))
+	require.NoError(t, err)
+	require.Same(t, c1, got)
+	_, err = c.Get(ctx, "capi/default/edge02", edge02)
+	require.NoError(t, err)
+	require.Equal(t, 2, built)
+	require.Equal(t, 2, c.Len())
+
+	// a rotated kubeconfig rebuilds the client
+	rotated := rest.CopyConfig(edge01)
+	rotated.BearerToken = "t2"
+	got, err = c.Get(ctx, "capi/default/edge01", rotated)
+	require.NoError(t, err)
+	require.NotSame(t, c1, got)
+	require.Equal(t, 3, built)
+	require.Equal(t, 2, c.Len())
+
+	// an invalidated cluster gets a new client
+	c.Invalidate("capi/default/edge01")
+	require.Equal(t, 1, c.Len())
+	_, err = c.Get(ctx, "capi/default/edge01", rotated)
+	require.NoError(t, err)
+	require.Equal(t, 4, built)
+
+	// stopping the cache drops the clients
+	startCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- c.Start(startCtx) }()
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCacheConcurrentGet(t *testing.T) {
+	ctx := context.Background()
+	var built atomic.Int32
+	release := make(chan struct{})
+	c := New()
+	c.newClient = func(config *rest.Config, _ client.Options) (client.Client, error) {
+		built.Add(1)
+		if config.Host == "https://edge01:6443" {
+			<-release
+		}
+		return fake.NewClientBuilder().Build(), nil
+	}
+	edge01 := &rest.Config{Host: "https://edge01:6443"}
+
+	// the gets of a cluster being built share the build
+	var wg sync.WaitGroup
+	clients := make([]client.Client, 3)
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := c.Get(ctx, "capi/default/edge01", edge01)
+			require.NoError(t, err)
+			clients[i] = got
+		}()
+	}
+	require.Eventually(t, func() bool { return built.Load() == 1 }, time.Second, time.Millisecond)
+
+	// a cluster slow to answer does not block the other clusters
+	_, err := c.Get(ctx, "capi/default/edge02", &rest.Config{Host: "https://edge02:6443"})
+	require.NoError(t, err)
+	require.Equal(t, 1, c.Len())
+	require.Equal(t, int32(2), built.Load())
+
+	close(release)
+	wg.Wait()
+	require.Same(t, clients[0], clients[1])
+	require.Same(t, clients[0], clients[2])
+	require.Equal(t, 2, c.Len())
+}
+
+func TestConfigHash(t *testing.T) {
+	config := &rest.Config{Host: "https://edge01:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}}
+	require.Equal(t, configHash(config), configHash(rest.CopyConfig(config)))
+
+	for _, change := range []func(*rest.Config){
+		func(c *rest.Config) { c.Host = "https://edge02:6443" },
+		func(c *rest.Config) { c.CAData = []byte("ca2") },
+		func(c *rest.Config) { c.CertData = []byte("cert") },
+		func(c *rest.Config) { c.Insecure = true },
+		// the fields are not concatenated
+		func(c *rest.Config) { c.Host = "https://edge01:6443c"; c.CAData = []byte("a") },
+	} {
+		changed := rest.CopyConfig(config)
+		change(changed)
+		require.NotEqual(t, configHash(config), configHash(changed))
+	}
+}
+
+func TestClusterOptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	o := &ctrlcluster.Options{}
+	clusterOptions(scheme)(o)
+	require.Equal(t, scheme, o.Scheme)
+	// the secrets and namespaces are read from the API server
+	require.Contains(t, o.Client.Cache.DisableFor, client.Object(&corev1.Secret{}))
+	require.Contains(t, o.Client.Cache.DisableFor, client.Object(&corev1.Namespace{}))
+}
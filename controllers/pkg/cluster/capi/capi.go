@@ -18,29 +18,42 @@ package capi
 
 import (
 	"context"
-	"encoding/json"
-	"reflect"
 	"strings"
 
-	"github.com/go-logr/logr"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/clientcmd"
-	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
+	// SecretType is the type of the secrets CAPI stores the kubeconfig of the
+	// clusters in
+	SecretType       = "cluster.x-k8s.io/secret"
 	kubeConfigSuffix = "-kubeconfig"
 )
 
 type Capi struct {
 	client.Client
 	Secret *corev1.Secret
-	l      logr.Logger
+}
+
+// New returns the client of the cluster of a CAPI kubeconfig secret, false if
+// the secret is not a CAPI kubeconfig secret
+func New(c client.Client, secret *corev1.Secret) (*Capi, bool) {
+	if string(secret.Type) != SecretType || !strings.Contains(secret.GetName(), "kubeconfig") {
+		return nil, false
+	}
+	return &Capi{Client: c, Secret: secret}, true
+}
+
+// KubeconfigSecretName returns the name of the secret holding the kubeconfig
+// of the CAPI cluster
+func KubeconfigSecretName(clusterName string) string {
+	return clusterName + kubeConfigSuffix
 }
 
 func (r *Capi) GetClusterName() string {
@@ -50,56 +63,25 @@ func (r *Capi) GetClusterName() string {
 	return strings.TrimSuffix(r.Secret.GetName(), kubeConfigSuffix)
 }
 
+// GetClusterClient returns the client of the CAPI cluster, shared through the
+// client cache. The cached client is dropped when the cluster does not meet
+// its readiness criteria.
 func (r *Capi) GetClusterClient(ctx context.Context) (resource.APIPatchingApplicator, bool, error) {
-	if !r.isCapiClusterReady(ctx) {
+	identity := kubeconfig.ClusterIdentity("capi", r.Secret.GetNamespace(), r.GetClusterName())
+	if readiness := r.CheckReadiness(ctx); !readiness.Ready {
+		log.FromContext(ctx).Info("cluster not ready", "cluster", r.GetClusterName(), "reason", readiness.Reason, "message", readiness.Message)
+		cache.Default().Invalidate(identity)
 		return resource.APIPatchingApplicator{}, false, nil
 	}
-	return getCapiClusterClient(r.Secret)
-}
-
-func (r *Capi) isCapiClusterReady(ctx context.Context) bool {
-	r.l = log.FromContext(ctx)
-	name := r.GetClusterName()
-
-	cl := resource.GetUnstructuredFromGVK(&schema.GroupVersionKind{Group: capiv1beta1.GroupVersion.Group, Version: capiv1beta1.GroupVersion.Version, Kind: reflect.TypeFor[capiv1beta1.Cluster]().Name()})
-	if err := r.Get(ctx, types.NamespacedName{Namespace: r.Secret.GetNamespace(), Name: name}, cl); err != nil {
-		r.l.Error(err, "cannot get cluster")
-		return false
-	}
-	b, err := json.Marshal(cl)
-	if err != nil {
-		r.l.Error(err, "cannot marshal cluster")
-		return false
-	}
-	cluster := &capiv1beta1.Cluster{}
-	if err := json.Unmarshal(b, cluster); err != nil {
-		r.l.Error(err, "cannot unmarshal cluster")
-		return false
-	}
-	return isReady(cluster.GetConditions())
+	return getCapiClusterClient(ctx, identity, r.Secret)
 }
 
-func isReady(cs capiv1beta1.Conditions) bool {
-	for _, c := range cs {
-		if c.Type == capiv1beta1.ReadyCondition {
-			if c.Status == corev1.ConditionTrue {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func getCapiClusterClient(secret *corev1.Secret) (resource.APIPatchingApplicator, bool, error) {
+func getCapiClusterClient(ctx context.Context, identity string, secret *corev1.Secret) (resource.APIPatchingApplicator, bool, error) {
 	//provide a rest config from the secret value
 	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data["value"])
 	if err != nil {
 		return resource.APIPatchingApplicator{}, false, err
 	}
 	// build a cluster client from the kube rest config
-	clClient, err := client.New(config, client.Options{})
-	if err != nil {
-		return resource.APIPatchingApplicator{}, false, err
-	}
-	return resource.NewAPIPatchingApplicator(clClient), true, nil
+	return kubeconfig.NewClusterClient(ctx, identity, config)
 }
@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReadinessCriteriaAnnotation overrides the readiness criteria of a CAPI
+	// cluster, in the format of ParseReadinessCriteria
+	ReadinessCriteriaAnnotation = "nephio.org/readiness-criteria"
+
+	// the criteria of the readiness of a CAPI cluster
+	CriterionReady               = "Ready"
+	CriterionControlPlaneReady   = "ControlPlaneReady"
+	CriterionInfrastructureReady = "InfrastructureReady"
+	CriterionAPIServerReachable  = "APIServerReachable"
+	CriterionMinReadyReplicas    = "MinReadyReplicas"
+)
+
+const (
+	// the reasons of the readiness of a cluster
+	ReasonReady                  = "Ready"
+	ReasonNotReady               = "NotReady"
+	ReasonClusterNotFound        = "ClusterNotFound"
+	ReasonClusterUnknown         = "ClusterUnknown"
+	ReasonInvalidCriteria        = "InvalidCriteria"
+	ReasonControlPlaneNotReady   = "ControlPlaneNotReady"
+	ReasonInfrastructureNotReady = "InfrastructureNotReady"
+	ReasonNotEnoughReadyReplicas = "NotEnoughReadyReplicas"
+	ReasonAPIServerUnreachable   = "APIServerUnreachable"
+)
+
+// the CAPI v1beta2 conditions replacing the v1beta1 conditions of the
+// criteria, reported under status.v1beta2.conditions of the v1beta1 API the
+// clusters are read with
+const (
+	v1beta2AvailableCondition             = "Available"
+	v1beta2ControlPlaneAvailableCondition = "ControlPlaneAvailable"
+	v1beta2InfrastructureReadyCondition   = "InfrastructureReady"
+)
+
+// Readiness is the readiness of a cluster, with the reason it is not ready
+type Readiness struct {
+	Ready bool
+	// Reason is a CamelCase reason, e.g. ControlPlaneNotReady
+	Reason string
+	// Message explains the reason
+	Message string
+}
+
+func notReady(reason, format string, a ...any) Readiness {
+	return Readiness{Reason: reason, Message: fmt.Sprintf(format, a...)}
+}
+
+// ReadinessCriteria are the criteria a CAPI cluster must meet to be ready
+type ReadinessCriteria struct {
+	// Ready requires the Ready condition, Available in v1beta2
+	Ready bool
+	// ControlPlaneReady requires the ControlPlaneReady condition,
+	// ControlPlaneAvailable in v1beta2
+	ControlPlaneReady bool
+	// InfrastructureReady requires the InfrastructureReady condition
+	InfrastructureReady bool
+	// APIServerReachable requires the API server of the cluster to answer
+	// /readyz
+	APIServerReachable bool
+	// MinReadyReplicas is the minimum number of ready replicas of the
+	// MachineDeployments of the cluster
+	MinReadyReplicas int32
+}
+
+var (
+	m               sync.RWMutex
+	defaultCriteria = ReadinessCriteria{Ready: true}
+)
+
+// DefaultReadinessCriteria returns the readiness criteria of the CAPI clusters
+// without the ReadinessCriteriaAnnotation, the Ready condition unless set
+// otherwise
+func DefaultReadinessCriteria() ReadinessCriteria {
+	m.RLock()
+	defer m.RUnlock()
+	return defaultCriteria
+}
+
+// SetDefaultReadinessCriteria sets the readiness criteria of the CAPI clusters
+// without the ReadinessCriteriaAnnotation
+func SetDefaultReadinessCriteria(criteria ReadinessCriteria) {
+	m.Lock()
+	defer m.Unlock()
+	defaultCriteria = criteria
+}
+
+// ParseReadinessCriteria parses a comma-separated list of criteria, e.g.
+// "Ready,ControlPlaneReady,APIServerReachable,MinReadyReplicas=2". The empty
+// list is no criteria.
+func ParseReadinessCriteria(s string) (ReadinessCriteria, error) {
+	criteria := ReadinessCriteria{}
+	for _, criterion := range strings.Split(s, ",") {
+		criterion = strings.TrimSpace(criterion)
+		name, value, hasValue := strings.Cut(criterion, "=")
+		if hasValue != (name == CriterionMinReadyReplicas) {
+			return ReadinessCriteria{}, fmt.Errorf("invalid readiness criterion %q", criterion)
+		}
+		switch name {
+		case "":
+		case CriterionReady:
+			criteria.Ready = true
+		case CriterionControlPlaneReady:
+			criteria.ControlPlaneReady = true
+		case CriterionInfrastructureReady:
+			criteria.InfrastructureReady = true
+		case CriterionAPIServerReachable:
+			criteria.APIServerReachable = true
+		case CriterionMinReadyReplicas:
+			replicas, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || replicas < 0 {
+				return ReadinessCriteria{}, fmt.Errorf("invalid readiness criterion %q", criterion)
+			}
+			criteria.MinReadyReplicas = int32(replicas)
+		default:
+			return ReadinessCriteria{}, fmt.Errorf("unknown readiness criterion %q", criterion)
+		}
+	}
+	return criteria, nil
+}
+
+func (r ReadinessCriteria) String() string {
+	criteria := []string{}
+	for _, c := range []struct {
+		name string
+		set  bool
+	}{
+		{CriterionReady, r.Ready},
+		{CriterionControlPlaneReady, r.ControlPlaneReady},
+		{CriterionInfrastructureReady, r.InfrastructureReady},
+		{CriterionAPIServerReachable, r.APIServerReachable},
+	} {
+		if c.set {
+			criteria = append(criteria, c.name)
+		}
+	}
+	if r.MinReadyReplicas > 0 {
+		criteria = append(criteria, fmt.Sprintf("%s=%d", CriterionMinReadyReplicas, r.MinReadyReplicas))
+	}
+	return strings.Join(criteria, ",")
+}
+
+// CheckReadiness evaluates the CAPI cluster of the secret against its
+// readiness criteria
+func (r *Capi) CheckReadiness(ctx context.Context) Readiness {
+	name := r.GetClusterName()
+	cl := resource.GetUnstructuredFromGVK(ptr.To(capiv1beta1.GroupVersion.WithKind("Cluster")))
+	if err := r.Get(ctx, types.NamespacedName{Namespace: r.Secret.GetNamespace(), Name: name}, cl); err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			return notReady(ReasonClusterNotFound, "cluster %s not found", name)
+		}
+		return notReady(ReasonClusterUnknown, "cannot get cluster %s: %s", name, err)
+	}
+	criteria, err := clusterCriteria(cl)
+	if err != nil {
+		return notReady(ReasonInvalidCriteria, "invalid %s annotation: %s", ReadinessCriteriaAnnotation, err)
+	}
+
+	if readiness := evaluateConditions(cl, criteria); !readiness.Ready {
+		return readiness
+	}
+	if criteria.MinReadyReplicas > 0 {
+		replicas, err := r.readyReplicas(ctx, cl)
+		if err != nil {
+			return notReady(ReasonClusterUnknown, "cannot get machine deployments: %s", err)
+		}
+		if replicas < int64(criteria.MinReadyReplicas) {
+			return notReady(ReasonNotEnoughReadyReplicas, "%d ready replicas, %d required", replicas, criteria.MinReadyReplicas)
+		}
+	}
+	if criteria.APIServerReachable {
+		config, err := clientcmd.RESTConfigFromKubeConfig(r.Secret.Data["value"])
+		if err != nil {
+			return notReady(ReasonClusterUnknown, "invalid kubeconfig: %s", err)
+		}
+		if !kubeconfig.IsReachable(ctx, config) {
+			return notReady(ReasonAPIServerUnreachable, "API server %s not ready", config.Host)
+		}
+	}
+	return Readiness{Ready: true, Reason: ReasonReady, Message: "cluster ready"}
+}
+
+// IsClusterReady returns true if the CAPI cluster meets the conditions of its
+// readiness criteria. MinReadyReplicas and APIServerReachable need other
+// objects than the cluster and are not evaluated, CheckReadiness evaluates
+// all the criteria.
+func IsClusterReady(cluster *capiv1beta1.Cluster) bool {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cluster)
+	if err != nil {
+		return false
+	}
+	cl := &unstructured.Unstructured{Object: obj}
+	criteria, err := clusterCriteria(cl)
+	if err != nil {
+		return false
+	}
+	return evaluateConditions(cl, criteria).Ready
+}
+
+// clusterCriteria returns the readiness criteria of the cluster, the default
+// criteria unless the cluster overrides them
+func clusterCriteria(cl client.Object) (ReadinessCriteria, error) {
+	if value, ok := cl.GetAnnotations()[ReadinessCriteriaAnnotation]; ok {
+		return ParseReadinessCriteria(value)
+	}
+	return DefaultReadinessCriteria(), nil
+}
+
+// readyReplicas returns the ready replicas of the MachineDeployments of the
+// cluster
+func (r *Capi) readyReplicas(ctx context.Context, cl *unstructured.Unstructured) (int64, error) {
+	mds := &unstructured.UnstructuredList{}
+	mds.SetGroupVersionKind(capiv1beta1.GroupVersion.WithKind("MachineDeploymentList"))
+	if err := r.List(ctx, mds, client.InNamespace(cl.GetNamespace()), client.MatchingLabels{capiv1beta1.ClusterNameLabel: cl.GetName()}); err != nil {
+		return 0, err
+	}
+	var replicas int64
+	for _, md := range mds.Items {
+		ready, _, _ := unstructured.NestedInt64(md.Object, "status", "readyReplicas")
+		replicas += ready
+	}
+	return replicas, nil
+}
+
+// evaluateConditions evaluates the conditions of the cluster against the
+// criteria. The v1beta2 conditions are preferred when the cluster reports
+// them.
+func evaluateConditions(cl *unstructured.Unstructured, criteria ReadinessCriteria) Readiness {
+	for _, c := range []struct {
+		required bool
+		v1beta1  string
+		v1beta2  string
+		field    string
+		reason   string
+	}{
+		{criteria.Ready, string(capiv1beta1.ReadyCondition), v1beta2AvailableCondition, "", ReasonNotReady},
+		{criteria.ControlPlaneReady, string(capiv1beta1.ControlPlaneReadyCondition), v1beta2ControlPlaneAvailableCondition, "controlPlaneReady", ReasonControlPlaneNotReady},
+		{criteria.InfrastructureReady, string(capiv1beta1.InfrastructureReadyCondition), v1beta2InfrastructureReadyCondition, "infrastructureReady", ReasonInfrastructureNotReady},
+	} {
+		if !c.required {
+			continue
+		}
+		condition, ok := getCondition(cl, c.v1beta1, c.v1beta2)
+		if !ok {
+			// the clusters without a control plane or infrastructure
+			// reference only report the status fields
+			if ready, found, _ := unstructured.NestedBool(cl.Object, "status", c.field); c.field != "" && found {
+				if !ready {
+					return notReady(c.reason, "%s is false", c.field)
+				}
+				continue
+			}
+			return notReady(c.reason, "condition %s not reported", c.v1beta1)
+		}
+		if condition.Status != metav1.ConditionTrue {
+			msg := fmt.Sprintf("condition %s is %s", condition.Type, condition.Status)
+			if condition.Message != "" {
+				msg = fmt.Sprintf("%s: %s", msg, condition.Message)
+			}
+			return notReady(c.reason, "%s", msg)
+		}
+	}
+	return Readiness{Ready: true, Reason: ReasonReady}
+}
+
+// getCondition returns the condition of the cluster: the v1beta2 condition of
+// a cluster reporting v1beta2 conditions, the v1beta1 condition otherwise
+func getCondition(cl *unstructured.Unstructured, v1beta1Type, v1beta2Type string) (metav1.Condition, bool) {
+	path, conditionType := []string{"status", "conditions"}, v1beta1Type
+	if _, ok, _ := unstructured.NestedSlice(cl.Object, "status", "v1beta2", "conditions"); ok {
+		path, conditionType = []string{"status", "v1beta2", "conditions"}, v1beta2Type
+	}
+	conditions, _, _ := unstructured.NestedSlice(cl.Object, path...)
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		message, _ := condition["message"].(string)
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionStatus(status), Message: message}, true
+	}
+	return metav1.Condition{}, false
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func condition(conditionType, status string) any {
+	return map[string]any{"type": conditionType, "status": status, "message": conditionType + " is " + status}
+}
+
+// newCluster returns a CAPI cluster with the status, as unstructured so the
+// v1beta2 conditions are kept
+func newCluster(apiVersion string, annotations map[string]string, status map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{"status": status}}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind("Cluster")
+	u.SetNamespace("default")
+	u.SetName("edge01")
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func newMachineDeployment(name string, readyReplicas int64) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"readyReplicas": readyReplicas}}}
+	u.SetAPIVersion("cluster.x-k8s.io/v1beta1")
+	u.SetKind("MachineDeployment")
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetLabels(map[string]string{"cluster.x-k8s.io/cluster-name": "edge01"})
+	return u
+}
+
+func TestParseReadinessCriteria(t *testing.T) {
+	cases := map[string]struct {
+		value   string
+		want    ReadinessCriteria
+		wantErr bool
+	}{
+		"Empty":    {value: "", want: ReadinessCriteria{}},
+		"Ready":    {value: "Ready", want: ReadinessCriteria{Ready: true}},
+		"All":      {value: "Ready, ControlPlaneReady,InfrastructureReady,APIServerReachable,MinReadyReplicas=2", want: ReadinessCriteria{Ready: true, ControlPlaneReady: true, InfrastructureReady: true, APIServerReachable: true, MinReadyReplicas: 2}},
+		"Unknown":  {value: "Ready,Healthy", wantErr: true},
+		"NoValue":  {value: "MinReadyReplicas", wantErr: true},
+		"Negative": {value: "MinReadyReplicas=-1", wantErr: true},
+		"Value":    {value: "Ready=true", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseReadinessCriteria(tc.value)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			// the criteria survive their string format
+			parsed, err := ParseReadinessCriteria(got.String())
+			require.NoError(t, err)
+			require.Equal(t, got, parsed)
+		})
+	}
+}
+
+func TestEvaluateConditions(t *testing.T) {
+	all := ReadinessCriteria{Ready: true, ControlPlaneReady: true, InfrastructureReady: true}
+	cases := map[string]struct {
+		cluster    *unstructured.Unstructured
+		criteria   ReadinessCriteria
+		wantReason string
+	}{
+		"Ready": {
+			cluster: newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{"conditions": []any{
+				condition("Ready", "True"), condition("ControlPlaneReady", "True"), condition("InfrastructureReady", "True"),
+			}}),
+			criteria:   all,
+			wantReason: ReasonReady,
+		},
+		"NotReady": {
+			cluster:    newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{"conditions": []any{condition("Ready", "False")}}),
+			criteria:   all,
+			wantReason: ReasonNotReady,
+		},
+		"ControlPlaneNotReady": {
+			cluster: newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{"conditions": []any{
+				condition("Ready", "True"), condition("ControlPlaneReady", "False"), condition("InfrastructureReady", "True"),
+			}}),
+			criteria:   all,
+			wantReason: ReasonControlPlaneNotReady,
+		},
+		"ControlPlaneNotRequired": {
+			cluster: newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{"conditions": []any{
+				condition("Ready", "True"), condition("ControlPlaneReady", "False"),
+			}}),
+			criteria:   ReadinessCriteria{Ready: true},
+			wantReason: ReasonReady,
+		},
+		"InfrastructureStatusField": {
+			cluster: newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{
+				"conditions":          []any{condition("Ready", "True"), condition("ControlPlaneReady", "True")},
+				"infrastructureReady": false,
+			}),
+			criteria:   all,
+			wantReason: ReasonInfrastructureNotReady,
+		},
+		"ConditionNotReported": {
+			cluster:    newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{}),
+			criteria:   all,
+			wantReason: ReasonNotReady,
+		},
+		// the v1beta2 conditions take precedence over the v1beta1 conditions
+		"V1beta2Conditions": {
+			cluster: newCluster("cluster.x-k8s.io/v1beta1", nil, map[string]any{
+				"conditions": []any{condition("Ready", "True"), condition("ControlPlaneReady", "True"), condition("InfrastructureReady", "True")},
+				"v1beta2": map[string]any{"conditions": []any{
+					condition("Available", "True"), condition("ControlPlaneAvailable", "False"), condition("InfrastructureReady", "True"),
+				}},
+			}),
+			criteria:   all,
+			wantReason: ReasonControlPlaneNotReady,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := evaluateConditions(tc.cluster, tc.criteria)
+			require.Equal(t, tc.wantReason, got.Reason, got.Message)
+			require.Equal(t, tc.wantReason == ReasonReady, got.Ready)
+		})
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	ready := map[string]any{"conditions": []any{condition("Ready", "True")}}
+	// the API server of the kubeconfig does not answer
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge01-kubeconfig"},
+		Type:       SecretType,
+		Data: map[string][]byte{"value": []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: edge01
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: edge01
+  context:
+    cluster: edge01
+current-context: edge01
+`)},
+	}
+	cases := map[string]struct {
+		objects    []client.Object
+		criteria   ReadinessCriteria
+		wantReason string
+	}{
+		"NotFound": {
+			criteria:   ReadinessCriteria{Ready: true},
+			wantReason: ReasonClusterNotFound,
+		},
+		"Ready": {
+			objects:    []client.Object{newCluster("cluster.x-k8s.io/v1beta1", nil, ready)},
+			criteria:   ReadinessCriteria{Ready: true},
+			wantReason: ReasonReady,
+		},
+		"ReadyReplicas": {
+			objects: []client.Object{
+				newCluster("cluster.x-k8s.io/v1beta1", nil, ready),
+				newMachineDeployment("md-0", 1),
+				newMachineDeployment("md-1", 2),
+			},
+			criteria:   ReadinessCriteria{Ready: true, MinReadyReplicas: 3},
+			wantReason: ReasonReady,
+		},
+		"NotEnoughReadyReplicas": {
+			objects: []client.Object{
+				newCluster("cluster.x-k8s.io/v1beta1", nil, ready),
+				newMachineDeployment("md-0", 1),
+			},
+			criteria:   ReadinessCriteria{Ready: true, MinReadyReplicas: 3},
+			wantReason: ReasonNotEnoughReadyReplicas,
+		},
+		"APIServerUnreachable": {
+			objects:    []client.Object{newCluster("cluster.x-k8s.io/v1beta1", nil, ready)},
+			criteria:   ReadinessCriteria{Ready: true, APIServerReachable: true},
+			wantReason: ReasonAPIServerUnreachable,
+		},
+		// the annotation of the cluster overrides the default criteria
+		"Annotation": {
+			objects:    []client.Object{newCluster("cluster.x-k8s.io/v1beta1", map[string]string{ReadinessCriteriaAnnotation: "MinReadyReplicas=1"}, ready)},
+			criteria:   ReadinessCriteria{Ready: true},
+			wantReason: ReasonNotEnoughReadyReplicas,
+		},
+		"InvalidAnnotation": {
+			objects:    []client.Object{newCluster("cluster.x-k8s.io/v1beta1", map[string]string{ReadinessCriteriaAnnotation: "Healthy"}, ready)},
+			criteria:   ReadinessCriteria{Ready: true},
+			wantReason: ReasonInvalidCriteria,
+		},
+	}
+	defer SetDefaultReadinessCriteria(DefaultReadinessCriteria())
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetDefaultReadinessCriteria(tc.criteria)
+			// the CAPI types are not registered, the unstructured clusters
+			// keep all their fields
+			c := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(tc.objects...).Build()
+			got := (&Capi{Client: c, Secret: secret}).CheckReadiness(context.Background())
+			require.Equal(t, tc.wantReason, got.Reason, got.Message)
+			require.Equal(t, tc.wantReason == ReasonReady, got.Ready)
+		})
+	}
+}
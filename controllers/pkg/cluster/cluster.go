@@ -18,7 +18,6 @@ package cluster
 
 import (
 	"context"
-	"strings"
 
 	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
@@ -30,17 +29,55 @@ type Cluster struct {
 	client.Client
 }
 
+// GetClusterClient returns the client of the cluster whose credentials are
+// stored in the secret, from the first registered provider that recognizes
+// the secret. It returns false if no provider recognizes the secret.
 func (r Cluster) GetClusterClient(secret *corev1.Secret) (ClusterClient, bool) {
-	switch string(secret.Type) {
-	case "cluster.x-k8s.io/secret":
-		if strings.Contains(secret.GetName(), "kubeconfig") {
-			return &capi.Capi{Client: r.Client, Secret: secret}, true
+	_, clusterClient, ok := r.getProviderClient(secret)
+	return clusterClient, ok
+}
+
+// getProviderClient returns the index of the provider recognizing the secret
+// and the client of the cluster
+func (r Cluster) getProviderClient(secret *corev1.Secret) (int, ClusterClient, bool) {
+	for i, p := range providers {
+		if clusterClient, ok := p.provider(r.Client, secret); ok {
+			return i, clusterClient, true
 		}
 	}
-	return nil, false
+	return 0, nil, false
 }
 
+// ClusterClient is the client of a remote cluster. GetClusterClient returns
+// false when the cluster is not ready, according to the readiness check of
+// the provider of the cluster.
 type ClusterClient interface {
 	GetClusterClient(context.Context) (resource.APIPatchingApplicator, bool, error)
 	GetClusterName() string
 }
+
+// Readiness is the readiness of a cluster, with the reason it is not ready
+type Readiness = capi.Readiness
+
+// ReadinessChecker is implemented by the cluster clients that report why
+// their cluster is not ready
+type ReadinessChecker interface {
+	CheckReadiness(context.Context) Readiness
+}
+
+// GetReadiness returns the readiness of the cluster of the client. The
+// clients that do not implement ReadinessChecker only report whether the
+// cluster is ready.
+func GetReadiness(ctx context.Context, clusterClient ClusterClient) Readiness {
+	if checker, ok := clusterClient.(ReadinessChecker); ok {
+		return checker.CheckReadiness(ctx)
+	}
+	_, ready, err := clusterClient.GetClusterClient(ctx)
+	switch {
+	case err != nil:
+		return Readiness{Reason: capi.ReasonClusterUnknown, Message: err.Error()}
+	case !ready:
+		return Readiness{Reason: capi.ReasonNotReady, Message: "cluster not ready"}
+	}
+	return Readiness{Ready: true, Reason: capi.ReasonReady, Message: "cluster ready"}
+}
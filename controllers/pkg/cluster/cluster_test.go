@@ -20,8 +20,11 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestGetClusterClient(t *testing.T) {
@@ -42,16 +45,97 @@ func TestGetClusterClient(t *testing.T) {
 			},
 			want: true,
 		},
+		"CapiNotKubeconfig": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "a-ca",
+				},
+				Type: corev1.SecretType("cluster.x-k8s.io/secret"),
+			},
+			want: false,
+		},
+		"Kubeconfig": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "a-kubeconfig",
+				},
+				Type: corev1.SecretType("nephio.org/kubeconfig"),
+			},
+			want: true,
+		},
+		"ArgoCD": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster-a",
+					Labels: map[string]string{"argocd.argoproj.io/secret-type": "cluster"},
+				},
+				Data: map[string][]byte{"name": []byte("a")},
+			},
+			want: true,
+		},
+		"OCM": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "a",
+					Name:      "nephio",
+					Labels:    map[string]string{"authentication.open-cluster-management.io/is-managed-serviceaccount": "true"},
+				},
+			},
+			want: true,
+		},
+		"Focom": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "a-kubeconfig",
+					Labels: map[string]string{"focom.nephio.org/provisioning-request": "a"},
+				},
+			},
+			want: true,
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			c := Cluster{}
-			_, got := c.GetClusterClient(tc.secret)
+			clusterClient, got := c.GetClusterClient(tc.secret)
 
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("-want, +got:\n%s", diff)
 			}
+			if got && clusterClient.GetClusterName() != "a" {
+				t.Errorf("want cluster a, got: %s", clusterClient.GetClusterName())
+			}
 		})
 	}
 }
+
+func TestRegisterProvider(t *testing.T) {
+	registered := providers
+	defer func() { providers = registered }()
+	providers = append([]namedProvider{}, registered...)
+
+	require.Equal(t, []string{"capi", "kubeconfig", "argocd", "ocm", "focom"}, ProviderNames())
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Type: corev1.SecretType("example.com/cluster")}
+	_, ok := Cluster{}.GetClusterClient(secret)
+	require.False(t, ok)
+
+	RegisterProvider("example", func(_ client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		if secret.Type != "example.com/cluster" {
+			return nil, false
+		}
+		return &kubeconfig.Kubeconfig{Secret: secret}, true
+	})
+	clusterClient, ok := Cluster{}.GetClusterClient(secret)
+	require.True(t, ok)
+	require.Equal(t, "a", clusterClient.GetClusterName())
+
+	// the provider registered with the same name is replaced
+	RegisterProvider("capi", func(_ client.Client, _ *corev1.Secret) (ClusterClient, bool) { return nil, false })
+	require.Equal(t, []string{"capi", "kubeconfig", "argocd", "ocm", "focom", "example"}, ProviderNames())
+	_, ok = Cluster{}.GetClusterClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-kubeconfig"},
+		Type:       corev1.SecretType("cluster.x-k8s.io/secret"),
+	})
+	require.False(t, ok)
+}
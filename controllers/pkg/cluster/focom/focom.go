@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package focom provides the clients of the clusters provisioned on O-Clouds
+// through FOCOM provisioning requests.
+//
+// The focom-operator only reports the phase of the requests, it does not
+// fetch the kubeconfig of the provisioned clusters. The secret labelled with
+// the name of the request is expected to be created, in the namespace of the
+// request, by the operator of the O-Cloud or the automation that retrieves
+// the kubeconfig from the O-Cloud once the request is fulfilled.
+package focom
+
+import (
+	"context"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ProvisioningRequestLabel labels the secrets holding the kubeconfig of a
+	// cluster with the name of the FocomProvisioningRequest, in the namespace
+	// of the request, that provisioned the cluster
+	ProvisioningRequestLabel = "focom.nephio.org/provisioning-request"
+	// ProvisioningPhaseFulfilled is the phase of the requests whose cluster is
+	// provisioned
+	ProvisioningPhaseFulfilled = "Fulfilled"
+
+	kubeconfigKey = "kubeconfig"
+)
+
+// ProvisioningRequestGroupVersionKind is the kind of the FOCOM provisioning
+// requests
+var ProvisioningRequestGroupVersionKind = schema.GroupVersionKind{Group: "focom.nephio.org", Version: "v1alpha1", Kind: "FocomProvisioningRequest"}
+
+type Focom struct {
+	client.Client
+	Secret *corev1.Secret
+}
+
+// New returns the client of the cluster of a FOCOM provisioning request
+// secret, false if the secret is not the kubeconfig of a provisioning request
+func New(c client.Client, secret *corev1.Secret) (*Focom, bool) {
+	if secret.GetLabels()[ProvisioningRequestLabel] == "" {
+		return nil, false
+	}
+	return &Focom{Client: c, Secret: secret}, true
+}
+
+// GetClusterName returns the name of the cluster, the name of the
+// provisioning request unless the secret sets the nephio.org/cluster-name
+// annotation
+func (r *Focom) GetClusterName() string {
+	if r.Secret == nil {
+		return ""
+	}
+	if name, ok := r.Secret.GetAnnotations()[kubeconfig.ClusterNameAnnotation]; ok {
+		return name
+	}
+	return r.Secret.GetLabels()[ProvisioningRequestLabel]
+}
+
+// GetClusterClient returns the client of the cluster, the cluster is ready
+// when the provisioning request is fulfilled and its API server is ready
+func (r *Focom) GetClusterClient(ctx context.Context) (resource.APIPatchingApplicator, bool, error) {
+	identity := kubeconfig.ClusterIdentity("focom", r.Secret.GetNamespace(), r.GetClusterName())
+	pr := &unstructured.Unstructured{}
+	pr.SetGroupVersionKind(ProvisioningRequestGroupVersionKind)
+	key := types.NamespacedName{Namespace: r.Secret.GetNamespace(), Name: r.Secret.GetLabels()[ProvisioningRequestLabel]}
+	if err := r.Get(ctx, key, pr); err != nil {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, client.IgnoreNotFound(err)
+	}
+	phase, _, _ := unstructured.NestedString(pr.Object, "status", "phase")
+	if phase != ProvisioningPhaseFulfilled {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, nil
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(r.Secret.Data[kubeconfigKey])
+	if err != nil {
+		return resource.APIPatchingApplicator{}, false, err
+	}
+	if !kubeconfig.IsReachable(ctx, config) {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, nil
+	}
+	return kubeconfig.NewClusterClient(ctx, identity, config)
+}
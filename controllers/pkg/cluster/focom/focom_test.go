@@ -0,0 +1,86 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package focom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetClusterClient(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	pr := &unstructured.Unstructured{Object: map[string]any{}}
+	pr.SetGroupVersionKind(ProvisioningRequestGroupVersionKind)
+	pr.SetNamespace("focom")
+	pr.SetName("edge01")
+	c := fake.NewClientBuilder().WithObjects(pr).Build()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "focom",
+			Name:      "edge01-kubeconfig",
+			Labels:    map[string]string{ProvisioningRequestLabel: "edge01"},
+		},
+		Data: map[string][]byte{"kubeconfig": []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: edge01
+  cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+contexts:
+- name: edge01
+  context:
+    cluster: edge01
+current-context: edge01
+`, server.URL))},
+	}
+	f, ok := New(c, secret)
+	require.True(t, ok)
+	require.Equal(t, "edge01", f.GetClusterName())
+
+	// the cluster is not ready until the provisioning request is fulfilled
+	_, ok, err := f.GetClusterClient(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, unstructured.SetNestedField(pr.Object, "provisioning", "status", "phase"))
+	require.NoError(t, c.Update(ctx, pr))
+	_, ok, err = f.GetClusterClient(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, unstructured.SetNestedField(pr.Object, ProvisioningPhaseFulfilled, "status", "phase"))
+	require.NoError(t, c.Update(ctx, pr))
+	_, ok, err = f.GetClusterClient(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok = New(c, &corev1.Secret{})
+	require.False(t, ok)
+}
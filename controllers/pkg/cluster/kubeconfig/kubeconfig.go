@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubeconfig provides the clients of the clusters whose kubeconfig is
+// stored in a plain secret, and the helpers the other cluster providers use
+// to build the client of a cluster and to probe its API server.
+package kubeconfig
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// SecretType is the type of the secrets holding the kubeconfig of a
+	// cluster under the kubeconfigKey
+	SecretType = "nephio.org/kubeconfig"
+	// ClusterNameAnnotation overrides the name of the cluster, which defaults
+	// to the name of the secret without the -kubeconfig suffix
+	ClusterNameAnnotation = "nephio.org/cluster-name"
+
+	kubeconfigKey    = "kubeconfig"
+	kubeConfigSuffix = "-kubeconfig"
+	probeTimeout     = 5 * time.Second
+)
+
+type Kubeconfig struct {
+	Secret *corev1.Secret
+}
+
+// New returns the client of the cluster of a plain kubeconfig secret, false
+// if the secret is not a kubeconfig secret
+func New(secret *corev1.Secret) (*Kubeconfig, bool) {
+	if string(secret.Type) != SecretType {
+		return nil, false
+	}
+	return &Kubeconfig{Secret: secret}, true
+}
+
+func (r *Kubeconfig) GetClusterName() string {
+	if r.Secret == nil {
+		return ""
+	}
+	if name, ok := r.Secret.GetAnnotations()[ClusterNameAnnotation]; ok {
+		return name
+	}
+	return strings.TrimSuffix(r.Secret.GetName(), kubeConfigSuffix)
+}
+
+// GetClusterClient returns the client of the cluster, the cluster is ready
+// when its API server is ready
+func (r *Kubeconfig) GetClusterClient(ctx context.Context) (resource.APIPatchingApplicator, bool, error) {
+	identity := ClusterIdentity("kubeconfig", r.Secret.GetNamespace(), r.GetClusterName())
+	config, err := clientcmd.RESTConfigFromKubeConfig(r.Secret.Data[kubeconfigKey])
+	if err != nil {
+		return resource.APIPatchingApplicator{}, false, err
+	}
+	if !IsReachable(ctx, config) {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, nil
+	}
+	return NewClusterClient(ctx, identity, config)
+}
+
+// ClusterIdentity returns the identity of a cluster in the client cache
+func ClusterIdentity(provider, namespace, name string) string {
+	return provider + "/" + namespace + "/" + name
+}
+
+// NewClusterClient returns the client of the cluster of the rest config,
+// shared with the other reconcilers through the client cache
+func NewClusterClient(ctx context.Context, identity string, config *rest.Config) (resource.APIPatchingApplicator, bool, error) {
+	clClient, err := cache.Default().Get(ctx, identity, config)
+	if err != nil {
+		return resource.APIPatchingApplicator{}, false, err
+	}
+	return resource.NewAPIPatchingApplicator(clClient), true, nil
+}
+
+// IsReachable returns true if the API server of the cluster reports it is
+// ready on its /readyz endpoint
+func IsReachable(ctx context.Context, config *rest.Config) bool {
+	config = rest.CopyConfig(config)
+	config.Timeout = probeTimeout
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "cannot build http client", "host", config.Host)
+		return false
+	}
+	restClient, err := rest.UnversionedRESTClientForConfigAndClient(config, httpClient)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "cannot build rest client", "host", config.Host)
+		return false
+	}
+	if err := restClient.Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+		log.FromContext(ctx).Info("cluster API server not ready", "host", config.Host, "error", err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newKubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: a
+  cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+contexts:
+- name: a
+  context:
+    cluster: a
+    user: a
+current-context: a
+users:
+- name: a
+  user:
+    token: t
+`, server))
+}
+
+func TestGetClusterClient(t *testing.T) {
+	ready := true
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" || r.Header.Get("Authorization") != "Bearer t" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !ready {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge01-kubeconfig"},
+		Type:       corev1.SecretType(SecretType),
+		Data:       map[string][]byte{"kubeconfig": newKubeconfig(server.URL)},
+	}
+	kc, ok := New(secret)
+	require.True(t, ok)
+	require.Equal(t, "edge01", kc.GetClusterName())
+
+	_, ok, err := kc.GetClusterClient(context.Background())
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ready = false
+	_, ok, err = kc.GetClusterClient(context.Background())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	secret.Annotations = map[string]string{ClusterNameAnnotation: "edge02"}
+	require.Equal(t, "edge02", kc.GetClusterName())
+
+	_, ok = New(&corev1.Secret{Type: corev1.SecretTypeOpaque})
+	require.False(t, ok)
+
+	secret.Data = map[string][]byte{"kubeconfig": []byte("invalid")}
+	_, _, err = kc.GetClusterClient(context.Background())
+	require.Error(t, err)
+}
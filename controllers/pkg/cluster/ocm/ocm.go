@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ocm provides the clients of the Open Cluster Management managed
+// clusters, authenticated with the token of a ManagedServiceAccount.
+package ocm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ManagedServiceAccountLabel labels the secrets holding the token of a
+	// ManagedServiceAccount, in the namespace of the managed cluster on the
+	// hub cluster
+	ManagedServiceAccountLabel = "authentication.open-cluster-management.io/is-managed-serviceaccount"
+	// AvailableCondition is True when the managed cluster is available
+	AvailableCondition = "ManagedClusterConditionAvailable"
+
+	tokenKey = "token"
+	caKey    = "ca.crt"
+)
+
+// ManagedClusterGroupVersionKind is the kind of the OCM managed clusters
+var ManagedClusterGroupVersionKind = schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1", Kind: "ManagedCluster"}
+
+type OCM struct {
+	client.Client
+	Secret *corev1.Secret
+}
+
+// New returns the client of the managed cluster of a ManagedServiceAccount
+// secret, false if the secret is not a ManagedServiceAccount secret
+func New(c client.Client, secret *corev1.Secret) (*OCM, bool) {
+	if secret.GetLabels()[ManagedServiceAccountLabel] != "true" {
+		return nil, false
+	}
+	return &OCM{Client: c, Secret: secret}, true
+}
+
+// GetClusterName returns the name of the managed cluster, the namespace of
+// the secret
+func (r *OCM) GetClusterName() string {
+	if r.Secret == nil {
+		return ""
+	}
+	return r.Secret.GetNamespace()
+}
+
+// GetClusterClient returns the client of the managed cluster, the cluster is
+// ready when the ManagedCluster is available
+func (r *OCM) GetClusterClient(ctx context.Context) (resource.APIPatchingApplicator, bool, error) {
+	identity := kubeconfig.ClusterIdentity("ocm", "", r.GetClusterName())
+	mc := &unstructured.Unstructured{}
+	mc.SetGroupVersionKind(ManagedClusterGroupVersionKind)
+	if err := r.Get(ctx, types.NamespacedName{Name: r.GetClusterName()}, mc); err != nil {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, client.IgnoreNotFound(err)
+	}
+	if !isAvailable(mc) {
+		cache.Default().Invalidate(identity)
+		return resource.APIPatchingApplicator{}, false, nil
+	}
+	config, err := r.restConfig(mc)
+	if err != nil {
+		return resource.APIPatchingApplicator{}, false, err
+	}
+	return kubeconfig.NewClusterClient(ctx, identity, config)
+}
+
+func isAvailable(mc *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(mc.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if ok && m["type"] == AvailableCondition {
+			return m["status"] == string(corev1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+// restConfig returns the rest config of the managed cluster, the API server
+// is the first client config of the ManagedCluster
+func (r *OCM) restConfig(mc *unstructured.Unstructured) (*rest.Config, error) {
+	configs, _, _ := unstructured.NestedSlice(mc.Object, "spec", "managedClusterClientConfigs")
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("managed cluster %s has no client config", mc.GetName())
+	}
+	cfg, _ := configs[0].(map[string]any)
+	url, _ := cfg["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("managed cluster %s has no API server url", mc.GetName())
+	}
+	token := r.Secret.Data[tokenKey]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("managed service account secret %s has no token", r.Secret.GetName())
+	}
+	caData := r.Secret.Data[caKey]
+	if caBundle, ok := cfg["caBundle"].(string); ok && len(caData) == 0 {
+		b, err := base64.StdEncoding.DecodeString(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caBundle of managed cluster %s: %w", mc.GetName(), err)
+		}
+		caData = b
+	}
+	return &rest.Config{
+		Host:            url,
+		BearerToken:     string(token),
+		TLSClientConfig: rest.TLSClientConfig{CAData: caData},
+	}, nil
+}
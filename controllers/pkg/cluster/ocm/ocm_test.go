@@ -0,0 +1,86 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetClusterClient(t *testing.T) {
+	ctx := context.Background()
+	mc := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"managedClusterClientConfigs": []any{
+				map[string]any{"url": "https://edge01.example.com:6443", "caBundle": "Y2E="},
+			},
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": AvailableCondition, "status": "Unknown"},
+			},
+		},
+	}}
+	mc.SetGroupVersionKind(ManagedClusterGroupVersionKind)
+	mc.SetName("edge01")
+	c := fake.NewClientBuilder().WithObjects(mc).Build()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "edge01",
+			Name:      "nephio",
+			Labels:    map[string]string{ManagedServiceAccountLabel: "true"},
+		},
+		Data: map[string][]byte{"token": []byte("t")},
+	}
+	o, ok := New(c, secret)
+	require.True(t, ok)
+	require.Equal(t, "edge01", o.GetClusterName())
+
+	// the cluster is not ready until the managed cluster is available
+	_, ok, err := o.GetClusterClient(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	config, err := o.restConfig(mc)
+	require.NoError(t, err)
+	require.Equal(t, "https://edge01.example.com:6443", config.Host)
+	require.Equal(t, "t", config.BearerToken)
+	require.Equal(t, "ca", string(config.CAData))
+
+	require.NoError(t, unstructured.SetNestedSlice(mc.Object, []any{
+		map[string]any{"type": AvailableCondition, "status": "True"},
+	}, "status", "conditions"))
+	require.True(t, isAvailable(mc))
+
+	// the cluster of a secret without managed cluster is not ready
+	o, ok = New(c, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "edge02",
+		Name:      "nephio",
+		Labels:    map[string]string{ManagedServiceAccountLabel: "true"},
+	}})
+	require.True(t, ok)
+	_, ok, err = o.GetClusterClient(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok = New(c, &corev1.Secret{})
+	require.False(t, ok)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/argocd"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/focom"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/kubeconfig"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/ocm"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func init() {
+	RegisterProvider("capi", func(c client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		return provide(capi.New(c, secret))
+	})
+	RegisterProvider("kubeconfig", func(_ client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		return provide(kubeconfig.New(secret))
+	})
+	RegisterProvider("argocd", func(_ client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		return provide(argocd.New(secret))
+	})
+	RegisterProvider("ocm", func(c client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		return provide(ocm.New(c, secret))
+	})
+	RegisterProvider("focom", func(c client.Client, secret *corev1.Secret) (ClusterClient, bool) {
+		return provide(focom.New(c, secret))
+	})
+}
+
+// Provider returns the client of the cluster whose credentials are stored in
+// the secret, false if the secret does not hold the credentials of a cluster
+// of the provider. The client of the management cluster lets the provider
+// check the readiness of the cluster.
+type Provider func(c client.Client, secret *corev1.Secret) (ClusterClient, bool)
+
+type namedProvider struct {
+	name     string
+	provider Provider
+}
+
+// providers are tried in the order they are registered
+var providers = []namedProvider{}
+
+// RegisterProvider registers a cluster client provider, replacing the
+// provider registered with the same name. Providers are registered from init
+// functions.
+func RegisterProvider(name string, p Provider) {
+	for i := range providers {
+		if providers[i].name == name {
+			providers[i].provider = p
+			return
+		}
+	}
+	providers = append(providers, namedProvider{name: name, provider: p})
+}
+
+// ProviderNames returns the names of the registered providers, in the order
+// they are tried
+func ProviderNames() []string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+// provide returns the client of a provider as a ClusterClient, without
+// wrapping a nil pointer in the interface
+func provide[T ClusterClient](clusterClient T, ok bool) (ClusterClient, bool) {
+	if !ok {
+		return nil, false
+	}
+	return clusterClient, true
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
+	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretClusterNameIndex indexes the secrets holding the credentials of a
+	// cluster by the name of the cluster, as reported by the provider of the
+	// secret
+	SecretClusterNameIndex = "nephio.org/secret-cluster-name"
+	// ClusterNameIndex indexes the CAPI clusters by name
+	ClusterNameIndex = "nephio.org/cluster-name"
+)
+
+// Indexes returns the indexes resolving the clusters by name, the reconcilers
+// resolving clusters return them from their Indexes method
+func Indexes() []reconcilerinterface.Index {
+	return []reconcilerinterface.Index{
+		{Object: &corev1.Secret{}, Field: SecretClusterNameIndex, Extract: IndexSecretByClusterName},
+		{Object: &capiv1beta1.Cluster{}, Field: ClusterNameIndex, Extract: IndexClusterByName},
+	}
+}
+
+// IndexSecretByClusterName returns the name of the cluster of the secret, for
+// the secrets a provider recognizes
+func IndexSecretByClusterName(obj client.Object) []string {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+	_, clusterClient, ok := Cluster{}.getProviderClient(secret)
+	if !ok || clusterClient.GetClusterName() == "" {
+		return nil
+	}
+	return []string{clusterClient.GetClusterName()}
+}
+
+// IndexClusterByName returns the name of the CAPI cluster
+func IndexClusterByName(obj client.Object) []string {
+	return []string{obj.GetName()}
+}
+
+// GetCapiCluster returns the CAPI cluster with the name, nil if it does not
+// exist. The name of a cluster must be unique across the namespaces.
+func (r Cluster) GetCapiCluster(ctx context.Context, clusterName string) (*capiv1beta1.Cluster, error) {
+	return GetCapiCluster(ctx, r.Client, clusterName)
+}
+
+// GetCapiCluster returns the CAPI cluster with the name using the reader,
+// whose cache must have the ClusterNameIndex, see GetCapiCluster of Cluster.
+func GetCapiCluster(ctx context.Context, c client.Reader, clusterName string) (*capiv1beta1.Cluster, error) {
+	clusters := &capiv1beta1.ClusterList{}
+	if err := c.List(ctx, clusters, client.MatchingFields{ClusterNameIndex: clusterName}); err != nil {
+		return nil, fmt.Errorf("cannot list clusters: %w", err)
+	}
+	switch len(clusters.Items) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &clusters.Items[0], nil
+	}
+	return nil, fmt.Errorf("cluster %s exists in namespaces %s and %s", clusterName, clusters.Items[0].GetNamespace(), clusters.Items[1].GetNamespace())
+}
+
+// GetClusterClientFor returns the client of the CAPI cluster with the
+// namespace and name, built from the <name>-kubeconfig secret in the
+// namespace of the cluster. It returns false if the secret does not exist
+// (yet).
+func (r Cluster) GetClusterClientFor(ctx context.Context, ref types.NamespacedName) (ClusterClient, bool, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: capi.KubeconfigSecretName(ref.Name)}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, false, client.IgnoreNotFound(err)
+	}
+	clusterClient, ok := r.GetClusterClient(secret)
+	return clusterClient, ok, nil
+}
+
+// ResolveClusterClient returns the client of the cluster with the name. The
+// CAPI cluster with the name resolves to its <name>-kubeconfig secret, the
+// other clusters to the secret whose provider reports the name, the first
+// registered provider winning. It returns false if the cluster has no
+// credentials (yet), and an error if the name is ambiguous.
+func (r Cluster) ResolveClusterClient(ctx context.Context, clusterName string) (ClusterClient, bool, error) {
+	cl, err := r.GetCapiCluster(ctx, clusterName)
+	if err != nil {
+		return nil, false, err
+	}
+	if cl != nil {
+		return r.GetClusterClientFor(ctx, client.ObjectKeyFromObject(cl))
+	}
+
+	secret, clusterClient, err := r.resolveSecret(ctx, clusterName)
+	if err != nil || secret == nil {
+		return nil, false, err
+	}
+	return clusterClient, true, nil
+}
+
+// ResolveClusterObject returns the object representing the cluster with the
+// name, for the reconcilers reading the settings of a cluster from its
+// annotations: the CAPI cluster with the name, or the secret holding the
+// credentials of a cluster of another provider, see ResolveClusterClient. It
+// returns nil if the cluster does not exist or has no credentials (yet).
+func (r Cluster) ResolveClusterObject(ctx context.Context, clusterName string) (client.Object, error) {
+	cl, err := r.GetCapiCluster(ctx, clusterName)
+	if err != nil || cl != nil {
+		return cl, err
+	}
+	secret, _, err := r.resolveSecret(ctx, clusterName)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// resolveSecret returns the secret for which a provider reports the name of
+// the cluster, and its client, nil if there is none
+func (r Cluster) resolveSecret(ctx context.Context, clusterName string) (*corev1.Secret, ClusterClient, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingFields{SecretClusterNameIndex: clusterName}); err != nil {
+		return nil, nil, fmt.Errorf("cannot list secrets: %w", err)
+	}
+	best := len(providers)
+	var found *corev1.Secret
+	var clusterClient ClusterClient
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		p, cc, ok := r.getProviderClient(secret)
+		if !ok || cc.GetClusterName() != clusterName {
+			continue
+		}
+		switch {
+		case p < best:
+			best, found, clusterClient = p, secret, cc
+		case p == best:
+			return nil, nil, fmt.Errorf("cluster %s has credentials in secrets %s and %s",
+				clusterName, client.ObjectKeyFromObject(found), client.ObjectKeyFromObject(secret))
+		}
+	}
+	return found, clusterClient, nil
+}
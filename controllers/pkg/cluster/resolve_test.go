@@ -0,0 +1,146 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newKubeconfigSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretType("cluster.x-k8s.io/secret"),
+	}
+}
+
+func newArgoCDSecret(namespace, name, clusterName string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"argocd.argoproj.io/secret-type": "cluster"},
+		},
+		Data: map[string][]byte{"name": []byte(clusterName)},
+	}
+}
+
+func TestResolveClusterClient(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&corev1.Secret{}, SecretClusterNameIndex, IndexSecretByClusterName).
+		WithIndex(&capiv1beta1.Cluster{}, ClusterNameIndex, IndexClusterByName).
+		WithObjects(
+			// edge1 is being provisioned, the kubeconfig of edge10 must not
+			// be used for it
+			newCapiCluster("default", "edge1", nil, false),
+			newCapiCluster("default", "edge10", nil, true),
+			newKubeconfigSecret("default", "edge10-kubeconfig"),
+			// the CAPI kubeconfig wins over the argocd secret
+			newArgoCDSecret("argocd", "cluster-edge10", "edge10"),
+			// clusters that are not CAPI clusters
+			newArgoCDSecret("argocd", "cluster-edge20", "edge20"),
+			newArgoCDSecret("argocd", "cluster-edge30-a", "edge30"),
+			newArgoCDSecret("argocd", "cluster-edge30-b", "edge30"),
+			// the name of a CAPI cluster must be unique
+			newCapiCluster("ns1", "edge40", nil, true),
+			newCapiCluster("ns2", "edge40", nil, true),
+		).Build()
+	r := Cluster{Client: c}
+
+	cases := map[string]struct {
+		clusterName string
+		want        string
+		wantOk      bool
+		wantErr     bool
+	}{
+		"Provisioning": {clusterName: "edge1"},
+		"Capi":         {clusterName: "edge10", want: "*capi.Capi", wantOk: true},
+		"ArgoCD":       {clusterName: "edge20", want: "*argocd.ArgoCD", wantOk: true},
+		"AmbiguousSecrets": {
+			clusterName: "edge30",
+			wantErr:     true,
+		},
+		"AmbiguousClusters": {
+			clusterName: "edge40",
+			wantErr:     true,
+		},
+		"NotFound": {clusterName: "edge"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clusterClient, ok, err := r.ResolveClusterClient(ctx, tc.clusterName)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantOk, ok)
+			if ok {
+				require.Equal(t, tc.clusterName, clusterClient.GetClusterName())
+				require.Equal(t, tc.want, fmt.Sprintf("%T", clusterClient))
+			}
+		})
+	}
+
+	clusterClient, ok, err := r.GetClusterClientFor(ctx, types.NamespacedName{Namespace: "default", Name: "edge10"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "edge10", clusterClient.GetClusterName())
+	_, ok, err = r.GetClusterClientFor(ctx, types.NamespacedName{Namespace: "default", Name: "edge1"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the settings of a cluster are read from the CAPI cluster, or from the
+	// credentials of the other clusters
+	obj, err := r.ResolveClusterObject(ctx, "edge1")
+	require.NoError(t, err)
+	require.IsType(t, &capiv1beta1.Cluster{}, obj)
+	obj, err = r.ResolveClusterObject(ctx, "edge20")
+	require.NoError(t, err)
+	require.Equal(t, types.NamespacedName{Namespace: "argocd", Name: "cluster-edge20"}, client.ObjectKeyFromObject(obj))
+	obj, err = r.ResolveClusterObject(ctx, "edge")
+	require.NoError(t, err)
+	require.Nil(t, obj)
+	_, err = r.ResolveClusterObject(ctx, "edge30")
+	require.Error(t, err)
+}
+
+func TestIndexes(t *testing.T) {
+	var fields []string
+	for _, index := range Indexes() {
+		fields = append(fields, index.Field)
+	}
+	require.Equal(t, []string{SecretClusterNameIndex, ClusterNameIndex}, fields)
+
+	require.Equal(t, []string{"edge1"}, IndexSecretByClusterName(newKubeconfigSecret("default", "edge1-kubeconfig")))
+	require.Equal(t, []string{"edge2"}, IndexSecretByClusterName(newArgoCDSecret("argocd", "cluster-x", "edge2")))
+	require.Nil(t, IndexSecretByClusterName(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "edge1-kubeconfig"}}))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
+	"k8s.io/apimachinery/pkg/labels"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// SelectorAnnotation selects the CAPI clusters a resource is installed on,
+// with a label selector over the labels of the clusters such as
+// "region=eu-west,tier=edge"
+const SelectorAnnotation = "nephio.org/cluster-selector"
+
+// GetSelector returns the cluster selector of the object, false if the object
+// has no cluster selector
+func GetSelector(obj client.Object) (labels.Selector, bool, error) {
+	value, ok := obj.GetAnnotations()[SelectorAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid %s annotation %q: %w", SelectorAnnotation, value, err)
+	}
+	if selector.Empty() {
+		return nil, true, fmt.Errorf("invalid %s annotation %q: the selector selects all clusters", SelectorAnnotation, value)
+	}
+	return selector, true, nil
+}
+
+// SelectClusters returns the CAPI clusters, in all namespaces, matching the
+// selector
+func (r Cluster) SelectClusters(ctx context.Context, selector labels.Selector) ([]capiv1beta1.Cluster, error) {
+	clusters := &capiv1beta1.ClusterList{}
+	if err := r.List(ctx, clusters, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("cannot list clusters: %w", err)
+	}
+	return clusters.Items, nil
+}
+
+// SelectionChanged passes the events of the CAPI clusters that can change the
+// clusters a resource is installed on: clusters that are created or deleted,
+// become ready or not ready against the conditions of their readiness
+// criteria, or whose labels or readiness criteria change
+func SelectionChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*capiv1beta1.Cluster)
+			if !ok {
+				return false
+			}
+			newCluster, ok := e.ObjectNew.(*capiv1beta1.Cluster)
+			if !ok {
+				return false
+			}
+			return capi.IsClusterReady(oldCluster) != capi.IsClusterReady(newCluster) ||
+				oldCluster.GetAnnotations()[capi.ReadinessCriteriaAnnotation] != newCluster.GetAnnotations()[capi.ReadinessCriteriaAnnotation] ||
+				!maps.Equal(oldCluster.GetLabels(), newCluster.GetLabels())
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
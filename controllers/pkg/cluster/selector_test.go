@@ -0,0 +1,134 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newCapiCluster(namespace, name string, lbls map[string]string, ready bool) *capiv1beta1.Cluster {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &capiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: lbls},
+		Status: capiv1beta1.ClusterStatus{
+			Conditions: capiv1beta1.Conditions{{Type: capiv1beta1.ReadyCondition, Status: status}},
+		},
+	}
+}
+
+func TestGetSelector(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		want        string
+		wantOk      bool
+		wantErr     bool
+	}{
+		"None": {},
+		"Selector": {
+			annotations: map[string]string{SelectorAnnotation: "region=eu-west,tier=edge"},
+			want:        "region=eu-west,tier=edge",
+			wantOk:      true,
+		},
+		"Invalid": {
+			annotations: map[string]string{SelectorAnnotation: "region in eu-west"},
+			wantOk:      true,
+			wantErr:     true,
+		},
+		"Empty": {
+			annotations: map[string]string{SelectorAnnotation: ""},
+			wantOk:      true,
+			wantErr:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			selector, ok, err := GetSelector(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}})
+			require.Equal(t, tc.wantOk, ok)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantOk {
+				require.Equal(t, tc.want, selector.String())
+			}
+		})
+	}
+}
+
+func TestSelectClusters(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	edge := newCapiCluster("default", "edge1", map[string]string{"region": "eu-west", "tier": "edge"}, true)
+	edge10 := newCapiCluster("default", "edge10", map[string]string{"region": "eu-west", "tier": "edge"}, false)
+	core := newCapiCluster("default", "core", map[string]string{"region": "eu-west", "tier": "core"}, true)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		edge, edge10, core,
+	).Build()
+	r := Cluster{Client: c}
+
+	selector, err := labels.Parse("region=eu-west,tier=edge")
+	require.NoError(t, err)
+	clusters, err := r.SelectClusters(ctx, selector)
+	require.NoError(t, err)
+	names := []string{}
+	for _, cl := range clusters {
+		names = append(names, cl.GetName())
+	}
+	require.ElementsMatch(t, []string{"edge1", "edge10"}, names)
+}
+
+func TestSelectionChanged(t *testing.T) {
+	notReady := newCapiCluster("default", "edge1", map[string]string{"tier": "edge"}, false)
+	ready := newCapiCluster("default", "edge1", map[string]string{"tier": "edge"}, true)
+	relabeled := newCapiCluster("default", "edge1", map[string]string{"tier": "core"}, true)
+	p := SelectionChanged()
+
+	require.True(t, p.Create(event.CreateEvent{Object: notReady}))
+	require.True(t, p.Delete(event.DeleteEvent{Object: ready}))
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: notReady, ObjectNew: ready}))
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: ready, ObjectNew: relabeled}))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: ready, ObjectNew: ready.DeepCopy()}))
+	require.False(t, p.Generic(event.GenericEvent{Object: ready}))
+
+	// the readiness criteria of the cluster are evaluated
+	controlPlaneRequired := ready.DeepCopy()
+	controlPlaneRequired.SetAnnotations(map[string]string{capi.ReadinessCriteriaAnnotation: "Ready,ControlPlaneReady"})
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: ready, ObjectNew: controlPlaneRequired}))
+	controlPlaneReady := controlPlaneRequired.DeepCopy()
+	controlPlaneReady.Status.Conditions = append(controlPlaneReady.Status.Conditions,
+		capiv1beta1.Condition{Type: capiv1beta1.ControlPlaneReadyCondition, Status: corev1.ConditionTrue})
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: controlPlaneRequired, ObjectNew: controlPlaneReady}))
+	require.False(t, capi.IsClusterReady(controlPlaneRequired))
+	require.True(t, capi.IsClusterReady(controlPlaneReady))
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package giteaclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// ReadyzCheckName is the name of the readiness check of the default git
+	// server
+	ReadyzCheckName = "git-server"
+	// healthCheckPeriod is the period the readiness check contacts the git
+	// server, readiness probes in between get the last result
+	healthCheckPeriod = 30 * time.Second
+)
+
+// defaultServer is the default git server, configured with environment
+// variables
+type defaultServer struct {
+	URL      string
+	Provider string
+	Secret   types.NamespacedName
+}
+
+// defaultServerFromEnv returns the default git server, false if GIT_URL is not
+// set
+func defaultServerFromEnv() (defaultServer, bool) {
+	gitURL, ok := os.LookupEnv("GIT_URL")
+	if !ok {
+		return defaultServer{}, false
+	}
+	server := defaultServer{
+		URL:      gitURL,
+		Provider: gitprovider.ProviderGitea,
+		Secret: types.NamespacedName{
+			Namespace: os.Getenv("POD_NAMESPACE"),
+			Name:      "git-user-secret",
+		},
+	}
+	if gitProvider, ok := os.LookupEnv("GIT_PROVIDER"); ok {
+		server.Provider = gitProvider
+	}
+	if gitNamespace, ok := os.LookupEnv("GIT_NAMESPACE"); ok {
+		server.Secret.Namespace = gitNamespace
+	}
+	if gitSecretName, ok := os.LookupEnv("GIT_SECRET_NAME"); ok {
+		server.Secret.Name = gitSecretName
+	}
+	return server, true
+}
+
+type defaultClient struct {
+	gc
+	client      resource.APIPatchingApplicator
+	newProvider func(name string, cfg gitprovider.Config) (gitprovider.GitProvider, error)
+	server      defaultServer
+	configured  bool
+	setup       sync.Once
+	setupErr    error
+
+	m sync.Mutex
+	// version is the resourceVersion of the Secret the client authenticated
+	// with, err the error of the last authentication
+	version     string
+	err         error
+	checkedAt   time.Time
+	checkErr    error
+	subscribers []chan event.GenericEvent
+}
+
+func newDefaultClient(c resource.APIPatchingApplicator) *defaultClient {
+	server, configured := defaultServerFromEnv()
+	return &defaultClient{
+		client:      c,
+		newProvider: gitprovider.New,
+		server:      server,
+		configured:  configured,
+	}
+}
+
+func (r *defaultClient) SetupWithManager(mgr ctrl.Manager) error {
+	r.setup.Do(func() {
+		r.setupErr = r.setupWithManager(mgr)
+	})
+	return r.setupErr
+}
+
+func (r *defaultClient) setupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.AddReadyzCheck(ReadyzCheckName, r.ReadyzCheck); err != nil {
+		return err
+	}
+	if !r.configured {
+		log.Log.Info("GIT_URL not defined, no default git server")
+		return nil
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("GitClientController").
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return client.ObjectKeyFromObject(obj) == r.server.Secret
+		}))).
+		Complete(r)
+}
+
+// Reconcile authenticates to the git server with the credentials Secret when
+// the Secret is created or changed. Failed authentications are retried with
+// the backoff of the controller.
+func (r *defaultClient) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("secret deleted, git client is not ready", "secret", req.NamespacedName)
+		r.gc.set(nil)
+		r.setStatus("", fmt.Errorf("secret %s not found, please follow README and create the git secret", req.NamespacedName))
+		return ctrl.Result{}, nil
+	}
+
+	r.m.Lock()
+	unchanged := r.version == secret.GetResourceVersion() && r.err == nil
+	r.m.Unlock()
+	if unchanged {
+		return ctrl.Result{}, nil
+	}
+
+	provider, err := r.newProvider(r.server.Provider, getProviderConfig(r.server.URL, secret))
+	if err != nil {
+		log.Error(err, "cannot authenticate to git server", "provider", r.server.Provider)
+		r.setStatus(secret.GetResourceVersion(), err)
+		return ctrl.Result{}, err
+	}
+	r.gc.set(provider)
+	r.setStatus(secret.GetResourceVersion(), nil)
+	log.Info("git client init done", "provider", r.server.Provider)
+	r.notify(secret)
+	return ctrl.Result{}, nil
+}
+
+func (r *defaultClient) setStatus(version string, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.version = version
+	r.err = err
+	r.checkedAt = time.Time{}
+}
+
+func (r *defaultClient) Subscribe() <-chan event.GenericEvent {
+	r.m.Lock()
+	defer r.m.Unlock()
+	ch := make(chan event.GenericEvent, 1)
+	r.subscribers = append(r.subscribers, ch)
+	return ch
+}
+
+// notify sends an event to the subscribers, unless they have an event pending
+func (r *defaultClient) notify(secret *corev1.Secret) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, ch := range r.subscribers {
+		select {
+		case ch <- event.GenericEvent{Object: secret}:
+		default:
+		}
+	}
+}
+
+// ReadyzCheck reports whether the default git server is reachable with the
+// credentials of the client. It passes when no default git server is
+// configured.
+func (r *defaultClient) ReadyzCheck(_ *http.Request) error {
+	if !r.configured {
+		return nil
+	}
+	provider, err := r.gc.get()
+	r.m.Lock()
+	version, authErr := r.version, r.err
+	checkedAt, checkErr := r.checkedAt, r.checkErr
+	r.m.Unlock()
+	if authErr != nil {
+		return fmt.Errorf("cannot authenticate to git server: %s", authErr.Error())
+	}
+	if err != nil {
+		return err
+	}
+	if time.Since(checkedAt) < healthCheckPeriod {
+		return checkErr
+	}
+
+	// the git server is contacted without holding the lock, the result is
+	// dropped if the client authenticated again in the meantime
+	_, err = provider.GetMyUserInfo()
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.version == version && r.err == nil {
+		r.checkedAt, r.checkErr = time.Now(), err
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giteaclient
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestDefaultClient(t *testing.T) {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "nephio-system", Name: "git-user-secret"}
+	c := newTestClient(t)
+	var configs []gitprovider.Config
+	var authErr error
+	r := &defaultClient{
+		client: resource.NewAPIPatchingApplicator(c),
+		newProvider: func(name string, cfg gitprovider.Config) (gitprovider.GitProvider, error) {
+			configs = append(configs, cfg)
+			if authErr != nil {
+				return nil, authErr
+			}
+			return gitprovider.NewFake(cfg.Username), nil
+		},
+		server:     defaultServer{URL: "https://git.example.com", Provider: gitprovider.ProviderGitea, Secret: key},
+		configured: true,
+	}
+	ready := r.Subscribe()
+
+	// the client waits for its secret
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.False(t, r.IsInitialized())
+	require.ErrorContains(t, r.ReadyzCheck(nil), "not found")
+	require.Empty(t, ready)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string][]byte{"username": []byte("nephio"), "password": []byte("p1")},
+	}
+	require.NoError(t, c.Create(ctx, secret))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.True(t, r.IsInitialized())
+	require.NoError(t, r.ReadyzCheck(nil))
+	require.Len(t, ready, 1)
+	<-ready
+
+	// an unchanged secret does not authenticate again
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+
+	// changed credentials authenticate again
+	secret.Data["username"] = []byte("admin")
+	require.NoError(t, c.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	u, err := r.GetMyUserInfo()
+	require.NoError(t, err)
+	require.Equal(t, "admin", u.UserName)
+	require.Len(t, ready, 1)
+
+	// failed authentications are retried and reported by the readiness check
+	authErr = fmt.Errorf("connection refused")
+	secret.Data["password"] = []byte("p2")
+	require.NoError(t, c.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.ErrorContains(t, err, "connection refused")
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.Error(t, err)
+	require.Len(t, configs, 4)
+	require.ErrorContains(t, r.ReadyzCheck(nil), "connection refused")
+
+	// a client whose secret is deleted returns errors instead of panicking
+	require.NoError(t, c.Delete(ctx, secret))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.False(t, r.IsInitialized())
+	_, err = r.ListAccessTokens()
+	require.ErrorIs(t, err, ErrNotInitialized)
+	require.ErrorIs(t, r.DeleteRepo("nephio", "mgmt"), ErrNotInitialized)
+
+	require.NoError(t, (&defaultClient{}).ReadyzCheck(nil))
+}
+
+// blockingProvider blocks GetMyUserInfo until unblocked
+type blockingProvider struct {
+	*gitprovider.Fake
+	called  chan struct{}
+	unblock chan struct{}
+}
+
+func (r *blockingProvider) GetMyUserInfo() (*gitprovider.User, error) {
+	r.called <- struct{}{}
+	<-r.unblock
+	return r.Fake.GetMyUserInfo()
+}
+
+func TestReadyzCheckDoesNotBlock(t *testing.T) {
+	provider := &blockingProvider{
+		Fake:    gitprovider.NewFake("nephio"),
+		called:  make(chan struct{}),
+		unblock: make(chan struct{}),
+	}
+	r := &defaultClient{configured: true}
+	r.gc.set(provider)
+
+	done := make(chan error)
+	go func() { done <- r.ReadyzCheck(nil) }()
+	<-provider.called
+	// the client is usable while the git server is contacted
+	r.Subscribe()
+	r.setStatus("1", nil)
+	close(provider.unblock)
+	require.NoError(t, <-done)
+	// the result of a check that raced with an authentication is not kept
+	require.True(t, r.checkedAt.IsZero())
+}
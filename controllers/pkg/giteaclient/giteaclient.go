@@ -18,38 +18,48 @@ package giteaclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"sync"
-	"time"
 
-	"code.gitea.io/sdk/gitea"
-	"github.com/go-logr/logr"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// GiteaClient is the client of a git server the repository and token
+// reconcilers manage repositories and tokens on. Despite its name the git
+// server can be any of the providers of the gitprovider package.
 type GiteaClient interface {
-	Start(ctx context.Context)
 	IsInitialized() bool
-	Get() *gitea.Client
-	GetMyUserInfo() (*gitea.User, *gitea.Response, error)
-	DeleteRepo(owner string, repo string) (*gitea.Response, error)
-	GetRepo(userName string, repoCRName string) (*gitea.Repository, *gitea.Response, error)
-	CreateRepo(createRepoOption gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error)
-	EditRepo(userName string, repoCRName string, editRepoOption gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error)
-	DeleteAccessToken(value interface{}) (*gitea.Response, error)
-	ListAccessTokens(opts gitea.ListAccessTokensOptions) ([]*gitea.AccessToken, *gitea.Response, error)
-	CreateAccessToken(opt gitea.CreateAccessTokenOption) (*gitea.AccessToken, *gitea.Response, error)
+	gitprovider.GitProvider
+}
+
+// DefaultClient is the client of the default git server, configured with the
+// GIT_URL, GIT_PROVIDER, GIT_NAMESPACE and GIT_SECRET_NAME environment
+// variables. It authenticates when its credentials Secret appears, and again
+// whenever the Secret changes.
+type DefaultClient interface {
+	GiteaClient
+	// SetupWithManager watches the credentials Secret with the manager and
+	// adds the readiness check of the git server to it. Only the first call
+	// sets up the client, the client is shared by the reconcilers.
+	SetupWithManager(mgr ctrl.Manager) error
+	// Subscribe returns a channel receiving an event each time the client
+	// becomes ready, to reconcile the objects waiting for it
+	Subscribe() <-chan event.GenericEvent
 }
 
 var lock = &sync.Mutex{}
 
-var singleInstance *gc
+var singleInstance *defaultClient
 
-func GetClient(ctx context.Context, client resource.APIPatchingApplicator) (GiteaClient, error) {
+// GetClient returns the client of the default git server. The client reads
+// its credentials Secret with the client passed by the first caller.
+func GetClient(ctx context.Context, client resource.APIPatchingApplicator) (DefaultClient, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("failed creating gitea client, value of ctx cannot be nil")
 	}
@@ -57,129 +67,222 @@ func GetClient(ctx context.Context, client resource.APIPatchingApplicator) (Gite
 	if client.Client == nil {
 		return nil, fmt.Errorf("failed creating gitea client, value of client.Client cannot be nil")
 	}
-	// check if an instance is created using check-lock-check pattern implementation
+	lock.Lock()
+	defer lock.Unlock()
 	if singleInstance == nil {
-		// Create a lock
-		lock.Lock()
-		defer lock.Unlock()
-		// Check instance is still null as another thread of execution may have initialized it before the lock was acquired.
-		if singleInstance == nil {
-			singleInstance = &gc{client: client}
-			log.FromContext(ctx).Info("Gitea Client Instance created now.")
-			go singleInstance.Start(ctx)
-		} else {
-			log.FromContext(ctx).Info("Gitea Client Instance already created.")
-		}
+		singleInstance = newDefaultClient(client)
+		log.FromContext(ctx).Info("Gitea Client Instance created now.")
 	} else {
 		log.FromContext(ctx).Info("Gitea Client Instance already created.")
 	}
 	return singleInstance, nil
 }
 
+// ErrNotInitialized is returned by the methods of a client that is not
+// initialized, or no longer initialized since its credentials were deleted
+var ErrNotInitialized = errors.New("git client not initialized")
+
+// gc delegates to the provider of a git server, the provider is nil until the
+// client is initialized and again once its credentials are deleted
 type gc struct {
-	client resource.APIPatchingApplicator
-
-	giteaClient *gitea.Client
-	l           logr.Logger
-}
-
-func (r *gc) Start(ctx context.Context) {
-	for {
-		select {
-		// The context is the one returned by ctrl.SetupSignalHandler().
-		// cancel() of this context will trigger <- ctx.Done().
-		// The Idea for continuously retrying is for enabling the user to
-		// create a secret eventually even after the controllers are started.
-		case <-ctx.Done():
-			fmt.Printf("controller manager context cancelled: Exit\n")
-			return
-		default:
-			r.l = log.FromContext(ctx)
-			//var err error
-			time.Sleep(5 * time.Second)
-
-			gitURL, ok := os.LookupEnv("GIT_URL")
-			if !ok {
-				r.l.Error(fmt.Errorf("git url not defined"), "cannot connect to git server")
-				break
-			}
-
-			namespace := os.Getenv("POD_NAMESPACE")
-			if gitNamespace, ok := os.LookupEnv("GIT_NAMESPACE"); ok {
-				namespace = gitNamespace
-			}
-			secretName := "git-user-secret"
-			if gitSecretName, ok := os.LookupEnv("GIT_SECRET_NAME"); ok {
-				secretName = gitSecretName
-			}
-
-			// get secret that was created when installing gitea
-			secret := &corev1.Secret{}
-			if err := r.client.Get(ctx, types.NamespacedName{
-				Namespace: namespace,
-				Name:      secretName,
-			},
-				secret); err != nil {
-				r.l.Error(err, "Cannot get secret, please follow README and create the gitea secret")
-				break
-			}
-
-			// To create/list tokens we can only use basic authentication using username and password
-			giteaClient, err := gitea.NewClient(
-				gitURL,
-				getClientAuth(secret))
-			if err != nil {
-				r.l.Error(err, "cannot authenticate to gitea")
-				break
-			}
-
-			r.giteaClient = giteaClient
-			r.l.Info("gitea init done")
-			return
-		}
-	}
-}
-
-func getClientAuth(secret *corev1.Secret) gitea.ClientOption {
-	return gitea.SetBasicAuth(string(secret.Data["username"]), string(secret.Data["password"]))
+	m        sync.RWMutex
+	provider gitprovider.GitProvider
+}
+
+// get returns the provider, ErrNotInitialized if there is none
+func (r *gc) get() (gitprovider.GitProvider, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if r.provider == nil {
+		return nil, ErrNotInitialized
+	}
+	return r.provider, nil
+}
+
+func (r *gc) set(provider gitprovider.GitProvider) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.provider = provider
+}
+
+func getProviderConfig(gitURL string, secret *corev1.Secret) gitprovider.Config {
+	return gitprovider.Config{
+		URL:      gitURL,
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+		Token:    string(secret.Data["token"]),
+	}
 }
 
 func (r *gc) IsInitialized() bool {
-	return r.giteaClient != nil
+	_, err := r.get()
+	return err == nil
+}
+
+func (r *gc) GetMyUserInfo() (*gitprovider.User, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetMyUserInfo()
+}
+
+func (r *gc) GetOrg(name string) (*gitprovider.Organization, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetOrg(name)
+}
+
+func (r *gc) CreateOrg(opt gitprovider.CreateOrgOption) (*gitprovider.Organization, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateOrg(opt)
+}
+
+func (r *gc) DeleteRepo(owner string, name string) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteRepo(owner, name)
+}
+
+func (r *gc) GetRepo(owner string, name string) (*gitprovider.Repository, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetRepo(owner, name)
+}
+
+func (r *gc) CreateRepo(opt gitprovider.CreateRepoOption) (*gitprovider.Repository, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateRepo(opt)
+}
+
+func (r *gc) EditRepo(owner string, name string, opt gitprovider.EditRepoOption) (*gitprovider.Repository, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.EditRepo(owner, name, opt)
+}
+
+func (r *gc) AddRepoTeam(owner string, name string, team string, permission gitprovider.Permission) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.AddRepoTeam(owner, name, team, permission)
+}
+
+func (r *gc) AddRepoCollaborator(owner string, name string, user string, permission gitprovider.Permission) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.AddRepoCollaborator(owner, name, user, permission)
+}
+
+func (r *gc) GetBranchProtection(owner string, name string, branch string) (*gitprovider.BranchProtection, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetBranchProtection(owner, name, branch)
+}
+
+func (r *gc) SetBranchProtection(owner string, name string, protection gitprovider.BranchProtection) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.SetBranchProtection(owner, name, protection)
 }
 
-func (r *gc) Get() *gitea.Client {
-	return r.giteaClient
+func (r *gc) ListRepoHooks(owner string, name string) ([]*gitprovider.Hook, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListRepoHooks(owner, name)
 }
 
-func (r *gc) GetMyUserInfo() (*gitea.User, *gitea.Response, error) {
-	return r.giteaClient.GetMyUserInfo()
+func (r *gc) CreateRepoHook(owner string, name string, hook gitprovider.Hook) (*gitprovider.Hook, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateRepoHook(owner, name, hook)
+}
+
+func (r *gc) EditRepoHook(owner string, name string, hook gitprovider.Hook) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.EditRepoHook(owner, name, hook)
 }
 
-func (r *gc) DeleteRepo(owner string, repo string) (*gitea.Response, error) {
-	return r.giteaClient.DeleteRepo(owner, repo)
+func (r *gc) DeleteRepoHook(owner string, name string, id int64) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteRepoHook(owner, name, id)
 }
 
-func (r *gc) GetRepo(userName string, repoCRName string) (*gitea.Repository, *gitea.Response, error) {
-	return r.giteaClient.GetRepo(userName, repoCRName)
+func (r *gc) ListDeployKeys(owner string, name string) ([]*gitprovider.DeployKey, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListDeployKeys(owner, name)
 }
 
-func (r *gc) CreateRepo(createRepoOption gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error) {
-	return r.giteaClient.CreateRepo(createRepoOption)
+func (r *gc) CreateDeployKey(owner string, name string, key gitprovider.DeployKey) (*gitprovider.DeployKey, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateDeployKey(owner, name, key)
 }
 
-func (r *gc) EditRepo(userName string, repoCRName string, editRepoOption gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error) {
-	return r.giteaClient.EditRepo(userName, repoCRName, editRepoOption)
+func (r *gc) DeleteDeployKey(owner string, name string, id int64) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteDeployKey(owner, name, id)
 }
 
-func (r *gc) DeleteAccessToken(value interface{}) (*gitea.Response, error) {
-	return r.giteaClient.DeleteAccessToken(value)
+func (r *gc) DeleteAccessToken(name string) error {
+	provider, err := r.get()
+	if err != nil {
+		return err
+	}
+	return provider.DeleteAccessToken(name)
 }
 
-func (r *gc) ListAccessTokens(opts gitea.ListAccessTokensOptions) ([]*gitea.AccessToken, *gitea.Response, error) {
-	return r.giteaClient.ListAccessTokens(opts)
+func (r *gc) ListAccessTokens() ([]*gitprovider.AccessToken, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListAccessTokens()
 }
 
-func (r *gc) CreateAccessToken(opt gitea.CreateAccessTokenOption) (*gitea.AccessToken, *gitea.Response, error) {
-	return r.giteaClient.CreateAccessToken(opt)
+func (r *gc) CreateAccessToken(opt gitprovider.CreateAccessTokenOption) (*gitprovider.AccessToken, error) {
+	provider, err := r.get()
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateAccessToken(opt)
 }
@@ -19,9 +19,7 @@
 package giteaclient
 
 import (
-	context "context"
-
-	gitea "code.gitea.io/sdk/gitea"
+	gitprovider "github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -39,43 +37,132 @@ func (_m *MockGiteaClient) EXPECT() *MockGiteaClient_Expecter {
 	return &MockGiteaClient_Expecter{mock: &_m.Mock}
 }
 
+// AddRepoCollaborator provides a mock function with given fields: owner, name, user, permission
+func (_m *MockGiteaClient) AddRepoCollaborator(owner string, name string, user string, permission gitprovider.Permission) error {
+	ret := _m.Called(owner, name, user, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRepoCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, gitprovider.Permission) error); ok {
+		r0 = rf(owner, name, user, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGiteaClient_AddRepoCollaborator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRepoCollaborator'
+type MockGiteaClient_AddRepoCollaborator_Call struct {
+	*mock.Call
+}
+
+// AddRepoCollaborator is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - user string
+//   - permission gitprovider.Permission
+func (_e *MockGiteaClient_Expecter) AddRepoCollaborator(owner interface{}, name interface{}, user interface{}, permission interface{}) *MockGiteaClient_AddRepoCollaborator_Call {
+	return &MockGiteaClient_AddRepoCollaborator_Call{Call: _e.mock.On("AddRepoCollaborator", owner, name, user, permission)}
+}
+
+func (_c *MockGiteaClient_AddRepoCollaborator_Call) Run(run func(owner string, name string, user string, permission gitprovider.Permission)) *MockGiteaClient_AddRepoCollaborator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(gitprovider.Permission))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_AddRepoCollaborator_Call) Return(_a0 error) *MockGiteaClient_AddRepoCollaborator_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGiteaClient_AddRepoCollaborator_Call) RunAndReturn(run func(string, string, string, gitprovider.Permission) error) *MockGiteaClient_AddRepoCollaborator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddRepoTeam provides a mock function with given fields: owner, name, team, permission
+func (_m *MockGiteaClient) AddRepoTeam(owner string, name string, team string, permission gitprovider.Permission) error {
+	ret := _m.Called(owner, name, team, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRepoTeam")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, gitprovider.Permission) error); ok {
+		r0 = rf(owner, name, team, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGiteaClient_AddRepoTeam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRepoTeam'
+type MockGiteaClient_AddRepoTeam_Call struct {
+	*mock.Call
+}
+
+// AddRepoTeam is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - team string
+//   - permission gitprovider.Permission
+func (_e *MockGiteaClient_Expecter) AddRepoTeam(owner interface{}, name interface{}, team interface{}, permission interface{}) *MockGiteaClient_AddRepoTeam_Call {
+	return &MockGiteaClient_AddRepoTeam_Call{Call: _e.mock.On("AddRepoTeam", owner, name, team, permission)}
+}
+
+func (_c *MockGiteaClient_AddRepoTeam_Call) Run(run func(owner string, name string, team string, permission gitprovider.Permission)) *MockGiteaClient_AddRepoTeam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(gitprovider.Permission))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_AddRepoTeam_Call) Return(_a0 error) *MockGiteaClient_AddRepoTeam_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGiteaClient_AddRepoTeam_Call) RunAndReturn(run func(string, string, string, gitprovider.Permission) error) *MockGiteaClient_AddRepoTeam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAccessToken provides a mock function with given fields: opt
-func (_m *MockGiteaClient) CreateAccessToken(opt gitea.CreateAccessTokenOption) (*gitea.AccessToken, *gitea.Response, error) {
+func (_m *MockGiteaClient) CreateAccessToken(opt gitprovider.CreateAccessTokenOption) (*gitprovider.AccessToken, error) {
 	ret := _m.Called(opt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccessToken")
 	}
 
-	var r0 *gitea.AccessToken
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func(gitea.CreateAccessTokenOption) (*gitea.AccessToken, *gitea.Response, error)); ok {
+	var r0 *gitprovider.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateAccessTokenOption) (*gitprovider.AccessToken, error)); ok {
 		return rf(opt)
 	}
-	if rf, ok := ret.Get(0).(func(gitea.CreateAccessTokenOption) *gitea.AccessToken); ok {
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateAccessTokenOption) *gitprovider.AccessToken); ok {
 		r0 = rf(opt)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.AccessToken)
+			r0 = ret.Get(0).(*gitprovider.AccessToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(gitea.CreateAccessTokenOption) *gitea.Response); ok {
+	if rf, ok := ret.Get(1).(func(gitprovider.CreateAccessTokenOption) error); ok {
 		r1 = rf(opt)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
-		}
-	}
-
-	if rf, ok := ret.Get(2).(func(gitea.CreateAccessTokenOption) error); ok {
-		r2 = rf(opt)
-	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // MockGiteaClient_CreateAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAccessToken'
@@ -84,118 +171,169 @@ type MockGiteaClient_CreateAccessToken_Call struct {
 }
 
 // CreateAccessToken is a helper method to define mock.On call
-//   - opt gitea.CreateAccessTokenOption
+//   - opt gitprovider.CreateAccessTokenOption
 func (_e *MockGiteaClient_Expecter) CreateAccessToken(opt interface{}) *MockGiteaClient_CreateAccessToken_Call {
 	return &MockGiteaClient_CreateAccessToken_Call{Call: _e.mock.On("CreateAccessToken", opt)}
 }
 
-func (_c *MockGiteaClient_CreateAccessToken_Call) Run(run func(opt gitea.CreateAccessTokenOption)) *MockGiteaClient_CreateAccessToken_Call {
+func (_c *MockGiteaClient_CreateAccessToken_Call) Run(run func(opt gitprovider.CreateAccessTokenOption)) *MockGiteaClient_CreateAccessToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(gitea.CreateAccessTokenOption))
+		run(args[0].(gitprovider.CreateAccessTokenOption))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_CreateAccessToken_Call) Return(_a0 *gitea.AccessToken, _a1 *gitea.Response, _a2 error) *MockGiteaClient_CreateAccessToken_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_CreateAccessToken_Call) Return(_a0 *gitprovider.AccessToken, _a1 error) *MockGiteaClient_CreateAccessToken_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_CreateAccessToken_Call) RunAndReturn(run func(gitea.CreateAccessTokenOption) (*gitea.AccessToken, *gitea.Response, error)) *MockGiteaClient_CreateAccessToken_Call {
+func (_c *MockGiteaClient_CreateAccessToken_Call) RunAndReturn(run func(gitprovider.CreateAccessTokenOption) (*gitprovider.AccessToken, error)) *MockGiteaClient_CreateAccessToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateRepo provides a mock function with given fields: createRepoOption
-func (_m *MockGiteaClient) CreateRepo(createRepoOption gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error) {
-	ret := _m.Called(createRepoOption)
+// CreateDeployKey provides a mock function with given fields: owner, name, key
+func (_m *MockGiteaClient) CreateDeployKey(owner string, name string, key gitprovider.DeployKey) (*gitprovider.DeployKey, error) {
+	ret := _m.Called(owner, name, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRepo")
+		panic("no return value specified for CreateDeployKey")
 	}
 
-	var r0 *gitea.Repository
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func(gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error)); ok {
-		return rf(createRepoOption)
+	var r0 *gitprovider.DeployKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.DeployKey) (*gitprovider.DeployKey, error)); ok {
+		return rf(owner, name, key)
 	}
-	if rf, ok := ret.Get(0).(func(gitea.CreateRepoOption) *gitea.Repository); ok {
-		r0 = rf(createRepoOption)
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.DeployKey) *gitprovider.DeployKey); ok {
+		r0 = rf(owner, name, key)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Repository)
+			r0 = ret.Get(0).(*gitprovider.DeployKey)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(gitea.CreateRepoOption) *gitea.Response); ok {
-		r1 = rf(createRepoOption)
+	if rf, ok := ret.Get(1).(func(string, string, gitprovider.DeployKey) error); ok {
+		r1 = rf(owner, name, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGiteaClient_CreateDeployKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeployKey'
+type MockGiteaClient_CreateDeployKey_Call struct {
+	*mock.Call
+}
+
+// CreateDeployKey is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - key gitprovider.DeployKey
+func (_e *MockGiteaClient_Expecter) CreateDeployKey(owner interface{}, name interface{}, key interface{}) *MockGiteaClient_CreateDeployKey_Call {
+	return &MockGiteaClient_CreateDeployKey_Call{Call: _e.mock.On("CreateDeployKey", owner, name, key)}
+}
+
+func (_c *MockGiteaClient_CreateDeployKey_Call) Run(run func(owner string, name string, key gitprovider.DeployKey)) *MockGiteaClient_CreateDeployKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(gitprovider.DeployKey))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_CreateDeployKey_Call) Return(_a0 *gitprovider.DeployKey, _a1 error) *MockGiteaClient_CreateDeployKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_CreateDeployKey_Call) RunAndReturn(run func(string, string, gitprovider.DeployKey) (*gitprovider.DeployKey, error)) *MockGiteaClient_CreateDeployKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrg provides a mock function with given fields: opt
+func (_m *MockGiteaClient) CreateOrg(opt gitprovider.CreateOrgOption) (*gitprovider.Organization, error) {
+	ret := _m.Called(opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrg")
+	}
+
+	var r0 *gitprovider.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateOrgOption) (*gitprovider.Organization, error)); ok {
+		return rf(opt)
+	}
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateOrgOption) *gitprovider.Organization); ok {
+		r0 = rf(opt)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitprovider.Organization)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(gitea.CreateRepoOption) error); ok {
-		r2 = rf(createRepoOption)
+	if rf, ok := ret.Get(1).(func(gitprovider.CreateOrgOption) error); ok {
+		r1 = rf(opt)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// MockGiteaClient_CreateRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRepo'
-type MockGiteaClient_CreateRepo_Call struct {
+// MockGiteaClient_CreateOrg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrg'
+type MockGiteaClient_CreateOrg_Call struct {
 	*mock.Call
 }
 
-// CreateRepo is a helper method to define mock.On call
-//   - createRepoOption gitea.CreateRepoOption
-func (_e *MockGiteaClient_Expecter) CreateRepo(createRepoOption interface{}) *MockGiteaClient_CreateRepo_Call {
-	return &MockGiteaClient_CreateRepo_Call{Call: _e.mock.On("CreateRepo", createRepoOption)}
+// CreateOrg is a helper method to define mock.On call
+//   - opt gitprovider.CreateOrgOption
+func (_e *MockGiteaClient_Expecter) CreateOrg(opt interface{}) *MockGiteaClient_CreateOrg_Call {
+	return &MockGiteaClient_CreateOrg_Call{Call: _e.mock.On("CreateOrg", opt)}
 }
 
-func (_c *MockGiteaClient_CreateRepo_Call) Run(run func(createRepoOption gitea.CreateRepoOption)) *MockGiteaClient_CreateRepo_Call {
+func (_c *MockGiteaClient_CreateOrg_Call) Run(run func(opt gitprovider.CreateOrgOption)) *MockGiteaClient_CreateOrg_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(gitea.CreateRepoOption))
+		run(args[0].(gitprovider.CreateOrgOption))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_CreateRepo_Call) Return(_a0 *gitea.Repository, _a1 *gitea.Response, _a2 error) *MockGiteaClient_CreateRepo_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_CreateOrg_Call) Return(_a0 *gitprovider.Organization, _a1 error) *MockGiteaClient_CreateOrg_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_CreateRepo_Call) RunAndReturn(run func(gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error)) *MockGiteaClient_CreateRepo_Call {
+func (_c *MockGiteaClient_CreateOrg_Call) RunAndReturn(run func(gitprovider.CreateOrgOption) (*gitprovider.Organization, error)) *MockGiteaClient_CreateOrg_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteAccessToken provides a mock function with given fields: value
-func (_m *MockGiteaClient) DeleteAccessToken(value interface{}) (*gitea.Response, error) {
-	ret := _m.Called(value)
+// CreateRepo provides a mock function with given fields: opt
+func (_m *MockGiteaClient) CreateRepo(opt gitprovider.CreateRepoOption) (*gitprovider.Repository, error) {
+	ret := _m.Called(opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteAccessToken")
+		panic("no return value specified for CreateRepo")
 	}
 
-	var r0 *gitea.Response
+	var r0 *gitprovider.Repository
 	var r1 error
-	if rf, ok := ret.Get(0).(func(interface{}) (*gitea.Response, error)); ok {
-		return rf(value)
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateRepoOption) (*gitprovider.Repository, error)); ok {
+		return rf(opt)
 	}
-	if rf, ok := ret.Get(0).(func(interface{}) *gitea.Response); ok {
-		r0 = rf(value)
+	if rf, ok := ret.Get(0).(func(gitprovider.CreateRepoOption) *gitprovider.Repository); ok {
+		r0 = rf(opt)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Response)
+			r0 = ret.Get(0).(*gitprovider.Repository)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(interface{}) error); ok {
-		r1 = rf(value)
+	if rf, ok := ret.Get(1).(func(gitprovider.CreateRepoOption) error); ok {
+		r1 = rf(opt)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -203,57 +341,57 @@ func (_m *MockGiteaClient) DeleteAccessToken(value interface{}) (*gitea.Response
 	return r0, r1
 }
 
-// MockGiteaClient_DeleteAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccessToken'
-type MockGiteaClient_DeleteAccessToken_Call struct {
+// MockGiteaClient_CreateRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRepo'
+type MockGiteaClient_CreateRepo_Call struct {
 	*mock.Call
 }
 
-// DeleteAccessToken is a helper method to define mock.On call
-//   - value interface{}
-func (_e *MockGiteaClient_Expecter) DeleteAccessToken(value interface{}) *MockGiteaClient_DeleteAccessToken_Call {
-	return &MockGiteaClient_DeleteAccessToken_Call{Call: _e.mock.On("DeleteAccessToken", value)}
+// CreateRepo is a helper method to define mock.On call
+//   - opt gitprovider.CreateRepoOption
+func (_e *MockGiteaClient_Expecter) CreateRepo(opt interface{}) *MockGiteaClient_CreateRepo_Call {
+	return &MockGiteaClient_CreateRepo_Call{Call: _e.mock.On("CreateRepo", opt)}
 }
 
-func (_c *MockGiteaClient_DeleteAccessToken_Call) Run(run func(value interface{})) *MockGiteaClient_DeleteAccessToken_Call {
+func (_c *MockGiteaClient_CreateRepo_Call) Run(run func(opt gitprovider.CreateRepoOption)) *MockGiteaClient_CreateRepo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(interface{}))
+		run(args[0].(gitprovider.CreateRepoOption))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_DeleteAccessToken_Call) Return(_a0 *gitea.Response, _a1 error) *MockGiteaClient_DeleteAccessToken_Call {
+func (_c *MockGiteaClient_CreateRepo_Call) Return(_a0 *gitprovider.Repository, _a1 error) *MockGiteaClient_CreateRepo_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_DeleteAccessToken_Call) RunAndReturn(run func(interface{}) (*gitea.Response, error)) *MockGiteaClient_DeleteAccessToken_Call {
+func (_c *MockGiteaClient_CreateRepo_Call) RunAndReturn(run func(gitprovider.CreateRepoOption) (*gitprovider.Repository, error)) *MockGiteaClient_CreateRepo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteRepo provides a mock function with given fields: owner, repo
-func (_m *MockGiteaClient) DeleteRepo(owner string, repo string) (*gitea.Response, error) {
-	ret := _m.Called(owner, repo)
+// CreateRepoHook provides a mock function with given fields: owner, name, hook
+func (_m *MockGiteaClient) CreateRepoHook(owner string, name string, hook gitprovider.Hook) (*gitprovider.Hook, error) {
+	ret := _m.Called(owner, name, hook)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRepo")
+		panic("no return value specified for CreateRepoHook")
 	}
 
-	var r0 *gitea.Response
+	var r0 *gitprovider.Hook
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (*gitea.Response, error)); ok {
-		return rf(owner, repo)
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.Hook) (*gitprovider.Hook, error)); ok {
+		return rf(owner, name, hook)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *gitea.Response); ok {
-		r0 = rf(owner, repo)
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.Hook) *gitprovider.Hook); ok {
+		r0 = rf(owner, name, hook)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Response)
+			r0 = ret.Get(0).(*gitprovider.Hook)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(owner, repo)
+	if rf, ok := ret.Get(1).(func(string, string, gitprovider.Hook) error); ok {
+		r1 = rf(owner, name, hook)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -261,6 +399,148 @@ func (_m *MockGiteaClient) DeleteRepo(owner string, repo string) (*gitea.Respons
 	return r0, r1
 }
 
+// MockGiteaClient_CreateRepoHook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRepoHook'
+type MockGiteaClient_CreateRepoHook_Call struct {
+	*mock.Call
+}
+
+// CreateRepoHook is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - hook gitprovider.Hook
+func (_e *MockGiteaClient_Expecter) CreateRepoHook(owner interface{}, name interface{}, hook interface{}) *MockGiteaClient_CreateRepoHook_Call {
+	return &MockGiteaClient_CreateRepoHook_Call{Call: _e.mock.On("CreateRepoHook", owner, name, hook)}
+}
+
+func (_c *MockGiteaClient_CreateRepoHook_Call) Run(run func(owner string, name string, hook gitprovider.Hook)) *MockGiteaClient_CreateRepoHook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(gitprovider.Hook))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_CreateRepoHook_Call) Return(_a0 *gitprovider.Hook, _a1 error) *MockGiteaClient_CreateRepoHook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_CreateRepoHook_Call) RunAndReturn(run func(string, string, gitprovider.Hook) (*gitprovider.Hook, error)) *MockGiteaClient_CreateRepoHook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAccessToken provides a mock function with given fields: name
+func (_m *MockGiteaClient) DeleteAccessToken(name string) error {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGiteaClient_DeleteAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccessToken'
+type MockGiteaClient_DeleteAccessToken_Call struct {
+	*mock.Call
+}
+
+// DeleteAccessToken is a helper method to define mock.On call
+//   - name string
+func (_e *MockGiteaClient_Expecter) DeleteAccessToken(name interface{}) *MockGiteaClient_DeleteAccessToken_Call {
+	return &MockGiteaClient_DeleteAccessToken_Call{Call: _e.mock.On("DeleteAccessToken", name)}
+}
+
+func (_c *MockGiteaClient_DeleteAccessToken_Call) Run(run func(name string)) *MockGiteaClient_DeleteAccessToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteAccessToken_Call) Return(_a0 error) *MockGiteaClient_DeleteAccessToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteAccessToken_Call) RunAndReturn(run func(string) error) *MockGiteaClient_DeleteAccessToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDeployKey provides a mock function with given fields: owner, name, id
+func (_m *MockGiteaClient) DeleteDeployKey(owner string, name string, id int64) error {
+	ret := _m.Called(owner, name, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeployKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(owner, name, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGiteaClient_DeleteDeployKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeployKey'
+type MockGiteaClient_DeleteDeployKey_Call struct {
+	*mock.Call
+}
+
+// DeleteDeployKey is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - id int64
+func (_e *MockGiteaClient_Expecter) DeleteDeployKey(owner interface{}, name interface{}, id interface{}) *MockGiteaClient_DeleteDeployKey_Call {
+	return &MockGiteaClient_DeleteDeployKey_Call{Call: _e.mock.On("DeleteDeployKey", owner, name, id)}
+}
+
+func (_c *MockGiteaClient_DeleteDeployKey_Call) Run(run func(owner string, name string, id int64)) *MockGiteaClient_DeleteDeployKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteDeployKey_Call) Return(_a0 error) *MockGiteaClient_DeleteDeployKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteDeployKey_Call) RunAndReturn(run func(string, string, int64) error) *MockGiteaClient_DeleteDeployKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRepo provides a mock function with given fields: owner, name
+func (_m *MockGiteaClient) DeleteRepo(owner string, name string) error {
+	ret := _m.Called(owner, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRepo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(owner, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // MockGiteaClient_DeleteRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRepo'
 type MockGiteaClient_DeleteRepo_Call struct {
 	*mock.Call
@@ -268,65 +548,104 @@ type MockGiteaClient_DeleteRepo_Call struct {
 
 // DeleteRepo is a helper method to define mock.On call
 //   - owner string
-//   - repo string
-func (_e *MockGiteaClient_Expecter) DeleteRepo(owner interface{}, repo interface{}) *MockGiteaClient_DeleteRepo_Call {
-	return &MockGiteaClient_DeleteRepo_Call{Call: _e.mock.On("DeleteRepo", owner, repo)}
+//   - name string
+func (_e *MockGiteaClient_Expecter) DeleteRepo(owner interface{}, name interface{}) *MockGiteaClient_DeleteRepo_Call {
+	return &MockGiteaClient_DeleteRepo_Call{Call: _e.mock.On("DeleteRepo", owner, name)}
 }
 
-func (_c *MockGiteaClient_DeleteRepo_Call) Run(run func(owner string, repo string)) *MockGiteaClient_DeleteRepo_Call {
+func (_c *MockGiteaClient_DeleteRepo_Call) Run(run func(owner string, name string)) *MockGiteaClient_DeleteRepo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_DeleteRepo_Call) Return(_a0 *gitea.Response, _a1 error) *MockGiteaClient_DeleteRepo_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockGiteaClient_DeleteRepo_Call) Return(_a0 error) *MockGiteaClient_DeleteRepo_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockGiteaClient_DeleteRepo_Call) RunAndReturn(run func(string, string) (*gitea.Response, error)) *MockGiteaClient_DeleteRepo_Call {
+func (_c *MockGiteaClient_DeleteRepo_Call) RunAndReturn(run func(string, string) error) *MockGiteaClient_DeleteRepo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// EditRepo provides a mock function with given fields: userName, repoCRName, editRepoOption
-func (_m *MockGiteaClient) EditRepo(userName string, repoCRName string, editRepoOption gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error) {
-	ret := _m.Called(userName, repoCRName, editRepoOption)
+// DeleteRepoHook provides a mock function with given fields: owner, name, id
+func (_m *MockGiteaClient) DeleteRepoHook(owner string, name string, id int64) error {
+	ret := _m.Called(owner, name, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for EditRepo")
+		panic("no return value specified for DeleteRepoHook")
 	}
 
-	var r0 *gitea.Repository
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func(string, string, gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error)); ok {
-		return rf(userName, repoCRName, editRepoOption)
-	}
-	if rf, ok := ret.Get(0).(func(string, string, gitea.EditRepoOption) *gitea.Repository); ok {
-		r0 = rf(userName, repoCRName, editRepoOption)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(owner, name, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Repository)
-		}
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, gitea.EditRepoOption) *gitea.Response); ok {
-		r1 = rf(userName, repoCRName, editRepoOption)
+	return r0
+}
+
+// MockGiteaClient_DeleteRepoHook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRepoHook'
+type MockGiteaClient_DeleteRepoHook_Call struct {
+	*mock.Call
+}
+
+// DeleteRepoHook is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - id int64
+func (_e *MockGiteaClient_Expecter) DeleteRepoHook(owner interface{}, name interface{}, id interface{}) *MockGiteaClient_DeleteRepoHook_Call {
+	return &MockGiteaClient_DeleteRepoHook_Call{Call: _e.mock.On("DeleteRepoHook", owner, name, id)}
+}
+
+func (_c *MockGiteaClient_DeleteRepoHook_Call) Run(run func(owner string, name string, id int64)) *MockGiteaClient_DeleteRepoHook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteRepoHook_Call) Return(_a0 error) *MockGiteaClient_DeleteRepoHook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGiteaClient_DeleteRepoHook_Call) RunAndReturn(run func(string, string, int64) error) *MockGiteaClient_DeleteRepoHook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EditRepo provides a mock function with given fields: owner, name, opt
+func (_m *MockGiteaClient) EditRepo(owner string, name string, opt gitprovider.EditRepoOption) (*gitprovider.Repository, error) {
+	ret := _m.Called(owner, name, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditRepo")
+	}
+
+	var r0 *gitprovider.Repository
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.EditRepoOption) (*gitprovider.Repository, error)); ok {
+		return rf(owner, name, opt)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.EditRepoOption) *gitprovider.Repository); ok {
+		r0 = rf(owner, name, opt)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitprovider.Repository)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(string, string, gitea.EditRepoOption) error); ok {
-		r2 = rf(userName, repoCRName, editRepoOption)
+	if rf, ok := ret.Get(1).(func(string, string, gitprovider.EditRepoOption) error); ok {
+		r1 = rf(owner, name, opt)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // MockGiteaClient_EditRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditRepo'
@@ -335,114 +654,166 @@ type MockGiteaClient_EditRepo_Call struct {
 }
 
 // EditRepo is a helper method to define mock.On call
-//   - userName string
-//   - repoCRName string
-//   - editRepoOption gitea.EditRepoOption
-func (_e *MockGiteaClient_Expecter) EditRepo(userName interface{}, repoCRName interface{}, editRepoOption interface{}) *MockGiteaClient_EditRepo_Call {
-	return &MockGiteaClient_EditRepo_Call{Call: _e.mock.On("EditRepo", userName, repoCRName, editRepoOption)}
+//   - owner string
+//   - name string
+//   - opt gitprovider.EditRepoOption
+func (_e *MockGiteaClient_Expecter) EditRepo(owner interface{}, name interface{}, opt interface{}) *MockGiteaClient_EditRepo_Call {
+	return &MockGiteaClient_EditRepo_Call{Call: _e.mock.On("EditRepo", owner, name, opt)}
 }
 
-func (_c *MockGiteaClient_EditRepo_Call) Run(run func(userName string, repoCRName string, editRepoOption gitea.EditRepoOption)) *MockGiteaClient_EditRepo_Call {
+func (_c *MockGiteaClient_EditRepo_Call) Run(run func(owner string, name string, opt gitprovider.EditRepoOption)) *MockGiteaClient_EditRepo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string), args[2].(gitea.EditRepoOption))
+		run(args[0].(string), args[1].(string), args[2].(gitprovider.EditRepoOption))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_EditRepo_Call) Return(_a0 *gitea.Repository, _a1 *gitea.Response, _a2 error) *MockGiteaClient_EditRepo_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_EditRepo_Call) Return(_a0 *gitprovider.Repository, _a1 error) *MockGiteaClient_EditRepo_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_EditRepo_Call) RunAndReturn(run func(string, string, gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error)) *MockGiteaClient_EditRepo_Call {
+func (_c *MockGiteaClient_EditRepo_Call) RunAndReturn(run func(string, string, gitprovider.EditRepoOption) (*gitprovider.Repository, error)) *MockGiteaClient_EditRepo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Get provides a mock function with given fields:
-func (_m *MockGiteaClient) Get() *gitea.Client {
-	ret := _m.Called()
+// EditRepoHook provides a mock function with given fields: owner, name, hook
+func (_m *MockGiteaClient) EditRepoHook(owner string, name string, hook gitprovider.Hook) error {
+	ret := _m.Called(owner, name, hook)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for EditRepoHook")
 	}
 
-	var r0 *gitea.Client
-	if rf, ok := ret.Get(0).(func() *gitea.Client); ok {
-		r0 = rf()
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.Hook) error); ok {
+		r0 = rf(owner, name, hook)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Client)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// MockGiteaClient_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockGiteaClient_Get_Call struct {
+// MockGiteaClient_EditRepoHook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditRepoHook'
+type MockGiteaClient_EditRepoHook_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
-func (_e *MockGiteaClient_Expecter) Get() *MockGiteaClient_Get_Call {
-	return &MockGiteaClient_Get_Call{Call: _e.mock.On("Get")}
+// EditRepoHook is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - hook gitprovider.Hook
+func (_e *MockGiteaClient_Expecter) EditRepoHook(owner interface{}, name interface{}, hook interface{}) *MockGiteaClient_EditRepoHook_Call {
+	return &MockGiteaClient_EditRepoHook_Call{Call: _e.mock.On("EditRepoHook", owner, name, hook)}
 }
 
-func (_c *MockGiteaClient_Get_Call) Run(run func()) *MockGiteaClient_Get_Call {
+func (_c *MockGiteaClient_EditRepoHook_Call) Run(run func(owner string, name string, hook gitprovider.Hook)) *MockGiteaClient_EditRepoHook_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(string), args[1].(string), args[2].(gitprovider.Hook))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_Get_Call) Return(_a0 *gitea.Client) *MockGiteaClient_Get_Call {
+func (_c *MockGiteaClient_EditRepoHook_Call) Return(_a0 error) *MockGiteaClient_EditRepoHook_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockGiteaClient_Get_Call) RunAndReturn(run func() *gitea.Client) *MockGiteaClient_Get_Call {
+func (_c *MockGiteaClient_EditRepoHook_Call) RunAndReturn(run func(string, string, gitprovider.Hook) error) *MockGiteaClient_EditRepoHook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBranchProtection provides a mock function with given fields: owner, name, branch
+func (_m *MockGiteaClient) GetBranchProtection(owner string, name string, branch string) (*gitprovider.BranchProtection, error) {
+	ret := _m.Called(owner, name, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBranchProtection")
+	}
+
+	var r0 *gitprovider.BranchProtection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*gitprovider.BranchProtection, error)); ok {
+		return rf(owner, name, branch)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *gitprovider.BranchProtection); ok {
+		r0 = rf(owner, name, branch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitprovider.BranchProtection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(owner, name, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGiteaClient_GetBranchProtection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBranchProtection'
+type MockGiteaClient_GetBranchProtection_Call struct {
+	*mock.Call
+}
+
+// GetBranchProtection is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - branch string
+func (_e *MockGiteaClient_Expecter) GetBranchProtection(owner interface{}, name interface{}, branch interface{}) *MockGiteaClient_GetBranchProtection_Call {
+	return &MockGiteaClient_GetBranchProtection_Call{Call: _e.mock.On("GetBranchProtection", owner, name, branch)}
+}
+
+func (_c *MockGiteaClient_GetBranchProtection_Call) Run(run func(owner string, name string, branch string)) *MockGiteaClient_GetBranchProtection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_GetBranchProtection_Call) Return(_a0 *gitprovider.BranchProtection, _a1 error) *MockGiteaClient_GetBranchProtection_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_GetBranchProtection_Call) RunAndReturn(run func(string, string, string) (*gitprovider.BranchProtection, error)) *MockGiteaClient_GetBranchProtection_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // GetMyUserInfo provides a mock function with given fields:
-func (_m *MockGiteaClient) GetMyUserInfo() (*gitea.User, *gitea.Response, error) {
+func (_m *MockGiteaClient) GetMyUserInfo() (*gitprovider.User, error) {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMyUserInfo")
 	}
 
-	var r0 *gitea.User
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func() (*gitea.User, *gitea.Response, error)); ok {
+	var r0 *gitprovider.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*gitprovider.User, error)); ok {
 		return rf()
 	}
-	if rf, ok := ret.Get(0).(func() *gitea.User); ok {
+	if rf, ok := ret.Get(0).(func() *gitprovider.User); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.User)
+			r0 = ret.Get(0).(*gitprovider.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() *gitea.Response); ok {
+	if rf, ok := ret.Get(1).(func() error); ok {
 		r1 = rf()
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
-		}
-	}
-
-	if rf, ok := ret.Get(2).(func() error); ok {
-		r2 = rf()
-	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // MockGiteaClient_GetMyUserInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMyUserInfo'
@@ -462,53 +833,102 @@ func (_c *MockGiteaClient_GetMyUserInfo_Call) Run(run func()) *MockGiteaClient_G
 	return _c
 }
 
-func (_c *MockGiteaClient_GetMyUserInfo_Call) Return(_a0 *gitea.User, _a1 *gitea.Response, _a2 error) *MockGiteaClient_GetMyUserInfo_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_GetMyUserInfo_Call) Return(_a0 *gitprovider.User, _a1 error) *MockGiteaClient_GetMyUserInfo_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_GetMyUserInfo_Call) RunAndReturn(run func() (*gitea.User, *gitea.Response, error)) *MockGiteaClient_GetMyUserInfo_Call {
+func (_c *MockGiteaClient_GetMyUserInfo_Call) RunAndReturn(run func() (*gitprovider.User, error)) *MockGiteaClient_GetMyUserInfo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRepo provides a mock function with given fields: userName, repoCRName
-func (_m *MockGiteaClient) GetRepo(userName string, repoCRName string) (*gitea.Repository, *gitea.Response, error) {
-	ret := _m.Called(userName, repoCRName)
+// GetOrg provides a mock function with given fields: name
+func (_m *MockGiteaClient) GetOrg(name string) (*gitprovider.Organization, error) {
+	ret := _m.Called(name)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRepo")
+		panic("no return value specified for GetOrg")
 	}
 
-	var r0 *gitea.Repository
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func(string, string) (*gitea.Repository, *gitea.Response, error)); ok {
-		return rf(userName, repoCRName)
+	var r0 *gitprovider.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*gitprovider.Organization, error)); ok {
+		return rf(name)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *gitea.Repository); ok {
-		r0 = rf(userName, repoCRName)
+	if rf, ok := ret.Get(0).(func(string) *gitprovider.Organization); ok {
+		r0 = rf(name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gitea.Repository)
+			r0 = ret.Get(0).(*gitprovider.Organization)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) *gitea.Response); ok {
-		r1 = rf(userName, repoCRName)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGiteaClient_GetOrg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrg'
+type MockGiteaClient_GetOrg_Call struct {
+	*mock.Call
+}
+
+// GetOrg is a helper method to define mock.On call
+//   - name string
+func (_e *MockGiteaClient_Expecter) GetOrg(name interface{}) *MockGiteaClient_GetOrg_Call {
+	return &MockGiteaClient_GetOrg_Call{Call: _e.mock.On("GetOrg", name)}
+}
+
+func (_c *MockGiteaClient_GetOrg_Call) Run(run func(name string)) *MockGiteaClient_GetOrg_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_GetOrg_Call) Return(_a0 *gitprovider.Organization, _a1 error) *MockGiteaClient_GetOrg_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_GetOrg_Call) RunAndReturn(run func(string) (*gitprovider.Organization, error)) *MockGiteaClient_GetOrg_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRepo provides a mock function with given fields: owner, name
+func (_m *MockGiteaClient) GetRepo(owner string, name string) (*gitprovider.Repository, error) {
+	ret := _m.Called(owner, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepo")
+	}
+
+	var r0 *gitprovider.Repository
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*gitprovider.Repository, error)); ok {
+		return rf(owner, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *gitprovider.Repository); ok {
+		r0 = rf(owner, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitprovider.Repository)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(string, string) error); ok {
-		r2 = rf(userName, repoCRName)
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(owner, name)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // MockGiteaClient_GetRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRepo'
@@ -517,25 +937,25 @@ type MockGiteaClient_GetRepo_Call struct {
 }
 
 // GetRepo is a helper method to define mock.On call
-//   - userName string
-//   - repoCRName string
-func (_e *MockGiteaClient_Expecter) GetRepo(userName interface{}, repoCRName interface{}) *MockGiteaClient_GetRepo_Call {
-	return &MockGiteaClient_GetRepo_Call{Call: _e.mock.On("GetRepo", userName, repoCRName)}
+//   - owner string
+//   - name string
+func (_e *MockGiteaClient_Expecter) GetRepo(owner interface{}, name interface{}) *MockGiteaClient_GetRepo_Call {
+	return &MockGiteaClient_GetRepo_Call{Call: _e.mock.On("GetRepo", owner, name)}
 }
 
-func (_c *MockGiteaClient_GetRepo_Call) Run(run func(userName string, repoCRName string)) *MockGiteaClient_GetRepo_Call {
+func (_c *MockGiteaClient_GetRepo_Call) Run(run func(owner string, name string)) *MockGiteaClient_GetRepo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_GetRepo_Call) Return(_a0 *gitea.Repository, _a1 *gitea.Response, _a2 error) *MockGiteaClient_GetRepo_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_GetRepo_Call) Return(_a0 *gitprovider.Repository, _a1 error) *MockGiteaClient_GetRepo_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_GetRepo_Call) RunAndReturn(run func(string, string) (*gitea.Repository, *gitea.Response, error)) *MockGiteaClient_GetRepo_Call {
+func (_c *MockGiteaClient_GetRepo_Call) RunAndReturn(run func(string, string) (*gitprovider.Repository, error)) *MockGiteaClient_GetRepo_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -585,102 +1005,225 @@ func (_c *MockGiteaClient_IsInitialized_Call) RunAndReturn(run func() bool) *Moc
 	return _c
 }
 
-// ListAccessTokens provides a mock function with given fields: opts
-func (_m *MockGiteaClient) ListAccessTokens(opts gitea.ListAccessTokensOptions) ([]*gitea.AccessToken, *gitea.Response, error) {
-	ret := _m.Called(opts)
+// ListAccessTokens provides a mock function with given fields:
+func (_m *MockGiteaClient) ListAccessTokens() ([]*gitprovider.AccessToken, error) {
+	ret := _m.Called()
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListAccessTokens")
 	}
 
-	var r0 []*gitea.AccessToken
-	var r1 *gitea.Response
-	var r2 error
-	if rf, ok := ret.Get(0).(func(gitea.ListAccessTokensOptions) ([]*gitea.AccessToken, *gitea.Response, error)); ok {
-		return rf(opts)
+	var r0 []*gitprovider.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*gitprovider.AccessToken, error)); ok {
+		return rf()
 	}
-	if rf, ok := ret.Get(0).(func(gitea.ListAccessTokensOptions) []*gitea.AccessToken); ok {
-		r0 = rf(opts)
+	if rf, ok := ret.Get(0).(func() []*gitprovider.AccessToken); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*gitea.AccessToken)
+			r0 = ret.Get(0).([]*gitprovider.AccessToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(gitea.ListAccessTokensOptions) *gitea.Response); ok {
-		r1 = rf(opts)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGiteaClient_ListAccessTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccessTokens'
+type MockGiteaClient_ListAccessTokens_Call struct {
+	*mock.Call
+}
+
+// ListAccessTokens is a helper method to define mock.On call
+func (_e *MockGiteaClient_Expecter) ListAccessTokens() *MockGiteaClient_ListAccessTokens_Call {
+	return &MockGiteaClient_ListAccessTokens_Call{Call: _e.mock.On("ListAccessTokens")}
+}
+
+func (_c *MockGiteaClient_ListAccessTokens_Call) Run(run func()) *MockGiteaClient_ListAccessTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_ListAccessTokens_Call) Return(_a0 []*gitprovider.AccessToken, _a1 error) *MockGiteaClient_ListAccessTokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_ListAccessTokens_Call) RunAndReturn(run func() ([]*gitprovider.AccessToken, error)) *MockGiteaClient_ListAccessTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeployKeys provides a mock function with given fields: owner, name
+func (_m *MockGiteaClient) ListDeployKeys(owner string, name string) ([]*gitprovider.DeployKey, error) {
+	ret := _m.Called(owner, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeployKeys")
+	}
+
+	var r0 []*gitprovider.DeployKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]*gitprovider.DeployKey, error)); ok {
+		return rf(owner, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []*gitprovider.DeployKey); ok {
+		r0 = rf(owner, name)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*gitea.Response)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gitprovider.DeployKey)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(gitea.ListAccessTokensOptions) error); ok {
-		r2 = rf(opts)
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(owner, name)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// MockGiteaClient_ListAccessTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccessTokens'
-type MockGiteaClient_ListAccessTokens_Call struct {
+// MockGiteaClient_ListDeployKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeployKeys'
+type MockGiteaClient_ListDeployKeys_Call struct {
 	*mock.Call
 }
 
-// ListAccessTokens is a helper method to define mock.On call
-//   - opts gitea.ListAccessTokensOptions
-func (_e *MockGiteaClient_Expecter) ListAccessTokens(opts interface{}) *MockGiteaClient_ListAccessTokens_Call {
-	return &MockGiteaClient_ListAccessTokens_Call{Call: _e.mock.On("ListAccessTokens", opts)}
+// ListDeployKeys is a helper method to define mock.On call
+//   - owner string
+//   - name string
+func (_e *MockGiteaClient_Expecter) ListDeployKeys(owner interface{}, name interface{}) *MockGiteaClient_ListDeployKeys_Call {
+	return &MockGiteaClient_ListDeployKeys_Call{Call: _e.mock.On("ListDeployKeys", owner, name)}
 }
 
-func (_c *MockGiteaClient_ListAccessTokens_Call) Run(run func(opts gitea.ListAccessTokensOptions)) *MockGiteaClient_ListAccessTokens_Call {
+func (_c *MockGiteaClient_ListDeployKeys_Call) Run(run func(owner string, name string)) *MockGiteaClient_ListDeployKeys_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(gitea.ListAccessTokensOptions))
+		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_ListAccessTokens_Call) Return(_a0 []*gitea.AccessToken, _a1 *gitea.Response, _a2 error) *MockGiteaClient_ListAccessTokens_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *MockGiteaClient_ListDeployKeys_Call) Return(_a0 []*gitprovider.DeployKey, _a1 error) *MockGiteaClient_ListDeployKeys_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockGiteaClient_ListAccessTokens_Call) RunAndReturn(run func(gitea.ListAccessTokensOptions) ([]*gitea.AccessToken, *gitea.Response, error)) *MockGiteaClient_ListAccessTokens_Call {
+func (_c *MockGiteaClient_ListDeployKeys_Call) RunAndReturn(run func(string, string) ([]*gitprovider.DeployKey, error)) *MockGiteaClient_ListDeployKeys_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Start provides a mock function with given fields: ctx
-func (_m *MockGiteaClient) Start(ctx context.Context) {
-	_m.Called(ctx)
+// ListRepoHooks provides a mock function with given fields: owner, name
+func (_m *MockGiteaClient) ListRepoHooks(owner string, name string) ([]*gitprovider.Hook, error) {
+	ret := _m.Called(owner, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRepoHooks")
+	}
+
+	var r0 []*gitprovider.Hook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]*gitprovider.Hook, error)); ok {
+		return rf(owner, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []*gitprovider.Hook); ok {
+		r0 = rf(owner, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gitprovider.Hook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(owner, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockGiteaClient_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
-type MockGiteaClient_Start_Call struct {
+// MockGiteaClient_ListRepoHooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRepoHooks'
+type MockGiteaClient_ListRepoHooks_Call struct {
 	*mock.Call
 }
 
-// Start is a helper method to define mock.On call
-//   - ctx context.Context
-func (_e *MockGiteaClient_Expecter) Start(ctx interface{}) *MockGiteaClient_Start_Call {
-	return &MockGiteaClient_Start_Call{Call: _e.mock.On("Start", ctx)}
+// ListRepoHooks is a helper method to define mock.On call
+//   - owner string
+//   - name string
+func (_e *MockGiteaClient_Expecter) ListRepoHooks(owner interface{}, name interface{}) *MockGiteaClient_ListRepoHooks_Call {
+	return &MockGiteaClient_ListRepoHooks_Call{Call: _e.mock.On("ListRepoHooks", owner, name)}
 }
 
-func (_c *MockGiteaClient_Start_Call) Run(run func(ctx context.Context)) *MockGiteaClient_Start_Call {
+func (_c *MockGiteaClient_ListRepoHooks_Call) Run(run func(owner string, name string)) *MockGiteaClient_ListRepoHooks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockGiteaClient_Start_Call) Return() *MockGiteaClient_Start_Call {
-	_c.Call.Return()
+func (_c *MockGiteaClient_ListRepoHooks_Call) Return(_a0 []*gitprovider.Hook, _a1 error) *MockGiteaClient_ListRepoHooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGiteaClient_ListRepoHooks_Call) RunAndReturn(run func(string, string) ([]*gitprovider.Hook, error)) *MockGiteaClient_ListRepoHooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetBranchProtection provides a mock function with given fields: owner, name, protection
+func (_m *MockGiteaClient) SetBranchProtection(owner string, name string, protection gitprovider.BranchProtection) error {
+	ret := _m.Called(owner, name, protection)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBranchProtection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, gitprovider.BranchProtection) error); ok {
+		r0 = rf(owner, name, protection)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGiteaClient_SetBranchProtection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetBranchProtection'
+type MockGiteaClient_SetBranchProtection_Call struct {
+	*mock.Call
+}
+
+// SetBranchProtection is a helper method to define mock.On call
+//   - owner string
+//   - name string
+//   - protection gitprovider.BranchProtection
+func (_e *MockGiteaClient_Expecter) SetBranchProtection(owner interface{}, name interface{}, protection interface{}) *MockGiteaClient_SetBranchProtection_Call {
+	return &MockGiteaClient_SetBranchProtection_Call{Call: _e.mock.On("SetBranchProtection", owner, name, protection)}
+}
+
+func (_c *MockGiteaClient_SetBranchProtection_Call) Run(run func(owner string, name string, protection gitprovider.BranchProtection)) *MockGiteaClient_SetBranchProtection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(gitprovider.BranchProtection))
+	})
+	return _c
+}
+
+func (_c *MockGiteaClient_SetBranchProtection_Call) Return(_a0 error) *MockGiteaClient_SetBranchProtection_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockGiteaClient_Start_Call) RunAndReturn(run func(context.Context)) *MockGiteaClient_Start_Call {
+func (_c *MockGiteaClient_SetBranchProtection_Call) RunAndReturn(run func(string, string, gitprovider.BranchProtection) error) *MockGiteaClient_SetBranchProtection_Call {
 	_c.Call.Return(run)
 	return _c
 }
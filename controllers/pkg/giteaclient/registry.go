@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package giteaclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	gitv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/git/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// GitServerAnnotation selects, on a Repository or Token, the GitServer it is
+// reconciled against. Without it the default git server is used.
+const GitServerAnnotation = "infra.nephio.org/git-server"
+
+// Registry holds the clients of the git servers: the default git server,
+// configured with environment variables, and the git servers of the
+// GitServer resources. The client of a GitServer is rebuilt when the
+// GitServer or its credentials Secret changes.
+type Registry struct {
+	client        client.Reader
+	defaultClient GiteaClient
+	newProvider   func(name string, cfg gitprovider.Config) (gitprovider.GitProvider, error)
+
+	m       sync.Mutex
+	clients map[string]*serverClient
+}
+
+// serverClient is the client of a GitServer, built from the versions of the
+// GitServer and its Secret
+type serverClient struct {
+	version string
+	client  GiteaClient
+}
+
+// NewRegistry returns a Registry reading the GitServers and their Secrets with
+// the client
+func NewRegistry(c client.Reader, defaultClient GiteaClient) *Registry {
+	return &Registry{
+		client:        c,
+		defaultClient: defaultClient,
+		newProvider:   gitprovider.New,
+		clients:       map[string]*serverClient{},
+	}
+}
+
+// ClientFor returns the client of the git server selected by the object with
+// the GitServerAnnotation, or the default client if the object has no
+// annotation. The GitServer must allow the namespace of the object.
+func (r *Registry) ClientFor(ctx context.Context, obj client.Object) (GiteaClient, error) {
+	name := obj.GetAnnotations()[GitServerAnnotation]
+	if name == "" {
+		if r.defaultClient == nil {
+			return nil, fmt.Errorf("no default git server configured, select a GitServer with %s", GitServerAnnotation)
+		}
+		return r.defaultClient, nil
+	}
+	server := &gitv1alpha1.GitServer{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, server); err != nil {
+		return nil, fmt.Errorf("cannot get GitServer %s: %s", name, err.Error())
+	}
+	if !server.AllowsNamespace(obj.GetNamespace()) {
+		return nil, fmt.Errorf("GitServer %s does not allow namespace %s", name, obj.GetNamespace())
+	}
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: server.Spec.SecretRef.Namespace,
+		Name:      server.Spec.SecretRef.Name,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("cannot get secret of GitServer %s: %s", name, err.Error())
+	}
+	version := fmt.Sprintf("%s/%d/%s", server.GetUID(), server.GetGeneration(), secret.GetResourceVersion())
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if c, ok := r.clients[name]; ok && c.version == version {
+		return c.client, nil
+	}
+	providerName := server.Spec.Provider
+	if providerName == "" {
+		providerName = gitprovider.ProviderGitea
+	}
+	provider, err := r.newProvider(providerName, getProviderConfig(server.Spec.URL, secret))
+	if err != nil {
+		return nil, fmt.Errorf("cannot authenticate to GitServer %s: %s", name, err.Error())
+	}
+	_, rebuilt := r.clients[name]
+	r.clients[name] = &serverClient{version: version, client: &gc{provider: provider}}
+	log.FromContext(ctx).Info("git client init done", "gitServer", name, "provider", providerName, "rebuilt", rebuilt)
+	return r.clients[name].client, nil
+}
+
+// EvictDeleted returns an event handler dropping the client of a GitServer
+// when the GitServer is deleted, so the registry does not keep the clients
+// of the git servers that are gone
+func (r *Registry) EvictDeleted() handler.EventHandler {
+	return handler.Funcs{
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.m.Lock()
+			defer r.m.Unlock()
+			if _, ok := r.clients[e.Object.GetName()]; ok {
+				delete(r.clients, e.Object.GetName())
+				log.FromContext(ctx).Info("git client removed", "gitServer", e.Object.GetName())
+			}
+		},
+	}
+}
+
+// EnqueueSelecting returns a map function that maps a GitServer, or the Secret
+// of a GitServer, to the requests of the objects of the list kind that select
+// the GitServer, so they are reconciled with the rebuilt client
+func EnqueueSelecting(c client.Reader, newList func() client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		servers := sets.New[string]()
+		switch obj := obj.(type) {
+		case *gitv1alpha1.GitServer:
+			servers.Insert(obj.GetName())
+		case *corev1.Secret:
+			list := &gitv1alpha1.GitServerList{}
+			if err := c.List(ctx, list); err != nil {
+				log.FromContext(ctx).Error(err, "cannot list GitServers")
+				return nil
+			}
+			for _, server := range list.Items {
+				if server.Spec.SecretRef.Namespace == obj.GetNamespace() && server.Spec.SecretRef.Name == obj.GetName() {
+					servers.Insert(server.GetName())
+				}
+			}
+		}
+		if servers.Len() == 0 {
+			return nil
+		}
+		list := newList()
+		if err := c.List(ctx, list); err != nil {
+			log.FromContext(ctx).Error(err, "cannot list objects selecting GitServers")
+			return nil
+		}
+		var requests []reconcile.Request
+		_ = meta.EachListItem(list, func(o runtime.Object) error {
+			if obj, ok := o.(client.Object); ok && servers.Has(obj.GetAnnotations()[GitServerAnnotation]) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+			return nil
+		})
+		return requests
+	}
+}
+
+// EnqueueDefault returns a map function that maps any object to the requests
+// of the objects of the list kind that use the default git server, to
+// reconcile them when the default client becomes ready
+func EnqueueDefault(c client.Reader, newList func() client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, _ client.Object) []reconcile.Request {
+		list := newList()
+		if err := c.List(ctx, list); err != nil {
+			log.FromContext(ctx).Error(err, "cannot list objects using the default git server")
+			return nil
+		}
+		var requests []reconcile.Request
+		_ = meta.EachListItem(list, func(o runtime.Object) error {
+			if obj, ok := o.(client.Object); ok && obj.GetAnnotations()[GitServerAnnotation] == "" {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+			return nil
+		})
+		return requests
+	}
+}
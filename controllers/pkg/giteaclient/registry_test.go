@@ -0,0 +1,157 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package giteaclient
+
+import (
+	"context"
+	"testing"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	gitv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/git/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, gitv1alpha1.AddToScheme(scheme))
+	require.NoError(t, infrav1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	server := &gitv1alpha1.GitServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "region-a"},
+		Spec: gitv1alpha1.GitServerSpec{
+			URL:       "https://git.region-a.example.com",
+			Provider:  gitprovider.ProviderGitLab,
+			SecretRef: corev1.SecretReference{Namespace: "nephio-system", Name: "region-a"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "nephio-system", Name: "region-a"},
+		Data:       map[string][]byte{"token": []byte("t1")},
+	}
+	c := newTestClient(t, server, secret)
+	defaultClient := &gc{provider: gitprovider.NewFake("nephio")}
+	r := NewRegistry(c, defaultClient)
+	var configs []gitprovider.Config
+	r.newProvider = func(name string, cfg gitprovider.Config) (gitprovider.GitProvider, error) {
+		require.Equal(t, gitprovider.ProviderGitLab, name)
+		configs = append(configs, cfg)
+		return gitprovider.NewFake("region-a"), nil
+	}
+
+	got, err := r.ClientFor(ctx, &infrav1alpha1.Repository{})
+	require.NoError(t, err)
+	require.Same(t, defaultClient, got)
+
+	repo := &infrav1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "nephio-system",
+		Annotations: map[string]string{GitServerAnnotation: "region-a"},
+	}}
+	first, err := r.ClientFor(ctx, repo)
+	require.NoError(t, err)
+	require.True(t, first.IsInitialized())
+	u, err := first.GetMyUserInfo()
+	require.NoError(t, err)
+	require.Equal(t, "region-a", u.UserName)
+	require.Equal(t, []gitprovider.Config{{URL: server.Spec.URL, Token: "t1"}}, configs)
+
+	// the client is reused until the credentials change
+	got, err = r.ClientFor(ctx, repo)
+	require.NoError(t, err)
+	require.Same(t, first, got)
+
+	secret.Data["token"] = []byte("t2")
+	require.NoError(t, c.Update(ctx, secret))
+	got, err = r.ClientFor(ctx, repo)
+	require.NoError(t, err)
+	require.NotSame(t, first, got)
+	require.Len(t, configs, 2)
+	require.Equal(t, "t2", configs[1].Token)
+
+	// only the namespace of the secret is allowed by default
+	other := repo.DeepCopy()
+	other.Namespace = "tenant-a"
+	_, err = r.ClientFor(ctx, other)
+	require.ErrorContains(t, err, "tenant-a")
+	server.Spec.AllowedNamespaces = []string{"tenant-a"}
+	require.NoError(t, c.Update(ctx, server))
+	_, err = r.ClientFor(ctx, other)
+	require.NoError(t, err)
+	_, err = r.ClientFor(ctx, repo)
+	require.ErrorContains(t, err, "nephio-system")
+	server.Spec.AllowedNamespaces = []string{"*"}
+	require.NoError(t, c.Update(ctx, server))
+	_, err = r.ClientFor(ctx, repo)
+	require.NoError(t, err)
+
+	// the client of a deleted GitServer is dropped
+	require.Len(t, r.clients, 1)
+	r.EvictDeleted().Delete(ctx, event.DeleteEvent{Object: server}, nil)
+	require.Empty(t, r.clients)
+
+	other.Annotations[GitServerAnnotation] = "region-b"
+	_, err = r.ClientFor(ctx, other)
+	require.ErrorContains(t, err, "region-b")
+	_, err = NewRegistry(c, nil).ClientFor(ctx, &infrav1alpha1.Repository{})
+	require.ErrorContains(t, err, GitServerAnnotation)
+}
+
+func TestEnqueueSelecting(t *testing.T) {
+	ctx := context.Background()
+	server := &gitv1alpha1.GitServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "region-a"},
+		Spec: gitv1alpha1.GitServerSpec{
+			SecretRef: corev1.SecretReference{Namespace: "nephio-system", Name: "region-a"},
+		},
+	}
+	selecting := &infrav1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "edge",
+		Annotations: map[string]string{GitServerAnnotation: "region-a"},
+	}}
+	other := &infrav1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt"}}
+	c := newTestClient(t, server, selecting, other)
+	mapFunc := EnqueueSelecting(c, func() client.ObjectList { return &infrav1alpha1.RepositoryList{} })
+	want := []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(selecting)}}
+
+	require.Equal(t, want, mapFunc(ctx, server))
+	require.Equal(t, want, mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "nephio-system", Name: "region-a"}}))
+	require.Empty(t, mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "region-a"}}))
+}
+
+func TestEnqueueDefault(t *testing.T) {
+	selecting := &infrav1alpha1.Token{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "edge",
+		Annotations: map[string]string{GitServerAnnotation: "region-a"},
+	}}
+	other := &infrav1alpha1.Token{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt"}}
+	c := newTestClient(t, selecting, other)
+	mapFunc := EnqueueDefault(c, func() client.ObjectList { return &infrav1alpha1.TokenList{} })
+	require.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(other)}}, mapFunc(context.Background(), nil))
+}
@@ -0,0 +1,419 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+const fakeURL = "http://git.fake"
+
+// Fake is an in-memory GitProvider for tests. Repositories are owned by the
+// fake user or by the organizations created in the fake.
+type Fake struct {
+	mu     sync.Mutex
+	user   User
+	orgs   map[string]*fakeOrg
+	repos  map[string]*fakeRepo
+	tokens map[string]*AccessToken
+	nextID int64
+}
+
+type fakeOrg struct {
+	Organization
+	teams map[string]bool
+}
+
+type fakeRepo struct {
+	Repository
+	teams         map[string]Permission
+	collaborators map[string]Permission
+	protections   map[string]BranchProtection
+	hooks         map[int64]Hook
+	deployKeys    map[int64]DeployKey
+}
+
+var _ GitProvider = &Fake{}
+
+func NewFake(userName string) *Fake {
+	return &Fake{
+		user:   User{ID: 1, UserName: userName},
+		orgs:   map[string]*fakeOrg{},
+		repos:  map[string]*fakeRepo{},
+		tokens: map[string]*AccessToken{},
+		nextID: 1,
+	}
+}
+
+func (r *Fake) GetMyUserInfo() (*User, error) {
+	u := r.user
+	return &u, nil
+}
+
+func (r *Fake) GetOrg(name string) (*Organization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	org, ok := r.orgs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: organization %s", ErrNotFound, name)
+	}
+	result := org.Organization
+	return &result, nil
+}
+
+func (r *Fake) CreateOrg(opt CreateOrgOption) (*Organization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.orgs[opt.Name]; ok {
+		return nil, fmt.Errorf("%w: organization %s", ErrAlreadyExists, opt.Name)
+	}
+	r.nextID++
+	org := &fakeOrg{
+		Organization: Organization{ID: r.nextID, Name: opt.Name, Description: opt.Description},
+		teams:        map[string]bool{},
+	}
+	r.orgs[opt.Name] = org
+	result := org.Organization
+	return &result, nil
+}
+
+// AddTeam creates a team in an organization of the fake
+func (r *Fake) AddTeam(org string, team string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.orgs[org]
+	if !ok {
+		return fmt.Errorf("%w: organization %s", ErrNotFound, org)
+	}
+	o.teams[team] = true
+	return nil
+}
+
+func (r *Fake) GetRepo(owner string, name string) (*Repository, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	result := repo.Repository
+	return &result, nil
+}
+
+func (r *Fake) CreateRepo(opt CreateRepoOption) (*Repository, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	owner := r.user.UserName
+	if opt.Owner != "" {
+		if _, ok := r.orgs[opt.Owner]; !ok {
+			return nil, fmt.Errorf("%w: organization %s", ErrNotFound, opt.Owner)
+		}
+		owner = opt.Owner
+	}
+	key := owner + "/" + opt.Name
+	if _, ok := r.repos[key]; ok {
+		return nil, fmt.Errorf("%w: repository %s", ErrAlreadyExists, key)
+	}
+	r.nextID++
+	repo := &fakeRepo{
+		Repository: Repository{
+			ID:            r.nextID,
+			Owner:         owner,
+			Name:          opt.Name,
+			Description:   opt.Description,
+			Private:       opt.Private,
+			DefaultBranch: opt.DefaultBranch,
+		},
+		teams:         map[string]Permission{},
+		collaborators: map[string]Permission{},
+		protections:   map[string]BranchProtection{},
+		hooks:         map[int64]Hook{},
+		deployKeys:    map[int64]DeployKey{},
+	}
+	setFakeURLs(&repo.Repository)
+	r.repos[key] = repo
+	result := repo.Repository
+	return &result, nil
+}
+
+func (r *Fake) EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	if opt.Description != nil {
+		repo.Description = *opt.Description
+	}
+	if opt.Private != nil {
+		repo.Private = *opt.Private
+	}
+	if opt.Name != nil && *opt.Name != name {
+		delete(r.repos, owner+"/"+name)
+		repo.Name = *opt.Name
+		setFakeURLs(&repo.Repository)
+		r.repos[owner+"/"+repo.Name] = repo
+	}
+	result := repo.Repository
+	return &result, nil
+}
+
+func (r *Fake) DeleteRepo(owner string, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.repos[owner+"/"+name]; !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	delete(r.repos, owner+"/"+name)
+	return nil
+}
+
+func (r *Fake) AddRepoTeam(owner string, name string, team string, permission Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	if org, ok := r.orgs[owner]; !ok || !org.teams[team] {
+		return fmt.Errorf("%w: team %s/%s", ErrNotFound, owner, team)
+	}
+	repo.teams[team] = permission
+	return nil
+}
+
+func (r *Fake) AddRepoCollaborator(owner string, name string, user string, permission Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	repo.collaborators[user] = permission
+	return nil
+}
+
+// RepoTeams returns the permissions of the teams with access to a repository
+func (r *Fake) RepoTeams(owner string, name string) map[string]Permission {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if repo, ok := r.repos[owner+"/"+name]; ok {
+		return maps.Clone(repo.teams)
+	}
+	return nil
+}
+
+// RepoCollaborators returns the permissions of the collaborators of a
+// repository
+func (r *Fake) RepoCollaborators(owner string, name string) map[string]Permission {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if repo, ok := r.repos[owner+"/"+name]; ok {
+		return maps.Clone(repo.collaborators)
+	}
+	return nil
+}
+
+func (r *Fake) GetBranchProtection(owner string, name string, branch string) (*BranchProtection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	protection, ok := repo.protections[branch]
+	if !ok {
+		return nil, fmt.Errorf("%w: branch protection %s", ErrNotFound, branch)
+	}
+	protection.StatusChecks = slices.Clone(protection.StatusChecks)
+	return &protection, nil
+}
+
+func (r *Fake) SetBranchProtection(owner string, name string, protection BranchProtection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	protection.StatusChecks = slices.Clone(protection.StatusChecks)
+	repo.protections[protection.Branch] = protection
+	return nil
+}
+
+func (r *Fake) ListRepoHooks(owner string, name string) ([]*Hook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	result := make([]*Hook, 0, len(repo.hooks))
+	for _, id := range slices.Sorted(maps.Keys(repo.hooks)) {
+		hook := repo.hooks[id]
+		// like git servers the fake does not disclose hook secrets
+		hook.Secret = ""
+		hook.Events = slices.Clone(hook.Events)
+		result = append(result, &hook)
+	}
+	return result, nil
+}
+
+func (r *Fake) CreateRepoHook(owner string, name string, hook Hook) (*Hook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	r.nextID++
+	hook.ID = r.nextID
+	hook.Events = slices.Clone(hook.Events)
+	repo.hooks[hook.ID] = hook
+	return &hook, nil
+}
+
+func (r *Fake) EditRepoHook(owner string, name string, hook Hook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	if _, ok := repo.hooks[hook.ID]; !ok {
+		return fmt.Errorf("%w: hook %d", ErrNotFound, hook.ID)
+	}
+	hook.Events = slices.Clone(hook.Events)
+	repo.hooks[hook.ID] = hook
+	return nil
+}
+
+func (r *Fake) DeleteRepoHook(owner string, name string, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	if _, ok := repo.hooks[id]; !ok {
+		return fmt.Errorf("%w: hook %d", ErrNotFound, id)
+	}
+	delete(repo.hooks, id)
+	return nil
+}
+
+// RepoHookSecret returns the secret of a hook, which ListRepoHooks does not
+// disclose
+func (r *Fake) RepoHookSecret(owner string, name string, id int64) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if repo, ok := r.repos[owner+"/"+name]; ok {
+		return repo.hooks[id].Secret
+	}
+	return ""
+}
+
+func (r *Fake) ListDeployKeys(owner string, name string) ([]*DeployKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	result := make([]*DeployKey, 0, len(repo.deployKeys))
+	for _, id := range slices.Sorted(maps.Keys(repo.deployKeys)) {
+		key := repo.deployKeys[id]
+		result = append(result, &key)
+	}
+	return result, nil
+}
+
+func (r *Fake) CreateDeployKey(owner string, name string, key DeployKey) (*DeployKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	r.nextID++
+	key.ID = r.nextID
+	repo.deployKeys[key.ID] = key
+	return &key, nil
+}
+
+func (r *Fake) DeleteDeployKey(owner string, name string, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo, ok := r.repos[owner+"/"+name]
+	if !ok {
+		return fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, name)
+	}
+	if _, ok := repo.deployKeys[id]; !ok {
+		return fmt.Errorf("%w: deploy key %d", ErrNotFound, id)
+	}
+	delete(repo.deployKeys, id)
+	return nil
+}
+
+func (r *Fake) ListAccessTokens() ([]*AccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]*AccessToken, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		// like git servers the fake does not disclose existing token values
+		result = append(result, &AccessToken{ID: token.ID, Name: token.Name, Scopes: token.Scopes})
+	}
+	return result, nil
+}
+
+func (r *Fake) CreateAccessToken(opt CreateAccessTokenOption) (*AccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[opt.Name]; ok {
+		return nil, fmt.Errorf("%w: access token %s", ErrAlreadyExists, opt.Name)
+	}
+	r.nextID++
+	token := &AccessToken{
+		ID:     r.nextID,
+		Name:   opt.Name,
+		Scopes: opt.Scopes,
+		Token:  fmt.Sprintf("token-%d", r.nextID),
+	}
+	r.tokens[opt.Name] = token
+	result := *token
+	return &result, nil
+}
+
+func (r *Fake) DeleteAccessToken(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[name]; !ok {
+		return fmt.Errorf("%w: access token %s", ErrNotFound, name)
+	}
+	delete(r.tokens, name)
+	return nil
+}
+
+func setFakeURLs(repo *Repository) {
+	repo.CloneURL = fmt.Sprintf("%s/%s/%s.git", fakeURL, repo.Owner, repo.Name)
+	repo.SSHURL = fmt.Sprintf("git@git.fake:%s/%s.git", repo.Owner, repo.Name)
+	repo.HTMLURL = fmt.Sprintf("%s/%s/%s", fakeURL, repo.Owner, repo.Name)
+}
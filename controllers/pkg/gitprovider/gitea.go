@@ -0,0 +1,408 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/sdk/gitea"
+	"k8s.io/utils/ptr"
+)
+
+// The repository scopes of Gitea 1.20 and later, which the SDK does not
+// define. The SDK only has the legacy "repo" scope of older servers.
+const (
+	giteaScopeReadRepository  gitea.AccessTokenScope = "read:repository"
+	giteaScopeWriteRepository gitea.AccessTokenScope = "write:repository"
+)
+
+type giteaProvider struct {
+	client *gitea.Client
+}
+
+// NewGitea creates a provider for a Gitea server. Gitea only allows tokens to
+// be created and listed with basic authentication, hence the provider always
+// authenticates with the username and password.
+func NewGitea(cfg Config) (GitProvider, error) {
+	client, err := gitea.NewClient(cfg.URL, gitea.SetBasicAuth(cfg.Username, cfg.Password))
+	if err != nil {
+		return nil, err
+	}
+	return &giteaProvider{client: client}, nil
+}
+
+func (r *giteaProvider) GetMyUserInfo() (*User, error) {
+	u, resp, err := r.client.GetMyUserInfo()
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return &User{ID: u.ID, UserName: u.UserName, Email: u.Email}, nil
+}
+
+func (r *giteaProvider) GetOrg(name string) (*Organization, error) {
+	org, resp, err := r.client.GetOrg(name)
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return &Organization{ID: org.ID, Name: org.UserName, Description: org.Description}, nil
+}
+
+func (r *giteaProvider) CreateOrg(opt CreateOrgOption) (*Organization, error) {
+	visibility := gitea.VisibleTypePublic
+	if opt.Private {
+		visibility = gitea.VisibleTypePrivate
+	}
+	org, resp, err := r.client.CreateOrg(gitea.CreateOrgOption{
+		Name:        opt.Name,
+		Description: opt.Description,
+		Visibility:  visibility,
+	})
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return &Organization{ID: org.ID, Name: org.UserName, Description: org.Description}, nil
+}
+
+func (r *giteaProvider) GetRepo(owner string, name string) (*Repository, error) {
+	repo, resp, err := r.client.GetRepo(owner, name)
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return giteaRepository(repo), nil
+}
+
+func (r *giteaProvider) CreateRepo(opt CreateRepoOption) (*Repository, error) {
+	createRepo := gitea.CreateRepoOption{
+		Name:          opt.Name,
+		Description:   opt.Description,
+		Private:       opt.Private,
+		IssueLabels:   opt.IssueLabels,
+		AutoInit:      opt.AutoInit,
+		Gitignores:    opt.Gitignores,
+		License:       opt.License,
+		Readme:        opt.Readme,
+		DefaultBranch: opt.DefaultBranch,
+		TrustModel:    gitea.TrustModel(opt.TrustModel),
+	}
+	var repo *gitea.Repository
+	var resp *gitea.Response
+	var err error
+	if opt.Owner != "" {
+		repo, resp, err = r.client.CreateOrgRepo(opt.Owner, createRepo)
+	} else {
+		repo, resp, err = r.client.CreateRepo(createRepo)
+	}
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return giteaRepository(repo), nil
+}
+
+func (r *giteaProvider) EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error) {
+	repo, resp, err := r.client.EditRepo(owner, name, gitea.EditRepoOption{
+		Name:        opt.Name,
+		Description: opt.Description,
+		Private:     opt.Private,
+	})
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return giteaRepository(repo), nil
+}
+
+func (r *giteaProvider) DeleteRepo(owner string, name string) error {
+	resp, err := r.client.DeleteRepo(owner, name)
+	return giteaError(resp, err)
+}
+
+// AddRepoTeam adds the repository to the team. In Gitea the permission of a
+// team applies to all repositories of the team, so the permission is ignored.
+func (r *giteaProvider) AddRepoTeam(owner string, name string, team string, permission Permission) error {
+	teams, resp, err := r.client.SearchOrgTeams(owner, &gitea.SearchTeamsOptions{Query: team})
+	if err != nil {
+		return giteaError(resp, err)
+	}
+	for _, t := range teams {
+		if t.Name == team {
+			resp, err := r.client.AddTeamRepository(t.ID, owner, name)
+			return giteaError(resp, err)
+		}
+	}
+	return fmt.Errorf("%w: team %s/%s", ErrNotFound, owner, team)
+}
+
+func (r *giteaProvider) AddRepoCollaborator(owner string, name string, user string, permission Permission) error {
+	mode := gitea.AccessMode(permission)
+	resp, err := r.client.AddCollaborator(owner, name, user, gitea.AddCollaboratorOption{Permission: &mode})
+	return giteaError(resp, err)
+}
+
+func (r *giteaProvider) GetBranchProtection(owner string, name string, branch string) (*BranchProtection, error) {
+	bp, resp, err := r.client.GetBranchProtection(owner, name, branch)
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return &BranchProtection{
+		Branch:            bp.RuleName,
+		RequiredApprovals: bp.RequiredApprovals,
+		StatusChecks:      bp.StatusCheckContexts,
+		BlockForcePush:    true,
+	}, nil
+}
+
+// SetBranchProtection protects the branch with a rule named after the branch.
+// Gitea rejects force pushes to any protected branch, so a protection allowing
+// force pushes is not supported.
+func (r *giteaProvider) SetBranchProtection(owner string, name string, protection BranchProtection) error {
+	if !protection.BlockForcePush {
+		return fmt.Errorf("%w: gitea always blocks force pushes to protected branches", ErrNotSupported)
+	}
+	enableStatusCheck := len(protection.StatusChecks) > 0
+	_, resp, err := r.client.GetBranchProtection(owner, name, protection.Branch)
+	if err == nil {
+		_, resp, err = r.client.EditBranchProtection(owner, name, protection.Branch, gitea.EditBranchProtectionOption{
+			EnablePush:          ptr.To(true),
+			EnableStatusCheck:   &enableStatusCheck,
+			StatusCheckContexts: protection.StatusChecks,
+			RequiredApprovals:   &protection.RequiredApprovals,
+		})
+		return giteaError(resp, err)
+	}
+	if err := giteaError(resp, err); !IsNotFound(err) {
+		return err
+	}
+	_, resp, err = r.client.CreateBranchProtection(owner, name, gitea.CreateBranchProtectionOption{
+		RuleName:            protection.Branch,
+		EnablePush:          true,
+		EnableStatusCheck:   enableStatusCheck,
+		StatusCheckContexts: protection.StatusChecks,
+		RequiredApprovals:   protection.RequiredApprovals,
+	})
+	return giteaError(resp, err)
+}
+
+func (r *giteaProvider) ListRepoHooks(owner string, name string) ([]*Hook, error) {
+	hooks, err := giteaList(func(opt gitea.ListOptions) ([]*gitea.Hook, *gitea.Response, error) {
+		return r.client.ListRepoHooks(owner, name, gitea.ListHooksOptions{ListOptions: opt})
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Hook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &Hook{
+			ID:          hook.ID,
+			URL:         hook.Config["url"],
+			ContentType: hook.Config["content_type"],
+			Events:      hook.Events,
+			Active:      hook.Active,
+		})
+	}
+	return result, nil
+}
+
+func (r *giteaProvider) CreateRepoHook(owner string, name string, hook Hook) (*Hook, error) {
+	h, resp, err := r.client.CreateRepoHook(owner, name, gitea.CreateHookOption{
+		Type:   gitea.HookTypeGitea,
+		Config: giteaHookConfig(hook),
+		Events: hook.Events,
+		Active: hook.Active,
+	})
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	hook.ID = h.ID
+	return &hook, nil
+}
+
+func (r *giteaProvider) EditRepoHook(owner string, name string, hook Hook) error {
+	resp, err := r.client.EditRepoHook(owner, name, hook.ID, gitea.EditHookOption{
+		Config: giteaHookConfig(hook),
+		Events: hook.Events,
+		Active: &hook.Active,
+	})
+	return giteaError(resp, err)
+}
+
+func (r *giteaProvider) DeleteRepoHook(owner string, name string, id int64) error {
+	resp, err := r.client.DeleteRepoHook(owner, name, id)
+	return giteaError(resp, err)
+}
+
+func (r *giteaProvider) ListDeployKeys(owner string, name string) ([]*DeployKey, error) {
+	keys, err := giteaList(func(opt gitea.ListOptions) ([]*gitea.DeployKey, *gitea.Response, error) {
+		return r.client.ListDeployKeys(owner, name, gitea.ListDeployKeysOptions{ListOptions: opt})
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*DeployKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &DeployKey{ID: key.ID, Title: key.Title, Key: key.Key, ReadOnly: key.ReadOnly})
+	}
+	return result, nil
+}
+
+func (r *giteaProvider) CreateDeployKey(owner string, name string, key DeployKey) (*DeployKey, error) {
+	k, resp, err := r.client.CreateDeployKey(owner, name, gitea.CreateKeyOption{
+		Title:    key.Title,
+		Key:      key.Key,
+		ReadOnly: key.ReadOnly,
+	})
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	key.ID = k.ID
+	return &key, nil
+}
+
+func (r *giteaProvider) DeleteDeployKey(owner string, name string, id int64) error {
+	resp, err := r.client.DeleteDeployKey(owner, name, id)
+	return giteaError(resp, err)
+}
+
+func (r *giteaProvider) ListAccessTokens() ([]*AccessToken, error) {
+	tokens, err := giteaList(func(opt gitea.ListOptions) ([]*gitea.AccessToken, *gitea.Response, error) {
+		return r.client.ListAccessTokens(gitea.ListAccessTokensOptions{ListOptions: opt})
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AccessToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, &AccessToken{
+			ID:     token.ID,
+			Name:   token.Name,
+			Scopes: fromGiteaScopes(token.Scopes),
+		})
+	}
+	return result, nil
+}
+
+func (r *giteaProvider) CreateAccessToken(opt CreateAccessTokenOption) (*AccessToken, error) {
+	token, resp, err := r.client.CreateAccessToken(gitea.CreateAccessTokenOption{
+		Name:   opt.Name,
+		Scopes: toGiteaScopes(opt.Scopes),
+	})
+	if err != nil {
+		return nil, giteaError(resp, err)
+	}
+	return &AccessToken{
+		ID:     token.ID,
+		Name:   token.Name,
+		Scopes: fromGiteaScopes(token.Scopes),
+		Token:  token.Token,
+	}, nil
+}
+
+func (r *giteaProvider) DeleteAccessToken(name string) error {
+	resp, err := r.client.DeleteAccessToken(name)
+	return giteaError(resp, err)
+}
+
+// giteaList gets all the pages of a list, following the next page of the Link
+// response headers like list does for GitLab and GitHub
+func giteaList[T any](get func(gitea.ListOptions) ([]T, *gitea.Response, error)) ([]T, error) {
+	var items []T
+	opt := gitea.ListOptions{Page: 1}
+	for {
+		page, resp, err := get(opt)
+		if err != nil {
+			return nil, giteaError(resp, err)
+		}
+		items = append(items, page...)
+		if resp == nil || resp.NextPage <= opt.Page {
+			return items, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+func giteaRepository(repo *gitea.Repository) *Repository {
+	result := &Repository{
+		ID:            repo.ID,
+		Name:          repo.Name,
+		Description:   repo.Description,
+		Private:       repo.Private,
+		DefaultBranch: repo.DefaultBranch,
+		CloneURL:      repo.CloneURL,
+		SSHURL:        repo.SSHURL,
+		HTMLURL:       repo.HTMLURL,
+	}
+	if repo.Owner != nil {
+		result.Owner = repo.Owner.UserName
+	}
+	return result
+}
+
+func giteaHookConfig(hook Hook) map[string]string {
+	config := map[string]string{
+		"url":          hook.URL,
+		"content_type": hook.ContentType,
+	}
+	if hook.Secret != "" {
+		config["secret"] = hook.Secret
+	}
+	return config
+}
+
+func giteaError(resp *gitea.Response, err error) error {
+	if err == nil {
+		return nil
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, err.Error())
+	}
+	if resp != nil && resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, err.Error())
+	}
+	return err
+}
+
+func toGiteaScopes(scopes []AccessTokenScope) []gitea.AccessTokenScope {
+	var result []gitea.AccessTokenScope
+	for _, scope := range scopes {
+		switch scope {
+		case AccessTokenScopeRepo:
+			result = append(result, giteaScopeWriteRepository)
+		case AccessTokenScopeRepoRead:
+			result = append(result, giteaScopeReadRepository)
+		default:
+			result = append(result, gitea.AccessTokenScope(scope))
+		}
+	}
+	return result
+}
+
+// fromGiteaScopes maps the scopes of a Gitea token back to the neutral
+// scopes, including the legacy "repo" scope of the tokens created by servers
+// older than 1.20
+func fromGiteaScopes(scopes []gitea.AccessTokenScope) []AccessTokenScope {
+	var result []AccessTokenScope
+	for _, scope := range scopes {
+		switch scope {
+		case giteaScopeWriteRepository, gitea.AccessTokenScopeRepo:
+			result = append(result, AccessTokenScopeRepo)
+		case giteaScopeReadRepository:
+			result = append(result, AccessTokenScopeRepoRead)
+		default:
+			result = append(result, AccessTokenScope(scope))
+		}
+	}
+	return result
+}
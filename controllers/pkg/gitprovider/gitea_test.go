@@ -0,0 +1,98 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitprovider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// giteaPage serves the items of a paginated Gitea list, linking to the next
+// page like Gitea does
+func giteaPage(w http.ResponseWriter, r *http.Request, pages ...string) {
+	page := r.URL.Query().Get("page")
+	i := 0
+	if page == "2" {
+		i = 1
+	}
+	if i < len(pages)-1 {
+		next := "http://" + r.Host + r.URL.Path + "?page=2&limit=" + r.URL.Query().Get("limit")
+		w.Header().Set("Link", `<`+next+`>; rel="next", <`+next+`>; rel="last"`)
+	}
+	_, _ = w.Write([]byte(pages[i]))
+}
+
+func TestGitea(t *testing.T) {
+	var created map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": "1.22.0"}`))
+	})
+	mux.HandleFunc("GET /api/v1/users/nephio/tokens", func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "nephio", user)
+		require.Equal(t, "secret", password)
+		giteaPage(w, r,
+			`[{"id": 1, "name": "legacy", "scopes": ["repo"]}]`,
+			`[{"id": 2, "name": "mgmt-token", "scopes": ["read:repository"]}, {"id": 3, "name": "edge-token", "scopes": ["write:repository"]}]`)
+	})
+	mux.HandleFunc("POST /api/v1/users/nephio/tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		_, _ = w.Write([]byte(`{"id": 4, "name": "new-token", "sha1": "abc", "scopes": ["write:repository"]}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/nephio/mgmt/hooks", func(w http.ResponseWriter, r *http.Request) {
+		giteaPage(w, r,
+			`[{"id": 1, "config": {"url": "http://a"}, "active": true}]`,
+			`[{"id": 2, "config": {"url": "http://b"}, "active": true}]`)
+	})
+	mux.HandleFunc("GET /api/v1/repos/nephio/mgmt/keys", func(w http.ResponseWriter, r *http.Request) {
+		giteaPage(w, r,
+			`[{"id": 1, "title": "a"}]`,
+			`[{"id": 2, "title": "b", "read_only": true}]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := New(ProviderGitea, Config{URL: server.URL, Username: "nephio", Password: "secret"})
+	require.NoError(t, err)
+
+	// all the pages are read and the scopes are mapped to the neutral ones
+	tokens, err := p.ListAccessTokens()
+	require.NoError(t, err)
+	require.Equal(t, []*AccessToken{
+		{ID: 1, Name: "legacy", Scopes: []AccessTokenScope{AccessTokenScopeRepo}},
+		{ID: 2, Name: "mgmt-token", Scopes: []AccessTokenScope{AccessTokenScopeRepoRead}},
+		{ID: 3, Name: "edge-token", Scopes: []AccessTokenScope{AccessTokenScopeRepo}},
+	}, tokens)
+
+	token, err := p.CreateAccessToken(CreateAccessTokenOption{Name: "new-token", Scopes: []AccessTokenScope{AccessTokenScopeRepo}})
+	require.NoError(t, err)
+	require.Equal(t, []any{"write:repository"}, created["scopes"])
+	require.Equal(t, []AccessTokenScope{AccessTokenScopeRepo}, token.Scopes)
+
+	hooks, err := p.ListRepoHooks("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	require.Equal(t, "http://b", hooks[1].URL)
+
+	keys, err := p.ListDeployKeys("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Equal(t, []*DeployKey{{ID: 1, Title: "a"}, {ID: 2, Title: "b", ReadOnly: true}}, keys)
+}
@@ -0,0 +1,392 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+type githubProvider struct {
+	rest *restClient
+}
+
+// NewGitHub creates a provider for github.com or a GitHub Enterprise server
+// using the REST API. The provider authenticates with a personal access token
+// of the user. GitHub has no API to manage personal access tokens, so the
+// token operations are not supported.
+func NewGitHub(cfg Config) (GitProvider, error) {
+	if cfg.token() == "" {
+		return nil, fmt.Errorf("github provider requires a token")
+	}
+	apiURL, err := githubAPI(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+cfg.token())
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return &githubProvider{rest: newRESTClient(apiURL, header)}, nil
+}
+
+// githubAPI returns the REST API endpoint of the server: api.github.com for
+// github.com and <url>/api/v3 for GitHub Enterprise
+func githubAPI(serverURL string) (string, error) {
+	if serverURL == "" {
+		return githubAPIURL, nil
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case u.Host == "github.com" || u.Host == "api.github.com":
+		return githubAPIURL, nil
+	case strings.Contains(u.Path, "/api/"):
+		return serverURL, nil
+	default:
+		return strings.TrimSuffix(serverURL, "/") + "/api/v3", nil
+	}
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+type githubOrg struct {
+	ID          int64  `json:"id"`
+	Login       string `json:"login"`
+	Description string `json:"description"`
+}
+
+type githubRepository struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Owner         githubUser `json:"owner"`
+	Description   string     `json:"description"`
+	Private       bool       `json:"private"`
+	DefaultBranch string     `json:"default_branch"`
+	CloneURL      string     `json:"clone_url"`
+	SSHURL        string     `json:"ssh_url"`
+	HTMLURL       string     `json:"html_url"`
+}
+
+func (r *githubProvider) GetMyUserInfo() (*User, error) {
+	u := &githubUser{}
+	if err := r.rest.do(http.MethodGet, "/user", nil, u); err != nil {
+		return nil, err
+	}
+	return &User{ID: u.ID, UserName: u.Login, Email: u.Email}, nil
+}
+
+func (r *githubProvider) GetOrg(name string) (*Organization, error) {
+	org := &githubOrg{}
+	if err := r.rest.do(http.MethodGet, "/orgs/"+url.PathEscape(name), nil, org); err != nil {
+		return nil, err
+	}
+	return &Organization{ID: org.ID, Name: org.Login, Description: org.Description}, nil
+}
+
+// CreateOrg creates an organization administered by the user. Only GitHub
+// Enterprise site administrators can create organizations through the API.
+func (r *githubProvider) CreateOrg(opt CreateOrgOption) (*Organization, error) {
+	u, err := r.GetMyUserInfo()
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{
+		"login": opt.Name,
+		"admin": u.UserName,
+	}
+	org := &githubOrg{}
+	if err := r.rest.do(http.MethodPost, "/admin/organizations", body, org); err != nil {
+		return nil, err
+	}
+	return &Organization{ID: org.ID, Name: org.Login, Description: opt.Description}, nil
+}
+
+func (r *githubProvider) GetRepo(owner string, name string) (*Repository, error) {
+	repo := &githubRepository{}
+	if err := r.rest.do(http.MethodGet, githubRepoPath(owner, name), nil, repo); err != nil {
+		return nil, err
+	}
+	return repo.repository(), nil
+}
+
+func (r *githubProvider) CreateRepo(opt CreateRepoOption) (*Repository, error) {
+	body := map[string]any{
+		"name":        opt.Name,
+		"description": opt.Description,
+		"private":     opt.Private,
+		"auto_init":   opt.AutoInit,
+	}
+	if opt.Gitignores != "" {
+		body["gitignore_template"] = opt.Gitignores
+	}
+	if opt.License != "" {
+		body["license_template"] = opt.License
+	}
+	path := "/user/repos"
+	if opt.Owner != "" {
+		path = "/orgs/" + url.PathEscape(opt.Owner) + "/repos"
+	}
+	repo := &githubRepository{}
+	if err := r.rest.do(http.MethodPost, path, body, repo); err != nil {
+		return nil, err
+	}
+	return repo.repository(), nil
+}
+
+func (r *githubProvider) EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error) {
+	body := map[string]any{}
+	if opt.Name != nil {
+		body["name"] = *opt.Name
+	}
+	if opt.Description != nil {
+		body["description"] = *opt.Description
+	}
+	if opt.Private != nil {
+		body["private"] = *opt.Private
+	}
+	repo := &githubRepository{}
+	if err := r.rest.do(http.MethodPatch, githubRepoPath(owner, name), body, repo); err != nil {
+		return nil, err
+	}
+	return repo.repository(), nil
+}
+
+func (r *githubProvider) DeleteRepo(owner string, name string) error {
+	return r.rest.do(http.MethodDelete, githubRepoPath(owner, name), nil, nil)
+}
+
+// AddRepoTeam grants the team, identified by its slug, access to the
+// repository
+func (r *githubProvider) AddRepoTeam(owner string, name string, team string, permission Permission) error {
+	path := fmt.Sprintf("/orgs/%s/teams/%s%s", url.PathEscape(owner), url.PathEscape(team), githubRepoPath(owner, name))
+	return r.rest.do(http.MethodPut, path, map[string]any{"permission": githubPermission(permission)}, nil)
+}
+
+// AddRepoCollaborator invites the user to collaborate on the repository
+func (r *githubProvider) AddRepoCollaborator(owner string, name string, user string, permission Permission) error {
+	path := githubRepoPath(owner, name) + "/collaborators/" + url.PathEscape(user)
+	return r.rest.do(http.MethodPut, path, map[string]any{"permission": githubPermission(permission)}, nil)
+}
+
+func (r *githubProvider) GetBranchProtection(owner string, name string, branch string) (*BranchProtection, error) {
+	p := &githubProtection{}
+	if err := r.rest.do(http.MethodGet, githubProtectionPath(owner, name, branch), nil, p); err != nil {
+		return nil, err
+	}
+	protection := &BranchProtection{
+		Branch:         branch,
+		BlockForcePush: !p.AllowForcePushes.Enabled,
+	}
+	if p.RequiredStatusChecks != nil {
+		protection.StatusChecks = p.RequiredStatusChecks.Contexts
+	}
+	if p.RequiredPullRequestReviews != nil {
+		protection.RequiredApprovals = p.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	return protection, nil
+}
+
+// SetBranchProtection replaces the protection of the branch; status checks
+// and reviews are only required when set
+func (r *githubProvider) SetBranchProtection(owner string, name string, protection BranchProtection) error {
+	body := map[string]any{
+		"required_status_checks":        nil,
+		"enforce_admins":                false,
+		"required_pull_request_reviews": nil,
+		"restrictions":                  nil,
+		"allow_force_pushes":            !protection.BlockForcePush,
+	}
+	if len(protection.StatusChecks) > 0 {
+		body["required_status_checks"] = map[string]any{
+			"strict":   false,
+			"contexts": protection.StatusChecks,
+		}
+	}
+	if protection.RequiredApprovals > 0 {
+		body["required_pull_request_reviews"] = map[string]any{
+			"required_approving_review_count": protection.RequiredApprovals,
+		}
+	}
+	return r.rest.do(http.MethodPut, githubProtectionPath(owner, name, protection.Branch), body, nil)
+}
+
+func (r *githubProvider) ListRepoHooks(owner string, name string) ([]*Hook, error) {
+	hooks, err := list[githubHook](r.rest, githubRepoPath(owner, name)+"/hooks?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Hook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &Hook{
+			ID:          hook.ID,
+			URL:         hook.Config.URL,
+			ContentType: hook.Config.ContentType,
+			Events:      hook.Events,
+			Active:      hook.Active,
+		})
+	}
+	return result, nil
+}
+
+func (r *githubProvider) CreateRepoHook(owner string, name string, hook Hook) (*Hook, error) {
+	body := githubHookBody(hook)
+	body["name"] = "web"
+	h := &githubHook{}
+	if err := r.rest.do(http.MethodPost, githubRepoPath(owner, name)+"/hooks", body, h); err != nil {
+		return nil, err
+	}
+	hook.ID = h.ID
+	return &hook, nil
+}
+
+func (r *githubProvider) EditRepoHook(owner string, name string, hook Hook) error {
+	path := fmt.Sprintf("%s/hooks/%d", githubRepoPath(owner, name), hook.ID)
+	return r.rest.do(http.MethodPatch, path, githubHookBody(hook), nil)
+}
+
+func (r *githubProvider) DeleteRepoHook(owner string, name string, id int64) error {
+	return r.rest.do(http.MethodDelete, fmt.Sprintf("%s/hooks/%d", githubRepoPath(owner, name), id), nil, nil)
+}
+
+func (r *githubProvider) ListDeployKeys(owner string, name string) ([]*DeployKey, error) {
+	keys, err := list[githubDeployKey](r.rest, githubRepoPath(owner, name)+"/keys?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*DeployKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &DeployKey{ID: key.ID, Title: key.Title, Key: key.Key, ReadOnly: key.ReadOnly})
+	}
+	return result, nil
+}
+
+func (r *githubProvider) CreateDeployKey(owner string, name string, key DeployKey) (*DeployKey, error) {
+	body := map[string]any{
+		"title":     key.Title,
+		"key":       key.Key,
+		"read_only": key.ReadOnly,
+	}
+	k := &githubDeployKey{}
+	if err := r.rest.do(http.MethodPost, githubRepoPath(owner, name)+"/keys", body, k); err != nil {
+		return nil, err
+	}
+	key.ID = k.ID
+	return &key, nil
+}
+
+func (r *githubProvider) DeleteDeployKey(owner string, name string, id int64) error {
+	return r.rest.do(http.MethodDelete, fmt.Sprintf("%s/keys/%d", githubRepoPath(owner, name), id), nil, nil)
+}
+
+func (r *githubProvider) ListAccessTokens() ([]*AccessToken, error) {
+	return nil, fmt.Errorf("%w: github provider cannot list access tokens", ErrNotSupported)
+}
+
+func (r *githubProvider) CreateAccessToken(opt CreateAccessTokenOption) (*AccessToken, error) {
+	return nil, fmt.Errorf("%w: github provider cannot create access tokens", ErrNotSupported)
+}
+
+func (r *githubProvider) DeleteAccessToken(name string) error {
+	return fmt.Errorf("%w: github provider cannot delete access tokens", ErrNotSupported)
+}
+
+func githubRepoPath(owner, name string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+func githubProtectionPath(owner, name, branch string) string {
+	return githubRepoPath(owner, name) + "/branches/" + url.PathEscape(branch) + "/protection"
+}
+
+func githubHookBody(hook Hook) map[string]any {
+	config := map[string]any{
+		"url":          hook.URL,
+		"content_type": hook.ContentType,
+	}
+	if hook.Secret != "" {
+		config["secret"] = hook.Secret
+	}
+	return map[string]any{
+		"active": hook.Active,
+		"events": hook.Events,
+		"config": config,
+	}
+}
+
+func githubPermission(permission Permission) string {
+	switch permission {
+	case PermissionAdmin:
+		return "admin"
+	case PermissionWrite:
+		return "push"
+	default:
+		return "pull"
+	}
+}
+
+type githubProtection struct {
+	RequiredStatusChecks *struct {
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	RequiredPullRequestReviews *struct {
+		RequiredApprovingReviewCount int64 `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+	AllowForcePushes struct {
+		Enabled bool `json:"enabled"`
+	} `json:"allow_force_pushes"`
+}
+
+type githubDeployKey struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Key      string `json:"key"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+type githubHook struct {
+	ID     int64    `json:"id"`
+	Active bool     `json:"active"`
+	Events []string `json:"events"`
+	Config struct {
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+	} `json:"config"`
+}
+
+func (r *githubRepository) repository() *Repository {
+	return &Repository{
+		ID:            r.ID,
+		Owner:         r.Owner.Login,
+		Name:          r.Name,
+		Description:   r.Description,
+		Private:       r.Private,
+		DefaultBranch: r.DefaultBranch,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		HTMLURL:       r.HTMLURL,
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubAPI(t *testing.T) {
+	tests := map[string]struct {
+		url  string
+		want string
+	}{
+		"Default": {
+			url:  "",
+			want: "https://api.github.com",
+		},
+		"GitHub": {
+			url:  "https://github.com",
+			want: "https://api.github.com",
+		},
+		"Enterprise": {
+			url:  "https://github.example.com/",
+			want: "https://github.example.com/api/v3",
+		},
+		"EnterpriseAPI": {
+			url:  "https://github.example.com/api/v3",
+			want: "https://github.example.com/api/v3",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := githubAPI(tc.url)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	var edited map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id": 7, "login": "nephio"}`))
+	})
+	mux.HandleFunc("PATCH /api/v3/repos/nephio/mgmt", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&edited))
+		_, _ = w.Write([]byte(`{"id": 1, "name": "mgmt", "owner": {"login": "nephio"}, "private": true,
+			"clone_url": "https://github.example.com/nephio/mgmt.git"}`))
+	})
+	mux.HandleFunc("DELETE /api/v3/repos/nephio/mgmt", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Must have admin rights to Repository."}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := New(ProviderGitHub, Config{URL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	u, err := p.GetMyUserInfo()
+	require.NoError(t, err)
+	require.Equal(t, "nephio", u.UserName)
+
+	private := true
+	repo, err := p.EditRepo("nephio", "mgmt", EditRepoOption{Private: &private})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"private": true}, edited)
+	require.Equal(t, "nephio", repo.Owner)
+	require.Equal(t, "https://github.example.com/nephio/mgmt.git", repo.CloneURL)
+
+	err = p.DeleteRepo("nephio", "mgmt")
+	require.ErrorContains(t, err, "Must have admin rights")
+	require.False(t, IsNotFound(err))
+
+	_, err = p.CreateAccessToken(CreateAccessTokenOption{Name: "mgmt"})
+	require.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestGitHubProtectionAndHooks(t *testing.T) {
+	var protection, hook map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/nephio/mgmt/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"required_status_checks": {"contexts": ["ci"]},
+			"required_pull_request_reviews": {"required_approving_review_count": 2},
+			"allow_force_pushes": {"enabled": false}}`))
+	})
+	mux.HandleFunc("PUT /api/v3/repos/nephio/mgmt/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&protection))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/nephio/mgmt/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 4, "active": true, "events": ["push"],
+			"config": {"url": "http://porch/webhook", "content_type": "json"}}]`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/nephio/mgmt/hooks", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&hook))
+		_, _ = w.Write([]byte(`{"id": 5}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := New(ProviderGitHub, Config{URL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	bp, err := p.GetBranchProtection("nephio", "mgmt", "main")
+	require.NoError(t, err)
+	require.Equal(t, &BranchProtection{Branch: "main", RequiredApprovals: 2, StatusChecks: []string{"ci"}, BlockForcePush: true}, bp)
+
+	require.NoError(t, p.SetBranchProtection("nephio", "mgmt", BranchProtection{Branch: "main", RequiredApprovals: 1}))
+	require.Equal(t, map[string]any{
+		"required_status_checks":        nil,
+		"enforce_admins":                false,
+		"required_pull_request_reviews": map[string]any{"required_approving_review_count": float64(1)},
+		"restrictions":                  nil,
+		"allow_force_pushes":            true,
+	}, protection)
+
+	hooks, err := p.ListRepoHooks("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Equal(t, []*Hook{{ID: 4, URL: "http://porch/webhook", ContentType: "json", Events: []string{"push"}, Active: true}}, hooks)
+
+	created, err := p.CreateRepoHook("nephio", "mgmt", Hook{URL: "http://config-sync/webhook", ContentType: "json", Events: []string{"push"}, Active: true, Secret: "s3cr3t"})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), created.ID)
+	require.Equal(t, "web", hook["name"])
+	require.Equal(t, map[string]any{"url": "http://config-sync/webhook", "content_type": "json", "secret": "s3cr3t"}, hook["config"])
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	_, err := New("bitbucket", Config{})
+	require.ErrorContains(t, err, "unknown git provider")
+}
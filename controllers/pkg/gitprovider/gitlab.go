@@ -0,0 +1,503 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+type gitlabProvider struct {
+	rest *restClient
+}
+
+// NewGitLab creates a provider for a GitLab server using the REST API v4. The
+// provider authenticates with a personal access token of the user. Creating
+// access tokens for the user requires the token to have admin rights.
+func NewGitLab(cfg Config) (GitProvider, error) {
+	if cfg.token() == "" {
+		return nil, fmt.Errorf("gitlab provider requires a token")
+	}
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", cfg.token())
+	return &gitlabProvider{
+		rest: newRESTClient(strings.TrimSuffix(cfg.URL, "/")+"/api/v4", header),
+	}, nil
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type gitlabGroup struct {
+	ID          int64  `json:"id"`
+	FullPath    string `json:"full_path"`
+	Description string `json:"description"`
+}
+
+type gitlabProject struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Visibility    string `json:"visibility"`
+	DefaultBranch string `json:"default_branch"`
+	HTTPURLToRepo string `json:"http_url_to_repo"`
+	SSHURLToRepo  string `json:"ssh_url_to_repo"`
+	WebURL        string `json:"web_url"`
+	Namespace     struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+}
+
+type gitlabDeployKey struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Key     string `json:"key"`
+	CanPush bool   `json:"can_push"`
+}
+
+type gitlabToken struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Token  string   `json:"token,omitempty"`
+}
+
+type gitlabProtectedBranch struct {
+	Name           string `json:"name"`
+	AllowForcePush bool   `json:"allow_force_push"`
+}
+
+type gitlabHook struct {
+	ID                  int64  `json:"id"`
+	URL                 string `json:"url"`
+	PushEvents          bool   `json:"push_events"`
+	MergeRequestsEvents bool   `json:"merge_requests_events"`
+	TagPushEvents       bool   `json:"tag_push_events"`
+}
+
+// gitlabHookEvents maps the hook events to the event flags of a GitLab hook
+var gitlabHookEvents = map[string]string{
+	HookEventPush:        "push_events",
+	HookEventPullRequest: "merge_requests_events",
+	HookEventCreate:      "tag_push_events",
+}
+
+func (r *gitlabProvider) GetMyUserInfo() (*User, error) {
+	u := &gitlabUser{}
+	if err := r.rest.do(http.MethodGet, "/user", nil, u); err != nil {
+		return nil, err
+	}
+	return &User{ID: u.ID, UserName: u.Username, Email: u.Email}, nil
+}
+
+// GetOrg gets the group with the given full path
+func (r *gitlabProvider) GetOrg(name string) (*Organization, error) {
+	g, err := r.getGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Organization{ID: g.ID, Name: g.FullPath, Description: g.Description}, nil
+}
+
+// CreateOrg creates a top level group
+func (r *gitlabProvider) CreateOrg(opt CreateOrgOption) (*Organization, error) {
+	body := map[string]any{
+		"name":        opt.Name,
+		"path":        opt.Name,
+		"description": opt.Description,
+		"visibility":  gitlabVisibility(opt.Private),
+	}
+	g := &gitlabGroup{}
+	if err := r.rest.do(http.MethodPost, "/groups", body, g); err != nil {
+		return nil, err
+	}
+	return &Organization{ID: g.ID, Name: g.FullPath, Description: g.Description}, nil
+}
+
+func (r *gitlabProvider) GetRepo(owner string, name string) (*Repository, error) {
+	p := &gitlabProject{}
+	if err := r.rest.do(http.MethodGet, gitlabProjectPath(owner, name), nil, p); err != nil {
+		return nil, err
+	}
+	return p.repository(), nil
+}
+
+func (r *gitlabProvider) CreateRepo(opt CreateRepoOption) (*Repository, error) {
+	body := map[string]any{
+		"name":                   opt.Name,
+		"path":                   opt.Name,
+		"description":            opt.Description,
+		"visibility":             gitlabVisibility(opt.Private),
+		"initialize_with_readme": opt.AutoInit,
+	}
+	if opt.DefaultBranch != "" {
+		body["default_branch"] = opt.DefaultBranch
+	}
+	if opt.Owner != "" {
+		g, err := r.getGroup(opt.Owner)
+		if err != nil {
+			return nil, err
+		}
+		body["namespace_id"] = g.ID
+	}
+	p := &gitlabProject{}
+	if err := r.rest.do(http.MethodPost, "/projects", body, p); err != nil {
+		return nil, err
+	}
+	return p.repository(), nil
+}
+
+func (r *gitlabProvider) EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error) {
+	body := map[string]any{}
+	if opt.Name != nil {
+		body["name"] = *opt.Name
+	}
+	if opt.Description != nil {
+		body["description"] = *opt.Description
+	}
+	if opt.Private != nil {
+		body["visibility"] = gitlabVisibility(*opt.Private)
+	}
+	p := &gitlabProject{}
+	if err := r.rest.do(http.MethodPut, gitlabProjectPath(owner, name), body, p); err != nil {
+		return nil, err
+	}
+	return p.repository(), nil
+}
+
+func (r *gitlabProvider) DeleteRepo(owner string, name string) error {
+	return r.rest.do(http.MethodDelete, gitlabProjectPath(owner, name), nil, nil)
+}
+
+// AddRepoTeam shares the project with the subgroup <owner>/<team>
+func (r *gitlabProvider) AddRepoTeam(owner string, name string, team string, permission Permission) error {
+	g, err := r.getGroup(owner + "/" + team)
+	if err != nil {
+		return err
+	}
+	path := gitlabProjectPath(owner, name) + "/share"
+	body := map[string]any{
+		"group_id":     g.ID,
+		"group_access": gitlabAccessLevel(permission),
+	}
+	err = r.rest.do(http.MethodPost, path, body, nil)
+	if errors.Is(err, ErrAlreadyExists) {
+		// the access level of a share cannot be updated, so share it again
+		if err := r.rest.do(http.MethodDelete, fmt.Sprintf("%s/%d", path, g.ID), nil, nil); err != nil {
+			return err
+		}
+		err = r.rest.do(http.MethodPost, path, body, nil)
+	}
+	return err
+}
+
+func (r *gitlabProvider) AddRepoCollaborator(owner string, name string, user string, permission Permission) error {
+	var users []gitlabUser
+	if err := r.rest.do(http.MethodGet, "/users?username="+url.QueryEscape(user), nil, &users); err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("%w: user %s", ErrNotFound, user)
+	}
+	path := gitlabProjectPath(owner, name) + "/members"
+	body := map[string]any{
+		"user_id":      users[0].ID,
+		"access_level": gitlabAccessLevel(permission),
+	}
+	err := r.rest.do(http.MethodPost, path, body, nil)
+	if errors.Is(err, ErrAlreadyExists) {
+		err = r.rest.do(http.MethodPut, fmt.Sprintf("%s/%d", path, users[0].ID), body, nil)
+	}
+	return err
+}
+
+func (r *gitlabProvider) GetBranchProtection(owner string, name string, branch string) (*BranchProtection, error) {
+	b := &gitlabProtectedBranch{}
+	path := gitlabProjectPath(owner, name) + "/protected_branches/" + url.PathEscape(branch)
+	if err := r.rest.do(http.MethodGet, path, nil, b); err != nil {
+		return nil, err
+	}
+	return &BranchProtection{Branch: b.Name, BlockForcePush: !b.AllowForcePush}, nil
+}
+
+// SetBranchProtection protects the branch. Required approvals and status
+// checks are merge request settings only available in paid GitLab tiers, so
+// they are not supported.
+func (r *gitlabProvider) SetBranchProtection(owner string, name string, protection BranchProtection) error {
+	if protection.RequiredApprovals > 0 || len(protection.StatusChecks) > 0 {
+		return fmt.Errorf("%w: gitlab provider cannot require approvals or status checks", ErrNotSupported)
+	}
+	path := gitlabProjectPath(owner, name) + "/protected_branches"
+	body := map[string]any{"allow_force_push": !protection.BlockForcePush}
+	_, err := r.GetBranchProtection(owner, name, protection.Branch)
+	if err == nil {
+		return r.rest.do(http.MethodPatch, path+"/"+url.PathEscape(protection.Branch), body, nil)
+	}
+	if !IsNotFound(err) {
+		return err
+	}
+	body["name"] = protection.Branch
+	return r.rest.do(http.MethodPost, path, body, nil)
+}
+
+// ListRepoHooks lists the hooks of the project. GitLab hooks have no active
+// flag and always send JSON payloads.
+func (r *gitlabProvider) ListRepoHooks(owner string, name string) ([]*Hook, error) {
+	hooks, err := list[gitlabHook](r.rest, gitlabProjectPath(owner, name)+"/hooks?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Hook, 0, len(hooks))
+	for _, hook := range hooks {
+		h := &Hook{ID: hook.ID, URL: hook.URL, ContentType: "json", Active: true}
+		if hook.PushEvents {
+			h.Events = append(h.Events, HookEventPush)
+		}
+		if hook.MergeRequestsEvents {
+			h.Events = append(h.Events, HookEventPullRequest)
+		}
+		if hook.TagPushEvents {
+			h.Events = append(h.Events, HookEventCreate)
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+func (r *gitlabProvider) CreateRepoHook(owner string, name string, hook Hook) (*Hook, error) {
+	body, err := gitlabHookBody(hook)
+	if err != nil {
+		return nil, err
+	}
+	h := &gitlabHook{}
+	if err := r.rest.do(http.MethodPost, gitlabProjectPath(owner, name)+"/hooks", body, h); err != nil {
+		return nil, err
+	}
+	hook.ID = h.ID
+	return &hook, nil
+}
+
+func (r *gitlabProvider) EditRepoHook(owner string, name string, hook Hook) error {
+	body, err := gitlabHookBody(hook)
+	if err != nil {
+		return err
+	}
+	return r.rest.do(http.MethodPut, fmt.Sprintf("%s/hooks/%d", gitlabProjectPath(owner, name), hook.ID), body, nil)
+}
+
+func (r *gitlabProvider) DeleteRepoHook(owner string, name string, id int64) error {
+	return r.rest.do(http.MethodDelete, fmt.Sprintf("%s/hooks/%d", gitlabProjectPath(owner, name), id), nil, nil)
+}
+
+func (r *gitlabProvider) ListDeployKeys(owner string, name string) ([]*DeployKey, error) {
+	keys, err := list[gitlabDeployKey](r.rest, gitlabProjectPath(owner, name)+"/deploy_keys?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*DeployKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &DeployKey{ID: key.ID, Title: key.Title, Key: key.Key, ReadOnly: !key.CanPush})
+	}
+	return result, nil
+}
+
+func (r *gitlabProvider) CreateDeployKey(owner string, name string, key DeployKey) (*DeployKey, error) {
+	body := map[string]any{
+		"title":    key.Title,
+		"key":      key.Key,
+		"can_push": !key.ReadOnly,
+	}
+	k := &gitlabDeployKey{}
+	if err := r.rest.do(http.MethodPost, gitlabProjectPath(owner, name)+"/deploy_keys", body, k); err != nil {
+		return nil, err
+	}
+	key.ID = k.ID
+	return &key, nil
+}
+
+func (r *gitlabProvider) DeleteDeployKey(owner string, name string, id int64) error {
+	return r.rest.do(http.MethodDelete, fmt.Sprintf("%s/deploy_keys/%d", gitlabProjectPath(owner, name), id), nil, nil)
+}
+
+func (r *gitlabProvider) ListAccessTokens() ([]*AccessToken, error) {
+	tokens, err := r.listTokens()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AccessToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, token.accessToken())
+	}
+	return result, nil
+}
+
+func (r *gitlabProvider) CreateAccessToken(opt CreateAccessTokenOption) (*AccessToken, error) {
+	u, err := r.GetMyUserInfo()
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{
+		"name":   opt.Name,
+		"scopes": toGitLabScopes(opt.Scopes),
+	}
+	token := &gitlabToken{}
+	if err := r.rest.do(http.MethodPost, fmt.Sprintf("/users/%d/personal_access_tokens", u.ID), body, token); err != nil {
+		return nil, err
+	}
+	return token.accessToken(), nil
+}
+
+func (r *gitlabProvider) DeleteAccessToken(name string) error {
+	tokens, err := r.listTokens()
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.Name == name {
+			return r.rest.do(http.MethodDelete, fmt.Sprintf("/personal_access_tokens/%d", token.ID), nil, nil)
+		}
+	}
+	return fmt.Errorf("%w: access token %q", ErrNotFound, name)
+}
+
+// listTokens lists the active personal access tokens of the user
+func (r *gitlabProvider) listTokens() ([]gitlabToken, error) {
+	u, err := r.GetMyUserInfo()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/personal_access_tokens?state=active&per_page=100&user_id=%d", u.ID)
+	return list[gitlabToken](r.rest, path)
+}
+
+func (r *gitlabProvider) getGroup(fullPath string) (*gitlabGroup, error) {
+	g := &gitlabGroup{}
+	if err := r.rest.do(http.MethodGet, "/groups/"+url.PathEscape(fullPath), nil, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// gitlabProjectPath addresses a project by its url encoded full path
+func gitlabProjectPath(owner, name string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+name)
+}
+
+func gitlabHookBody(hook Hook) (map[string]any, error) {
+	if !hook.Active {
+		return nil, fmt.Errorf("%w: gitlab hooks cannot be inactive", ErrNotSupported)
+	}
+	if hook.ContentType != "" && hook.ContentType != "json" {
+		return nil, fmt.Errorf("%w: gitlab hooks only send json payloads", ErrNotSupported)
+	}
+	body := map[string]any{"url": hook.URL}
+	for _, flag := range gitlabHookEvents {
+		body[flag] = false
+	}
+	for _, event := range hook.Events {
+		flag, ok := gitlabHookEvents[event]
+		if !ok {
+			return nil, fmt.Errorf("%w: gitlab hooks have no %s event", ErrNotSupported, event)
+		}
+		body[flag] = true
+	}
+	if hook.Secret != "" {
+		body["token"] = hook.Secret
+	}
+	return body, nil
+}
+
+func gitlabVisibility(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
+
+// gitlabAccessLevel maps a permission to the reporter, developer and
+// maintainer roles
+func gitlabAccessLevel(permission Permission) int {
+	switch permission {
+	case PermissionAdmin:
+		return 40
+	case PermissionWrite:
+		return 30
+	default:
+		return 20
+	}
+}
+
+func (p *gitlabProject) repository() *Repository {
+	return &Repository{
+		ID:            p.ID,
+		Owner:         p.Namespace.FullPath,
+		Name:          p.Name,
+		Description:   p.Description,
+		Private:       p.Visibility != "public",
+		DefaultBranch: p.DefaultBranch,
+		CloneURL:      p.HTTPURLToRepo,
+		SSHURL:        p.SSHURLToRepo,
+		HTMLURL:       p.WebURL,
+	}
+}
+
+func (t *gitlabToken) accessToken() *AccessToken {
+	return &AccessToken{ID: t.ID, Name: t.Name, Token: t.Token, Scopes: fromGitLabScopes(t.Scopes)}
+}
+
+func toGitLabScopes(scopes []AccessTokenScope) []string {
+	var result []string
+	for _, scope := range scopes {
+		switch scope {
+		case AccessTokenScopeRepo:
+			result = append(result, "read_repository", "write_repository")
+		case AccessTokenScopeRepoRead:
+			result = append(result, "read_repository")
+		default:
+			result = append(result, string(scope))
+		}
+	}
+	return result
+}
+
+// fromGitLabScopes maps the scopes of a GitLab token back to the neutral
+// scopes, the write_repository scope and its read_repository companion
+// becoming AccessTokenScopeRepo
+func fromGitLabScopes(scopes []string) []AccessTokenScope {
+	write := slices.Contains(scopes, "write_repository")
+	var result []AccessTokenScope
+	for _, scope := range scopes {
+		switch {
+		case scope == "write_repository":
+			result = append(result, AccessTokenScopeRepo)
+		case scope == "read_repository" && write:
+			// granted by AccessTokenScopeRepo
+		case scope == "read_repository":
+			result = append(result, AccessTokenScopeRepoRead)
+		default:
+			result = append(result, AccessTokenScope(scope))
+		}
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLab(t *testing.T) {
+	var created map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`{"id": 7, "username": "nephio"}`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/{path}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("path") != "nephio/mgmt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1, "name": "mgmt", "visibility": "private", "namespace": {"full_path": "nephio"},
+			"http_url_to_repo": "https://gitlab.example.com/nephio/mgmt.git"}`))
+	})
+	mux.HandleFunc("POST /api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		_, _ = w.Write([]byte(`{"id": 2, "name": "edge", "visibility": "public", "namespace": {"full_path": "nephio"}}`))
+	})
+	mux.HandleFunc("GET /api/v4/personal_access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "7", r.URL.Query().Get("user_id"))
+		// the token is on the second page
+		if r.URL.Query().Get("page") != "2" {
+			next := "http://" + r.Host + r.URL.Path + "?" + r.URL.RawQuery + "&page=2"
+			w.Header().Set("Link", `<`+next+`>; rel="next", <`+next+`>; rel="last"`)
+			_, _ = w.Write([]byte(`[{"id": 5, "name": "other", "scopes": ["api"]}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id": 3, "name": "mgmt-token", "scopes": ["read_repository"]}]`))
+	})
+	mux.HandleFunc("DELETE /api/v4/personal_access_tokens/3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "alice", r.URL.Query().Get("username"))
+		_, _ = w.Write([]byte(`[{"id": 9, "username": "alice"}]`))
+	})
+	mux.HandleFunc("POST /api/v4/projects/{path}/members", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message": "Member already exists"}`))
+	})
+	var member map[string]any
+	mux.HandleFunc("PUT /api/v4/projects/{path}/members/9", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&member))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := New(ProviderGitLab, Config{URL: server.URL, Password: "secret"})
+	require.NoError(t, err)
+
+	u, err := p.GetMyUserInfo()
+	require.NoError(t, err)
+	require.Equal(t, "nephio", u.UserName)
+
+	repo, err := p.GetRepo("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Equal(t, &Repository{
+		ID:       1,
+		Owner:    "nephio",
+		Name:     "mgmt",
+		Private:  true,
+		CloneURL: "https://gitlab.example.com/nephio/mgmt.git",
+	}, repo)
+
+	_, err = p.GetRepo("nephio", "other")
+	require.True(t, IsNotFound(err))
+
+	repo, err = p.CreateRepo(CreateRepoOption{Name: "edge", AutoInit: true, License: "MIT"})
+	require.NoError(t, err)
+	require.False(t, repo.Private)
+	require.Equal(t, map[string]any{
+		"name":                   "edge",
+		"path":                   "edge",
+		"description":            "",
+		"visibility":             "public",
+		"initialize_with_readme": true,
+	}, created)
+
+	// an existing member gets its access level updated
+	require.NoError(t, p.AddRepoCollaborator("nephio", "mgmt", "alice", PermissionWrite))
+	require.Equal(t, float64(30), member["access_level"])
+
+	require.NoError(t, p.DeleteAccessToken("mgmt-token"))
+	require.True(t, IsNotFound(p.DeleteAccessToken("other-token")))
+}
+
+func TestGitLabProtectionAndHooks(t *testing.T) {
+	var protection, hook map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{path}/protected_branches/main", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("POST /api/v4/projects/{path}/protected_branches", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&protection))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/{path}/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 4, "url": "http://porch/webhook", "push_events": true, "tag_push_events": true}]`))
+	})
+	mux.HandleFunc("PUT /api/v4/projects/{path}/hooks/4", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&hook))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p, err := New(ProviderGitLab, Config{URL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	_, err = p.GetBranchProtection("nephio", "mgmt", "main")
+	require.True(t, IsNotFound(err))
+	require.NoError(t, p.SetBranchProtection("nephio", "mgmt", BranchProtection{Branch: "main", BlockForcePush: true}))
+	require.Equal(t, map[string]any{"name": "main", "allow_force_push": false}, protection)
+	err = p.SetBranchProtection("nephio", "mgmt", BranchProtection{Branch: "main", RequiredApprovals: 1})
+	require.True(t, errors.Is(err, ErrNotSupported))
+
+	hooks, err := p.ListRepoHooks("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Equal(t, []*Hook{{ID: 4, URL: "http://porch/webhook", ContentType: "json", Events: []string{"push", "create"}, Active: true}}, hooks)
+
+	require.NoError(t, p.EditRepoHook("nephio", "mgmt", Hook{ID: 4, URL: "http://porch/webhook", Events: []string{"pull_request"}, Active: true}))
+	require.Equal(t, map[string]any{
+		"url":                   "http://porch/webhook",
+		"push_events":           false,
+		"merge_requests_events": true,
+		"tag_push_events":       false,
+	}, hook)
+	err = p.EditRepoHook("nephio", "mgmt", Hook{ID: 4, URL: "http://porch/webhook", Events: []string{"delete"}, Active: true})
+	require.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestGitLabRequiresToken(t *testing.T) {
+	_, err := NewGitLab(Config{URL: "https://gitlab.example.com", Username: "nephio"})
+	require.Error(t, err)
+}
+
+func TestFromGitLabScopes(t *testing.T) {
+	cases := map[string]struct {
+		scopes []string
+		want   []AccessTokenScope
+	}{
+		"read": {
+			scopes: []string{"read_repository"},
+			want:   []AccessTokenScope{AccessTokenScopeRepoRead},
+		},
+		"read and write": {
+			scopes: toGitLabScopes([]AccessTokenScope{AccessTokenScopeRepo}),
+			want:   []AccessTokenScope{AccessTokenScopeRepo},
+		},
+		"other": {
+			scopes: []string{"api", "read_repository"},
+			want:   []AccessTokenScope{"api", AccessTokenScopeRepoRead},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, fromGitLabScopes(tc.scopes))
+		})
+	}
+}
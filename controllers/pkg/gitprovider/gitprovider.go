@@ -0,0 +1,273 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package gitprovider abstracts the git servers the repository and token
+// reconcilers manage repositories and access tokens on.
+package gitprovider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	ProviderGitea  = "gitea"
+	ProviderGitLab = "gitlab"
+	ProviderGitHub = "github"
+)
+
+var (
+	// ErrNotFound is returned, wrapped, when a repository or token does not
+	// exist on the git server
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned, wrapped, when the git server reports a
+	// conflict with an existing resource
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrNotSupported is returned, wrapped, when the git server does not
+	// support an operation
+	ErrNotSupported = errors.New("not supported")
+)
+
+// IsNotFound returns true if the error reports a missing repository or token
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// GitProvider manages repositories and access tokens on a git server on
+// behalf of the authenticated user. Repositories are owned by the user or by
+// an organization. Options a provider has no equivalent for are ignored by
+// that provider.
+type GitProvider interface {
+	GetMyUserInfo() (*User, error)
+	GetOrg(name string) (*Organization, error)
+	CreateOrg(opt CreateOrgOption) (*Organization, error)
+	GetRepo(owner string, name string) (*Repository, error)
+	CreateRepo(opt CreateRepoOption) (*Repository, error)
+	EditRepo(owner string, name string, opt EditRepoOption) (*Repository, error)
+	DeleteRepo(owner string, name string) error
+	// AddRepoTeam grants a team of the organization owning the repository
+	// access to the repository
+	AddRepoTeam(owner string, name string, team string, permission Permission) error
+	// AddRepoCollaborator grants a user access to the repository
+	AddRepoCollaborator(owner string, name string, user string, permission Permission) error
+	// GetBranchProtection gets the protection of a branch, it returns
+	// ErrNotFound if the branch is not protected
+	GetBranchProtection(owner string, name string, branch string) (*BranchProtection, error)
+	// SetBranchProtection protects a branch, or updates its protection
+	SetBranchProtection(owner string, name string, protection BranchProtection) error
+	ListRepoHooks(owner string, name string) ([]*Hook, error)
+	CreateRepoHook(owner string, name string, hook Hook) (*Hook, error)
+	// EditRepoHook updates the hook with the ID of the given hook
+	EditRepoHook(owner string, name string, hook Hook) error
+	DeleteRepoHook(owner string, name string, id int64) error
+	ListDeployKeys(owner string, name string) ([]*DeployKey, error)
+	CreateDeployKey(owner string, name string, key DeployKey) (*DeployKey, error)
+	DeleteDeployKey(owner string, name string, id int64) error
+	ListAccessTokens() ([]*AccessToken, error)
+	CreateAccessToken(opt CreateAccessTokenOption) (*AccessToken, error)
+	DeleteAccessToken(name string) error
+}
+
+type User struct {
+	ID       int64
+	UserName string
+	Email    string
+}
+
+type Organization struct {
+	ID          int64
+	Name        string
+	Description string
+}
+
+type CreateOrgOption struct {
+	Name        string
+	Description string
+	Private     bool
+}
+
+// Permission is the access a team or collaborator has to a repository
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	PermissionAdmin Permission = "admin"
+)
+
+// ParsePermission validates a permission
+func ParsePermission(s string) (Permission, error) {
+	switch p := Permission(s); p {
+	case PermissionRead, PermissionWrite, PermissionAdmin:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid permission %q, expected one of %s, %s or %s", s, PermissionRead, PermissionWrite, PermissionAdmin)
+}
+
+type Repository struct {
+	ID            int64
+	Owner         string
+	Name          string
+	Description   string
+	Private       bool
+	DefaultBranch string
+	CloneURL      string
+	SSHURL        string
+	HTMLURL       string
+}
+
+type CreateRepoOption struct {
+	// Owner is the organization to create the repository in, the repository
+	// is created for the authenticated user if empty
+	Owner         string
+	Name          string
+	Description   string
+	Private       bool
+	IssueLabels   string
+	Gitignores    string
+	License       string
+	Readme        string
+	DefaultBranch string
+	TrustModel    string
+	// AutoInit initializes the repository with an initial commit
+	AutoInit bool
+}
+
+// EditRepoOption holds the repository settings to update; nil fields are
+// left unchanged
+type EditRepoOption struct {
+	Name        *string
+	Description *string
+	Private     *bool
+}
+
+// BranchProtection holds the rules enforced on a branch
+type BranchProtection struct {
+	Branch string
+	// RequiredApprovals is the number of approving reviews required to merge
+	// a pull request
+	RequiredApprovals int64
+	// StatusChecks are the status check contexts required to pass before a
+	// pull request is merged
+	StatusChecks []string
+	// BlockForcePush rejects force pushes to the branch
+	BlockForcePush bool
+}
+
+// Hook event names, as used by Gitea and GitHub
+const (
+	HookEventPush        = "push"
+	HookEventPullRequest = "pull_request"
+	HookEventCreate      = "create"
+	HookEventDelete      = "delete"
+)
+
+// Hook is a webhook of a repository
+type Hook struct {
+	ID  int64
+	URL string
+	// ContentType is json or form
+	ContentType string
+	Events      []string
+	Active      bool
+	// Secret signs the payloads; it is only sent to the git server, never
+	// returned by it
+	Secret string
+}
+
+// DeployKey is an SSH key granting access to a single repository
+type DeployKey struct {
+	ID    int64
+	Title string
+	// Key is the public key in authorized_keys format
+	Key      string
+	ReadOnly bool
+}
+
+// AccessTokenScope is a provider neutral token scope, mapped by each provider
+// to its own scopes
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeRepo grants read and write access to repositories
+	AccessTokenScopeRepo AccessTokenScope = "repo"
+	// AccessTokenScopeRepoRead grants read access to repositories
+	AccessTokenScopeRepoRead AccessTokenScope = "repo:read"
+)
+
+type AccessToken struct {
+	ID     int64
+	Name   string
+	Scopes []AccessTokenScope
+	// Token is the secret value, only returned when the token is created
+	Token string
+}
+
+type CreateAccessTokenOption struct {
+	Name   string
+	Scopes []AccessTokenScope
+}
+
+// Config holds the address of the git server and the credentials of the user
+// the provider authenticates as. Providers authenticating with a token use
+// Token, or Password if Token is not set.
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	Token    string
+}
+
+func (c Config) token() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return c.Password
+}
+
+// Factory creates a GitProvider for a git server
+type Factory func(cfg Config) (GitProvider, error)
+
+var providers = map[string]Factory{
+	ProviderGitea:  NewGitea,
+	ProviderGitLab: NewGitLab,
+	ProviderGitHub: NewGitHub,
+}
+
+// Register adds a provider, or replaces the provider with the same name
+func Register(name string, factory Factory) {
+	providers[name] = factory
+}
+
+// New creates a GitProvider of the named provider
+func New(name string, cfg Config) (GitProvider, error) {
+	factory, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown git provider %q, supported providers: %v", name, Names())
+	}
+	return factory(cfg)
+}
+
+// Names returns the names of the registered providers
+func Names() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
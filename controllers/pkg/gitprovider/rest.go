@@ -0,0 +1,138 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const restTimeout = 30 * time.Second
+
+// restClient is a minimal JSON client for the REST APIs of the git servers
+// that have no SDK in this module
+type restClient struct {
+	baseURL string
+	header  http.Header
+	client  *http.Client
+}
+
+func newRESTClient(baseURL string, header http.Header) *restClient {
+	return &restClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		header:  header,
+		client:  &http.Client{Timeout: restTimeout},
+	}
+}
+
+// do sends the request with in as JSON body, if not nil, and decodes the
+// response into out, if not nil. A 404 response is reported as ErrNotFound
+// and a 409 response as ErrAlreadyExists.
+func (r *restClient) do(method, path string, in, out any) error {
+	_, err := r.send(method, r.baseURL+path, in, out)
+	return err
+}
+
+// list gets all the pages of a list, following the next links of the Link
+// response headers that GitLab and GitHub return for paginated lists
+func list[T any](r *restClient, path string) ([]T, error) {
+	var items []T
+	next := r.baseURL + path
+	for next != "" {
+		var page []T
+		header, err := r.send(http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		next = nextLink(header)
+		// the credentials are only sent to the git server
+		if next != "" && !strings.HasPrefix(next, r.baseURL+"/") {
+			return nil, fmt.Errorf("next page %s is not on the git server", next)
+		}
+	}
+	return items, nil
+}
+
+// nextLink returns the URL of the next page of a paginated list, empty for
+// the last page
+func nextLink(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+func (r *restClient) send(method, url string, in, out any) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	path := strings.TrimPrefix(url, r.baseURL)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, method, path)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("%w: %s %s: %s", ErrAlreadyExists, method, path, strings.TrimSpace(string(data)))
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil || len(data) == 0 {
+		return resp.Header, nil
+	}
+	return resp.Header, json.Unmarshal(data, out)
+}
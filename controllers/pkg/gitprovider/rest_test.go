@@ -0,0 +1,47 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitprovider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextLink(t *testing.T) {
+	cases := map[string]struct {
+		link string
+		want string
+	}{
+		"None": {},
+		"Last page": {
+			link: `<https://git.example.com/api/v4/hooks?page=1>; rel="first"`,
+		},
+		"Next page": {
+			link: `<https://git.example.com/api/v4/hooks?page=1>; rel="prev", <https://git.example.com/api/v4/hooks?page=3>; rel="next"`,
+			want: "https://git.example.com/api/v4/hooks?page=3",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.link != "" {
+				header.Set("Link", tc.link)
+			}
+			require.Equal(t, tc.want, nextLink(header))
+		})
+	}
+}
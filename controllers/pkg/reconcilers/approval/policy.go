@@ -0,0 +1,225 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Policy decides whether a PackageRevision may be approved. Policies are only
+// evaluated once the readiness gates and the owning PackageVariant are Ready.
+// The reader is backed by the cache of the controller, which maintains the
+// field indexes defined in this package.
+type Policy interface {
+	Evaluate(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error)
+}
+
+// PolicyFunc allows an ordinary function to be used as a Policy.
+type PolicyFunc func(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error)
+
+func (f PolicyFunc) Evaluate(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error) {
+	return f(ctx, c, pr)
+}
+
+// Policies holds the approval policies that can be referenced by name from
+// the approval.nephio.org/policy annotation.
+var Policies = map[string]Policy{}
+
+// RegisterPolicy makes a policy available under the given name. It is meant to
+// be called from init functions, in the same way as reconcilerinterface.Register.
+func RegisterPolicy(name string, p Policy) {
+	Policies[name] = p
+}
+
+func init() {
+	RegisterPolicy(InitialPolicyAnnotationValue, PolicyFunc(policyInitial))
+	RegisterPolicy(AlwaysPolicyAnnotationValue, PolicyFunc(policyAlways))
+}
+
+func policyAlways(_ context.Context, _ client.Reader, _ *porchv1alpha1.PackageRevision) (bool, error) {
+	return true, nil
+}
+
+// parsePolicy parses the value of the policy annotation into a Policy. The
+// value is either a single policy name or an expression combining names with
+// "&&" (AND) and "||" (OR); "&&" binds tighter than "||" and parentheses can
+// be used for grouping, e.g. "always && (initial || rollout)".
+func parsePolicy(value string) (Policy, error) {
+	tokens, err := tokenizePolicy(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty policy")
+	}
+
+	p := &policyParser{tokens: tokens}
+	policy, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return policy, nil
+}
+
+func tokenizePolicy(value string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(value); {
+		c := rune(value[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case strings.HasPrefix(value[i:], "&&") || strings.HasPrefix(value[i:], "||"):
+			tokens = append(tokens, value[i:i+2])
+			i += 2
+		case isPolicyNameChar(c):
+			start := i
+			for i < len(value) && isPolicyNameChar(rune(value[i])) {
+				i++
+			}
+			tokens = append(tokens, value[start:i])
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+func isPolicyNameChar(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) ||
+		c == '-' || c == '_' || c == '.' || c == '/')
+}
+
+type policyParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *policyParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *policyParser) parseOr() (Policy, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	policies := []Policy{left}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, right)
+	}
+	if len(policies) == 1 {
+		return left, nil
+	}
+	return anyPolicy(policies), nil
+}
+
+func (p *policyParser) parseAnd() (Policy, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	policies := []Policy{left}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, right)
+	}
+	if len(policies) == 1 {
+		return left, nil
+	}
+	return allPolicy(policies), nil
+}
+
+func (p *policyParser) parseTerm() (Policy, error) {
+	tok := p.peek()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of policy")
+	case "(":
+		p.pos++
+		policy, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return policy, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+
+	p.pos++
+	policy, ok := Policies[tok]
+	if !ok {
+		return nil, fmt.Errorf("unknown policy %q", tok)
+	}
+	return policy, nil
+}
+
+// allPolicy is met when all of its policies are met
+type allPolicy []Policy
+
+func (a allPolicy) Evaluate(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error) {
+	for _, p := range a {
+		ok, err := p.Evaluate(ctx, c, pr)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// anyPolicy is met when at least one of its policies is met
+type anyPolicy []Policy
+
+func (a anyPolicy) Evaluate(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error) {
+	for _, p := range a {
+		ok, err := p.Evaluate(ctx, c, pr)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
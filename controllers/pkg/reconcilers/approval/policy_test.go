@@ -0,0 +1,130 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func staticPolicy(approve bool, err error) Policy {
+	return PolicyFunc(func(_ context.Context, _ client.Reader, _ *porchapi.PackageRevision) (bool, error) {
+		return approve, err
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	registered := Policies
+	t.Cleanup(func() { Policies = registered })
+	Policies = maps.Clone(registered)
+	RegisterPolicy("test-yes", staticPolicy(true, nil))
+	RegisterPolicy("test-no", staticPolicy(false, nil))
+	RegisterPolicy("test-err", staticPolicy(false, fmt.Errorf("failed")))
+
+	testCases := map[string]struct {
+		value           string
+		expectedParse   bool
+		expectedApprove bool
+		expectedError   bool
+	}{
+		"built-in always": {
+			value:           "always",
+			expectedParse:   true,
+			expectedApprove: true,
+		},
+		"unknown policy": {
+			value:         "foo",
+			expectedParse: false,
+		},
+		"empty policy": {
+			value:         " ",
+			expectedParse: false,
+		},
+		"and all met": {
+			value:           "test-yes && always",
+			expectedParse:   true,
+			expectedApprove: true,
+		},
+		"and one not met": {
+			value:           "test-yes&&test-no",
+			expectedParse:   true,
+			expectedApprove: false,
+		},
+		"or one met": {
+			value:           "test-no || test-yes",
+			expectedParse:   true,
+			expectedApprove: true,
+		},
+		"or none met": {
+			value:           "test-no || test-no",
+			expectedParse:   true,
+			expectedApprove: false,
+		},
+		"and binds tighter than or": {
+			value:           "test-yes || test-no && test-no",
+			expectedParse:   true,
+			expectedApprove: true,
+		},
+		"parentheses": {
+			value:           "(test-yes || test-no) && test-no",
+			expectedParse:   true,
+			expectedApprove: false,
+		},
+		"or short-circuits errors": {
+			value:           "test-yes || test-err",
+			expectedParse:   true,
+			expectedApprove: true,
+		},
+		"error propagated": {
+			value:         "test-yes && test-err",
+			expectedParse: true,
+			expectedError: true,
+		},
+		"missing operand": {
+			value:         "test-yes &&",
+			expectedParse: false,
+		},
+		"unbalanced parentheses": {
+			value:         "(test-yes || test-no",
+			expectedParse: false,
+		},
+		"unknown policy in expression": {
+			value:         "test-yes || foo",
+			expectedParse: false,
+		},
+		"invalid character": {
+			value:         "test-yes & test-no",
+			expectedParse: false,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			p, err := parsePolicy(tc.value)
+			require.Equal(t, tc.expectedParse, err == nil)
+			if err != nil {
+				return
+			}
+			actualApprove, actualError := p.Evaluate(context.TODO(), nil, &porchapi.PackageRevision{})
+			require.Equal(t, tc.expectedApprove, actualApprove)
+			require.Equal(t, tc.expectedError, actualError != nil)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/client-go/rest"
 
+	approvalv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/approval/v1alpha1"
 	ctrlconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
 	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,22 +32,31 @@ import (
 
 	"k8s.io/client-go/tools/record"
 
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
 	porchclient "github.com/nephio-project/nephio/controllers/pkg/porch/client"
 	porchutil "github.com/nephio-project/nephio/controllers/pkg/porch/util"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	pvapi "github.com/nephio-project/porch/controllers/packagevariants/api/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
 	DelayAnnotationName          = "approval.nephio.org/delay"
 	PolicyAnnotationName         = "approval.nephio.org/policy"
+	WindowAnnotationName         = "approval.nephio.org/maintenance-window"
+	WindowTimezoneAnnotationName = "approval.nephio.org/maintenance-window-timezone"
 	InitialPolicyAnnotationValue = "initial"
 	AlwaysPolicyAnnotationValue  = "always"
+
+	controllerName = "approval-controller"
 )
 
 func init() {
@@ -58,6 +68,10 @@ func init() {
 // +kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions/approval,verbs=get;update;patch
 // +kubebuilder:rbac:groups=config.porch.kpt.dev,resources=packagevariants,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.porch.kpt.dev,resources=packagevariants/status,verbs=get
+// +kubebuilder:rbac:groups=config.porch.kpt.dev,resources=repositories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=approval.nephio.org,resources=approvalrecords,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c interface{}) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
@@ -66,25 +80,44 @@ func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c i
 		return nil, fmt.Errorf("cannot initialize, expecting controllerConfig, got: %s", reflect.TypeOf(c).Name())
 	}
 
+	if err := approvalv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+
 	r.apiReader = mgr.GetAPIReader()
 	r.baseClient = mgr.GetClient()
 	r.porchRESTClient = cfg.PorchRESTClient
-	r.recorder = mgr.GetEventRecorderFor("approval-controller")
-	r.requeueDuration = time.Duration(cfg.ApprovalRequeueDuration) * time.Second
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
+	r.recordRetention = cfg.ApprovalRecordRetention
 
+	// Besides changes to the PackageRevision itself, a PackageRevision is
+	// reconsidered when its owning PackageVariant changes, when a sibling
+	// PackageRevision its policy may depend on is published or deleted, and
+	// when the workload cluster of a repository of its rollout changes.
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("ApprovalController").
 		For(&porchv1alpha1.PackageRevision{}).
+		Watches(&porchv1alpha1.PackageRevision{}, r.siblingHandler()).
+		Watches(&pvapi.PackageVariant{}, handler.EnqueueRequestsFromMapFunc(r.mapPackageVariantPackageRevisions)).
+		Watches(&capiv1beta1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.mapClusterPackageRevisions),
+			builder.WithPredicates(cluster.SelectionChanged())).
 		Complete(r)
 }
 
+// Indexes returns the indexes of the approval controller and, for the
+// rollout policy, the indexes resolving the workload clusters by name.
+func (r *reconciler) Indexes() []reconcilerinterface.Index {
+	return append(Indexes(), cluster.Indexes()...)
+}
+
 // reconciler reconciles a NetworkInstance object
 type reconciler struct {
 	apiReader       client.Reader
 	baseClient      client.Client
 	porchRESTClient rest.Interface
 	recorder        record.EventRecorder
-	requeueDuration time.Duration
+	// recordRetention is how long the ApprovalRecords are kept, forever if 0
+	recordRetention time.Duration
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -112,7 +145,7 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// of the package variant. If it is not Ready, then we should not approve yet. The
 	// lack of readiness could indicate an error which even impacts whether or not the
 	// readiness gates have been properly set.
-	pvReady, err := porchutil.PackageVariantReady(ctx, pr, r.apiReader)
+	pvReady, err := porchutil.PackageVariantReady(ctx, pr, r.baseClient)
 	if err != nil {
 		r.recorder.Event(pr, corev1.EventTypeWarning,
 			"Error", fmt.Sprintf("could not get owning PackageVariant: %s", err.Error()))
@@ -120,36 +153,33 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// There is no need to requeue, we are triggered when the PackageVariant changes
 	if !pvReady {
 		r.recorder.Eventf(pr, corev1.EventTypeNormal,
 			"NotApproved", "owning PackageVariant for %s not Ready", pr.Spec.PackageName)
 
-		return ctrl.Result{RequeueAfter: r.requeueDuration}, nil
+		return ctrl.Result{}, nil
 	}
 
 	// All policies require readiness gates to be met, so if they
-	// are not, we are done for now.
+	// are not, we are done until the conditions of the PackageRevision change.
 	if !porchv1alpha1.PackageRevisionIsReady(pr.Spec.ReadinessGates, pr.Status.Conditions) {
 		r.recorder.Eventf(pr, corev1.EventTypeNormal,
 			"NotApproved", "readiness gates not met for %s, in repo %s", pr.Spec.PackageName, pr.Spec.RepositoryName)
 
-		return ctrl.Result{RequeueAfter: r.requeueDuration}, nil
+		return ctrl.Result{}, nil
 	}
 
 	// Readiness is met, so check our other policies
-	approve := false
-	switch policy {
-	case InitialPolicyAnnotationValue:
-		approve, err = r.policyInitial(ctx, pr)
-	case AlwaysPolicyAnnotationValue:
-		approve, err = true, nil
-	default:
+	p, err := parsePolicy(policy)
+	if err != nil {
 		r.recorder.Eventf(pr, corev1.EventTypeWarning,
-			"InvalidPolicy", "invalid %q annotation value: %q", PolicyAnnotationName, policy)
+			"InvalidPolicy", "invalid %q annotation value: %q: %s", PolicyAnnotationName, policy, err.Error())
 
 		return ctrl.Result{}, nil
 	}
 
+	approve, err := p.Evaluate(ctx, r.baseClient, pr)
 	if err != nil {
 		r.recorder.Eventf(pr, corev1.EventTypeWarning,
 			"Error", "error evaluating approval policy %q: %s", policy, err.Error())
@@ -157,11 +187,13 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// There is no need to requeue, we are triggered when the state the
+	// policies depend on changes
 	if !approve {
 		r.recorder.Eventf(pr, corev1.EventTypeNormal,
 			"NotApproved", "approval policy %q not met for %s", policy, pr.Spec.PackageName)
 
-		return ctrl.Result{RequeueAfter: r.requeueDuration}, nil
+		return ctrl.Result{}, nil
 	}
 
 	// Delay if needed, and let the user know via an event
@@ -189,13 +221,54 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{RequeueAfter: requeue}, nil
 	}
 
+	// Publishing is only allowed within the maintenance windows, if any are
+	// set. Proposing a Draft is not restricted.
+	if pr.Spec.Lifecycle != porchv1alpha1.PackageRevisionLifecycleDraft {
+		now := time.Now()
+		requeue, err := manageWindow(pr, now)
+		if err != nil {
+			r.recorder.Eventf(pr, corev1.EventTypeWarning,
+				"Error", "error processing %q: %s", WindowAnnotationName, err.Error())
+
+			// Do not propagate the error; it is a user error, as with the delay
+			return ctrl.Result{}, nil
+		}
+
+		if requeue > 0 {
+			r.recorder.Eventf(pr, corev1.EventTypeNormal,
+				"NotApproved", "outside maintenance window, approval will next be attempted at %s",
+				now.Add(requeue).Format(time.RFC3339))
+			return ctrl.Result{RequeueAfter: requeue}, nil
+		}
+	}
+
 	action := "approving"
 	reason := "Approved"
-
-	// All policies met
+	recordAction := approvalv1alpha1.ApprovalActionApproved
 	if pr.Spec.Lifecycle == porchv1alpha1.PackageRevisionLifecycleDraft {
 		action = "proposing"
 		reason = "Proposed"
+		recordAction = approvalv1alpha1.ApprovalActionProposed
+	}
+
+	// Keep a durable record of the decision. The record is created before the
+	// decision is applied, so no decision is applied without an audit trail.
+	pvState, err := packageVariantState(ctx, r.baseClient, pr)
+	if err != nil {
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "could not get owning PackageVariant: %s", err.Error())
+		return ctrl.Result{}, err
+	}
+	record := buildApprovalRecord(pr, recordAction, policy, pvState, time.Now())
+	if err := r.baseClient.Create(ctx, record); err != nil {
+		log.Error(err, "cannot create approval record")
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "error recording %s decision: %s", action, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// All policies met
+	if pr.Spec.Lifecycle == porchv1alpha1.PackageRevisionLifecycleDraft {
 		pr.Spec.Lifecycle = porchv1alpha1.PackageRevisionLifecycleProposed
 		err = r.baseClient.Update(ctx, pr)
 	} else {
@@ -208,12 +281,22 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err != nil {
 		r.recorder.Eventf(pr, corev1.EventTypeWarning,
 			"Error", "error %s: %s", action, err.Error())
-	} else {
-		r.recorder.Eventf(pr, corev1.EventTypeNormal,
-			reason, "all approval policies met for %s: %s", pr.Spec.PackageName, reason)
+		// the decision was not applied, the record of the retry replaces it
+		if err := r.baseClient.Delete(ctx, record); resource.IgnoreNotFound(err) != nil {
+			log.Error(err, "cannot delete approval record")
+		}
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.Eventf(pr, corev1.EventTypeNormal,
+		reason, "all approval policies met for %s: %s", pr.Spec.PackageName, reason)
+
+	// the decision is applied, failing to prune the old records is not retried
+	if err := pruneApprovalRecords(ctx, r.baseClient, pr.Namespace, r.recordRetention, time.Now()); err != nil {
+		log.Error(err, "cannot prune approval records")
 	}
 
-	return ctrl.Result{}, err
+	return ctrl.Result{}, nil
 }
 
 func shouldProcess(pr *porchv1alpha1.PackageRevision) (string, bool) {
@@ -252,9 +335,10 @@ func manageDelay(pr *porchv1alpha1.PackageRevision) (time.Duration, error) {
 	return d, nil
 }
 
-func (r *reconciler) policyInitial(ctx context.Context, pr *porchv1alpha1.PackageRevision) (bool, error) {
+func policyInitial(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error) {
 	var prList porchv1alpha1.PackageRevisionList
-	if err := r.apiReader.List(ctx, &prList); err != nil {
+	if err := c.List(ctx, &prList, client.InNamespace(pr.Namespace),
+		client.MatchingFields{PackageIndexKey: PackageKey(pr)}); err != nil {
 		return false, err
 	}
 
@@ -231,14 +231,12 @@ func TestPolicyInitial(t *testing.T) {
 	for tn, tc := range testCases {
 		// Create a new instance of the mock object
 		readerMock := new(mockReader.MockReader)
-		readerMock.On("List", context.TODO(), mock.AnythingOfType("*v1alpha1.PackageRevisionList")).Return(tc.mockReturnErr).Run(func(args mock.Arguments) {
+		readerMock.On("List", context.TODO(), mock.AnythingOfType("*v1alpha1.PackageRevisionList"), mock.Anything, mock.Anything).Return(tc.mockReturnErr).Run(func(args mock.Arguments) {
 			packRevList := args.Get(1).(*porchapi.PackageRevisionList)
 			*packRevList = *tc.prl // tc.prl is what r.Get will store in 2nd Argument
 		})
-		// Create an instance of the component under test
-		r := reconciler{apiReader: readerMock}
 		t.Run(tn, func(t *testing.T) {
-			actualApproval, actualError := r.policyInitial(context.TODO(), &tc.pr)
+			actualApproval, actualError := policyInitial(context.TODO(), readerMock, &tc.pr)
 			require.Equal(t, tc.expectedApprove, actualApproval)
 			require.Equal(t, tc.expectedError, actualError)
 		})
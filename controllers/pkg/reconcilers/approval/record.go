@@ -0,0 +1,138 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"time"
+
+	approvalv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/approval/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	pvapi "github.com/nephio-project/porch/controllers/packagevariants/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// buildApprovalRecord captures the inputs of an approval decision in an
+// ApprovalRecord. The record is deliberately not owned by the PackageRevision,
+// so it outlives the PackageRevision it describes; records older than the
+// retention are pruned by pruneApprovalRecords.
+func buildApprovalRecord(pr *porchv1alpha1.PackageRevision, action approvalv1alpha1.ApprovalAction, policy string, pv *approvalv1alpha1.PackageVariantState, now time.Time) *approvalv1alpha1.ApprovalRecord {
+	annotations := pr.GetAnnotations()
+
+	var gates []approvalv1alpha1.ReadinessGateState
+	for _, gate := range pr.Spec.ReadinessGates {
+		state := approvalv1alpha1.ReadinessGateState{
+			ConditionType: gate.ConditionType,
+			Status:        string(porchv1alpha1.ConditionUnknown),
+		}
+		for _, cond := range pr.Status.Conditions {
+			if cond.Type != gate.ConditionType {
+				continue
+			}
+			state.Status = string(cond.Status)
+			state.Reason = cond.Reason
+			state.Message = cond.Message
+			break
+		}
+		gates = append(gates, state)
+	}
+
+	// the delay was validated before the decision was taken
+	var delay *metav1.Duration
+	if value, ok := annotations[DelayAnnotationName]; ok {
+		if d, err := time.ParseDuration(value); err == nil {
+			delay = &metav1.Duration{Duration: d}
+		}
+	}
+
+	return &approvalv1alpha1.ApprovalRecord{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: approvalv1alpha1.GroupVersion.String(),
+			Kind:       approvalv1alpha1.ApprovalRecordKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pr.Name + "-",
+			Namespace:    pr.Namespace,
+		},
+		Spec: approvalv1alpha1.ApprovalRecordSpec{
+			PackageRevision:           pr.Name,
+			PackageName:               pr.Spec.PackageName,
+			RepositoryName:            pr.Spec.RepositoryName,
+			WorkspaceName:             pr.Spec.WorkspaceName,
+			Revision:                  pr.Spec.Revision,
+			Action:                    action,
+			Policy:                    policy,
+			ReadinessGates:            gates,
+			PackageVariant:            pv,
+			Delay:                     delay,
+			MaintenanceWindow:         annotations[WindowAnnotationName],
+			MaintenanceWindowTimezone: annotations[WindowTimezoneAnnotationName],
+			Actor:                     controllerName,
+			Timestamp:                 metav1.NewTime(now),
+		},
+	}
+}
+
+// packageVariantState returns the state of the Ready condition of the
+// PackageVariant owning the PackageRevision, nil if the PackageRevision is not
+// owned by a PackageVariant
+func packageVariantState(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (*approvalv1alpha1.PackageVariantState, error) {
+	owners := packageVariantIndex(pr)
+	if len(owners) == 0 {
+		return nil, nil
+	}
+	pv := &pvapi.PackageVariant{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: pr.Namespace, Name: owners[0]}, pv); err != nil {
+		return nil, err
+	}
+	state := &approvalv1alpha1.PackageVariantState{Name: pv.Name, Ready: string(metav1.ConditionUnknown)}
+	for _, cond := range pv.Status.Conditions {
+		if cond.Type != "Ready" {
+			continue
+		}
+		state.Ready = string(cond.Status)
+		state.Reason = cond.Reason
+		state.Message = cond.Message
+		break
+	}
+	return state, nil
+}
+
+// pruneApprovalRecords deletes the records of the namespace taken longer than
+// the retention ago, no record is deleted if the retention is 0
+func pruneApprovalRecords(ctx context.Context, c client.Client, namespace string, retention time.Duration, now time.Time) error {
+	if retention <= 0 {
+		return nil
+	}
+	records := &approvalv1alpha1.ApprovalRecordList{}
+	if err := c.List(ctx, records, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for i := range records.Items {
+		record := &records.Items[i]
+		if now.Sub(record.Spec.Timestamp.Time) <= retention {
+			continue
+		}
+		if err := c.Delete(ctx, record); resource.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
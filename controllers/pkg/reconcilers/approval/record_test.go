@@ -0,0 +1,153 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	approvalv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/approval/v1alpha1"
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	pvapi "github.com/nephio-project/porch/controllers/packagevariants/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildApprovalRecord(t *testing.T) {
+	now := time.Date(2023, time.June, 7, 12, 0, 0, 0, time.UTC)
+	pr := &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge01.free5gc-upf.v1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"approval.nephio.org/policy": "initial",
+				"approval.nephio.org/delay":  "2m",
+			},
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    "free5gc-upf",
+			RepositoryName: "edge01",
+			WorkspaceName:  "v1",
+			Revision:       1,
+			ReadinessGates: []porchapi.ReadinessGate{
+				{ConditionType: "config.injection.Ready"},
+				{ConditionType: "nephio.org.Specializer.specialize"},
+			},
+		},
+		Status: porchapi.PackageRevisionStatus{
+			Conditions: []porchapi.Condition{
+				{Type: "config.injection.Ready", Status: porchapi.ConditionTrue, Reason: "Ready", Message: "injected"},
+			},
+		},
+	}
+
+	pv := &approvalv1alpha1.PackageVariantState{Name: "upf-edge01", Ready: "True", Reason: "NoErrors"}
+	record := buildApprovalRecord(pr, approvalv1alpha1.ApprovalActionApproved, "initial", pv, now)
+
+	require.Equal(t, "edge01.free5gc-upf.v1-", record.GenerateName)
+	require.Equal(t, "default", record.Namespace)
+	require.Empty(t, record.OwnerReferences)
+	require.Equal(t, approvalv1alpha1.ApprovalRecordSpec{
+		PackageRevision: "edge01.free5gc-upf.v1",
+		PackageName:     "free5gc-upf",
+		RepositoryName:  "edge01",
+		WorkspaceName:   "v1",
+		Revision:        1,
+		Action:          approvalv1alpha1.ApprovalActionApproved,
+		Policy:          "initial",
+		ReadinessGates: []approvalv1alpha1.ReadinessGateState{
+			{ConditionType: "config.injection.Ready", Status: "True", Reason: "Ready", Message: "injected"},
+			{ConditionType: "nephio.org.Specializer.specialize", Status: "Unknown"},
+		},
+		PackageVariant: pv,
+		Delay:          &metav1.Duration{Duration: 2 * time.Minute},
+		Actor:          "approval-controller",
+		Timestamp:      metav1.NewTime(now),
+	}, record.Spec)
+}
+
+func TestPackageVariantState(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, pvapi.AddToScheme(scheme))
+	pv := &pvapi.PackageVariant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "upf-edge01"},
+		Status: pvapi.PackageVariantStatus{Conditions: []metav1.Condition{
+			{Type: "Stalled", Status: metav1.ConditionFalse, Reason: "Valid"},
+			{Type: "Ready", Status: metav1.ConditionTrue, Reason: "NoErrors", Message: "successfully ensured downstream package variant"},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pv).Build()
+
+	owned := &porchapi.PackageRevision{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "edge01.free5gc-upf.v1",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: pvapi.GroupVersion.String(),
+			Kind:       "PackageVariant",
+			Name:       "upf-edge01",
+			Controller: ptr.To(true),
+		}},
+	}}
+	state, err := packageVariantState(ctx, c, owned)
+	require.NoError(t, err)
+	require.Equal(t, &approvalv1alpha1.PackageVariantState{
+		Name:    "upf-edge01",
+		Ready:   "True",
+		Reason:  "NoErrors",
+		Message: "successfully ensured downstream package variant",
+	}, state)
+
+	state, err = packageVariantState(ctx, c, &porchapi.PackageRevision{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "standalone"}})
+	require.NoError(t, err)
+	require.Nil(t, state)
+}
+
+func TestPruneApprovalRecords(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.June, 7, 12, 0, 0, 0, time.UTC)
+	scheme := runtime.NewScheme()
+	require.NoError(t, approvalv1alpha1.AddToScheme(scheme))
+	newRecord := func(name string, age time.Duration) *approvalv1alpha1.ApprovalRecord {
+		return &approvalv1alpha1.ApprovalRecord{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       approvalv1alpha1.ApprovalRecordSpec{Timestamp: metav1.NewTime(now.Add(-age))},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newRecord("recent", time.Hour),
+		newRecord("old", 100*24*time.Hour),
+	).Build()
+	list := func() []string {
+		records := &approvalv1alpha1.ApprovalRecordList{}
+		require.NoError(t, c.List(ctx, records))
+		names := []string{}
+		for _, record := range records.Items {
+			names = append(names, record.Name)
+		}
+		return names
+	}
+
+	// no retention keeps the records forever
+	require.NoError(t, pruneApprovalRecords(ctx, c, "default", 0, now))
+	require.ElementsMatch(t, []string{"recent", "old"}, list())
+
+	require.NoError(t, pruneApprovalRecords(ctx, c, "default", 90*24*time.Hour, now))
+	require.Equal(t, []string{"recent"}, list())
+}
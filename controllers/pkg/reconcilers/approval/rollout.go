@@ -0,0 +1,260 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	pvapi "github.com/nephio-project/porch/controllers/packagevariants/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RolloutAnnotationName        = "approval.nephio.org/rollout"
+	RolloutWavesAnnotationName   = "approval.nephio.org/rollout-waves"
+	RolloutTargetsAnnotationName = "approval.nephio.org/rollout-targets"
+	RolloutPolicyAnnotationValue = "rollout"
+
+	clusterNameLabelKey = "nephio.org/cluster-name"
+	// packageVariantSetOwnerLabelKey is set by the PackageVariantSet
+	// controller on the PackageVariants it creates, to the UID of the
+	// PackageVariantSet
+	packageVariantSetOwnerLabelKey = "config.porch.kpt.dev/packagevariantset"
+)
+
+func init() {
+	RegisterPolicy(RolloutPolicyAnnotationValue, PolicyFunc(policyRollout))
+}
+
+// rolloutWave selects the repositories that belong to a wave, either by
+// label selector or by a percentage of all the repositories in the rollout.
+// A wave with neither selects all remaining repositories.
+type rolloutWave struct {
+	selector labels.Selector
+	percent  int
+}
+
+// rolloutTarget is a repository that is part of a rollout
+type rolloutTarget struct {
+	repository  string
+	clusterName string
+	labels      labels.Set
+	published   bool
+}
+
+// policyRollout approves a PackageRevision once all repositories in the
+// earlier waves of its rollout have a Published revision of the rollout and
+// their workload clusters are Ready. The PackageRevisions of a rollout are
+// those in the same namespace with the same rollout annotation value; the
+// repositories of the rollout also include those it targets that have no
+// PackageRevision yet, see rolloutRepositories.
+func policyRollout(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) (bool, error) {
+	rollout, ok := pr.GetAnnotations()[RolloutAnnotationName]
+	if !ok {
+		return false, fmt.Errorf("missing %q annotation", RolloutAnnotationName)
+	}
+	waves, err := parseRolloutWaves(pr.GetAnnotations()[RolloutWavesAnnotationName])
+	if err != nil {
+		return false, fmt.Errorf("invalid %q annotation: %s", RolloutWavesAnnotationName, err.Error())
+	}
+
+	repositories, err := rolloutRepositories(ctx, c, pr)
+	if err != nil {
+		return false, err
+	}
+	published := map[string]bool{}
+	for _, repoName := range repositories {
+		published[repoName] = false
+	}
+
+	var prList porchv1alpha1.PackageRevisionList
+	if err := c.List(ctx, &prList, client.InNamespace(pr.Namespace),
+		client.MatchingFields{RolloutIndexKey: rollout}); err != nil {
+		return false, err
+	}
+	for _, pr2 := range prList.Items {
+		if pr2.GetAnnotations()[RolloutAnnotationName] != rollout {
+			continue
+		}
+		published[pr2.Spec.RepositoryName] = published[pr2.Spec.RepositoryName] ||
+			porchv1alpha1.LifecycleIsPublished(pr2.Spec.Lifecycle)
+	}
+
+	targets := make([]rolloutTarget, 0, len(published))
+	for repoName, isPublished := range published {
+		target := rolloutTarget{
+			repository:  repoName,
+			clusterName: repoName,
+			published:   isPublished,
+		}
+		repo := &porchconfig.Repository{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: pr.Namespace, Name: repoName}, repo); err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+		}
+		target.labels = repo.GetLabels()
+		if clusterName, ok := target.labels[clusterNameLabelKey]; ok {
+			target.clusterName = clusterName
+		}
+		targets = append(targets, target)
+	}
+
+	assigned := assignRolloutWaves(waves, targets)
+	wave := assigned[pr.Spec.RepositoryName]
+	if wave == 0 {
+		return true, nil
+	}
+
+	// all repositories of the earlier waves must be published and healthy
+	for _, target := range targets {
+		if assigned[target.repository] >= wave {
+			continue
+		}
+		if !target.published {
+			return false, nil
+		}
+		cl, err := cluster.GetCapiCluster(ctx, c, target.clusterName)
+		if err != nil {
+			return false, err
+		}
+		if cl == nil || !capi.IsClusterReady(cl) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// rolloutRepositories returns the repositories targeted by the rollout of the
+// PackageRevision, whether or not they have a PackageRevision of the rollout
+// yet: the repositories declared in its rollout-targets annotation, and the
+// downstream repositories of the PackageVariants created by the
+// PackageVariantSet that created its PackageVariant.
+func rolloutRepositories(ctx context.Context, c client.Reader, pr *porchv1alpha1.PackageRevision) ([]string, error) {
+	repositories := []string{pr.Spec.RepositoryName}
+	for _, repoName := range strings.Split(pr.GetAnnotations()[RolloutTargetsAnnotationName], ",") {
+		if repoName = strings.TrimSpace(repoName); repoName != "" {
+			repositories = append(repositories, repoName)
+		}
+	}
+
+	pvNames := packageVariantIndex(pr)
+	if len(pvNames) == 0 {
+		return repositories, nil
+	}
+	pv := &pvapi.PackageVariant{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: pr.Namespace, Name: pvNames[0]}, pv); err != nil {
+		return nil, resource.IgnoreNotFound(err)
+	}
+	pvsUID, ok := pv.GetLabels()[packageVariantSetOwnerLabelKey]
+	if !ok {
+		return repositories, nil
+	}
+	var pvList pvapi.PackageVariantList
+	if err := c.List(ctx, &pvList, client.InNamespace(pr.Namespace),
+		client.MatchingLabels{packageVariantSetOwnerLabelKey: pvsUID}); err != nil {
+		return nil, err
+	}
+	for _, pv := range pvList.Items {
+		if pv.Spec.Downstream != nil && pv.Spec.Downstream.Repo != "" {
+			repositories = append(repositories, pv.Spec.Downstream.Repo)
+		}
+	}
+	return repositories, nil
+}
+
+// parseRolloutWaves parses the waves of a rollout separated by ";". Each wave
+// is a label selector over the labels of the target repositories, a
+// percentage such as "10%", or "*" for all remaining repositories.
+func parseRolloutWaves(value string) ([]rolloutWave, error) {
+	var waves []rolloutWave
+	for _, s := range strings.Split(value, ";") {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			continue
+		case s == "*":
+			waves = append(waves, rolloutWave{})
+		case strings.HasSuffix(s, "%"):
+			percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+			if err != nil || percent <= 0 || percent > 100 {
+				return nil, fmt.Errorf("invalid percentage %q", s)
+			}
+			waves = append(waves, rolloutWave{percent: percent})
+		default:
+			selector, err := labels.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			waves = append(waves, rolloutWave{selector: selector})
+		}
+	}
+	if len(waves) == 0 {
+		return nil, fmt.Errorf("no rollout waves specified")
+	}
+	return waves, nil
+}
+
+// assignRolloutWaves returns the index of the wave each repository belongs
+// to. Repositories are assigned to the first wave that selects them, in
+// repository name order, so the assignment is stable across reconciles.
+// Repositories not selected by any wave belong to an implicit final wave.
+func assignRolloutWaves(waves []rolloutWave, targets []rolloutTarget) map[string]int {
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].repository < targets[j].repository
+	})
+
+	assigned := map[string]int{}
+	for i, wave := range waves {
+		count := 0
+		limit := len(targets)
+		if wave.percent > 0 {
+			limit = (len(targets)*wave.percent + 99) / 100
+		}
+		for _, target := range targets {
+			if count >= limit {
+				break
+			}
+			if _, ok := assigned[target.repository]; ok {
+				continue
+			}
+			if wave.selector != nil && !wave.selector.Matches(target.labels) {
+				continue
+			}
+			assigned[target.repository] = i
+			count++
+		}
+	}
+
+	for _, target := range targets {
+		if _, ok := assigned[target.repository]; !ok {
+			assigned[target.repository] = len(waves)
+		}
+	}
+	return assigned
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	pvapi "github.com/nephio-project/porch/controllers/packagevariants/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAssignRolloutWaves(t *testing.T) {
+	targets := []rolloutTarget{
+		{repository: "edge05", labels: labels.Set{"tier": "edge"}},
+		{repository: "edge01", labels: labels.Set{"tier": "edge"}},
+		{repository: "canary", labels: labels.Set{"tier": "canary"}},
+		{repository: "edge02", labels: labels.Set{"tier": "edge"}},
+		{repository: "edge03", labels: labels.Set{"tier": "edge"}},
+		{repository: "core", labels: labels.Set{"tier": "core"}},
+	}
+	testCases := map[string]struct {
+		waves    string
+		expected map[string]int
+	}{
+		"canary, percentage, rest": {
+			waves: "tier=canary; 25%; *",
+			expected: map[string]int{
+				"canary": 0,
+				"core":   1,
+				"edge01": 1,
+				"edge02": 2,
+				"edge03": 2,
+				"edge05": 2,
+			},
+		},
+		"unselected repositories go last": {
+			waves: "tier=canary; tier in (edge)",
+			expected: map[string]int{
+				"canary": 0,
+				"core":   2,
+				"edge01": 1,
+				"edge02": 1,
+				"edge03": 1,
+				"edge05": 1,
+			},
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			waves, err := parseRolloutWaves(tc.waves)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, assignRolloutWaves(waves, targets))
+		})
+	}
+}
+
+func TestParseRolloutWaves(t *testing.T) {
+	for _, value := range []string{"", " ; ", "0%", "101%", "abc%", "tier in edge"} {
+		_, err := parseRolloutWaves(value)
+		require.Error(t, err, value)
+	}
+}
+
+func rolloutPackageRevision(repo string, lifecycle porchapi.PackageRevisionLifecycle, waves string) *porchapi.PackageRevision {
+	return &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repo + ".upf.v2",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"approval.nephio.org/policy":        "rollout",
+				"approval.nephio.org/rollout":       "upf-v2",
+				"approval.nephio.org/rollout-waves": waves,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: porchconfig.GroupVersion.String(),
+				Kind:       "PackageVariant",
+				Name:       "upf-" + repo,
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    "upf",
+			Lifecycle:      lifecycle,
+		},
+	}
+}
+
+func rolloutPackageVariant(repo string) *pvapi.PackageVariant {
+	return &pvapi.PackageVariant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "upf-" + repo,
+			Namespace: "default",
+			Labels:    map[string]string{"config.porch.kpt.dev/packagevariantset": "upf-uid"},
+		},
+		Spec: pvapi.PackageVariantSpec{
+			Downstream: &pvapi.Downstream{Repo: repo, Package: "upf"},
+		},
+	}
+}
+
+func rolloutRepository(name, tier string) *porchconfig.Repository {
+	return &porchconfig.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"tier": tier},
+		},
+	}
+}
+
+func rolloutCluster(namespace, name string, ready corev1.ConditionStatus) *capiv1beta1.Cluster {
+	return &capiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: capiv1beta1.ClusterStatus{
+			Conditions: capiv1beta1.Conditions{
+				{Type: capiv1beta1.ReadyCondition, Status: ready},
+			},
+		},
+	}
+}
+
+func TestPolicyRollout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, porchapi.AddToScheme(scheme))
+	require.NoError(t, porchconfig.AddToScheme(scheme))
+	require.NoError(t, pvapi.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+
+	testCases := map[string]struct {
+		repo            string
+		waves           string
+		canaryLifecycle porchapi.PackageRevisionLifecycle
+		canaryReady     corev1.ConditionStatus
+		// objects besides the canary, edge01 and edge02 repositories and
+		// the canary cluster
+		objects         []client.Object
+		expectedApprove bool
+		expectedErr     bool
+	}{
+		"first wave is approved": {
+			repo:            "canary",
+			canaryLifecycle: porchapi.PackageRevisionLifecycleProposed,
+			canaryReady:     corev1.ConditionFalse,
+			expectedApprove: true,
+		},
+		"previous wave not published": {
+			repo:            "edge02",
+			canaryLifecycle: porchapi.PackageRevisionLifecycleProposed,
+			canaryReady:     corev1.ConditionTrue,
+			expectedApprove: false,
+		},
+		"previous wave cluster not ready": {
+			repo:            "edge02",
+			canaryLifecycle: porchapi.PackageRevisionLifecycleDeletionProposed,
+			canaryReady:     corev1.ConditionFalse,
+			expectedApprove: false,
+		},
+		"previous wave published and ready": {
+			repo:            "edge02",
+			canaryLifecycle: porchapi.PackageRevisionLifecyclePublished,
+			canaryReady:     corev1.ConditionTrue,
+			expectedApprove: true,
+		},
+		"previous wave cluster is ambiguous": {
+			repo:            "edge02",
+			canaryLifecycle: porchapi.PackageRevisionLifecyclePublished,
+			canaryReady:     corev1.ConditionTrue,
+			objects:         []client.Object{rolloutCluster("other", "canary", corev1.ConditionTrue)},
+			expectedErr:     true,
+		},
+		"percentage wave without package variant set": {
+			repo:            "edge02",
+			waves:           "tier=canary; 20%; *",
+			canaryLifecycle: porchapi.PackageRevisionLifecyclePublished,
+			canaryReady:     corev1.ConditionTrue,
+			expectedApprove: true,
+		},
+		"percentage wave includes package variant set targets without revisions": {
+			repo:            "edge02",
+			waves:           "tier=canary; 20%; *",
+			canaryLifecycle: porchapi.PackageRevisionLifecyclePublished,
+			canaryReady:     corev1.ConditionTrue,
+			objects: []client.Object{
+				rolloutPackageVariant("canary"),
+				rolloutPackageVariant("edge01"),
+				rolloutPackageVariant("edge02"),
+			},
+			expectedApprove: false,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			waves := tc.waves
+			if waves == "" {
+				waves = "tier=canary; *"
+			}
+			pr := rolloutPackageRevision(tc.repo, porchapi.PackageRevisionLifecycleProposed, waves)
+			objects := append([]client.Object{
+				pr,
+				rolloutRepository("canary", "canary"),
+				rolloutRepository("edge01", "edge"),
+				rolloutRepository("edge02", "edge"),
+				rolloutCluster("default", "canary", tc.canaryReady),
+			}, tc.objects...)
+			if tc.repo != "canary" {
+				objects = append(objects, rolloutPackageRevision("canary", tc.canaryLifecycle, waves))
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&porchapi.PackageRevision{}, RolloutIndexKey, rolloutIndex).
+				WithIndex(&capiv1beta1.Cluster{}, cluster.ClusterNameIndex, cluster.IndexClusterByName).
+				WithObjects(objects...).Build()
+
+			actualApprove, err := policyRollout(context.TODO(), c, pr)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedApprove, actualApprove)
+		})
+	}
+}
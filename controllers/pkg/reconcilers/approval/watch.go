@@ -0,0 +1,222 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+
+	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Field indexes on PackageRevisions maintained in the cache of the approval
+// controller. Policies can use them with client.MatchingFields.
+const (
+	// PackageIndexKey indexes PackageRevisions by "<repository>/<package>"
+	PackageIndexKey = "approval.nephio.org/package"
+	// RolloutIndexKey indexes PackageRevisions by their rollout annotation
+	RolloutIndexKey = "approval.nephio.org/rollout"
+	// PackageVariantIndexKey indexes PackageRevisions by the name of the
+	// PackageVariant controlling them
+	PackageVariantIndexKey = "approval.nephio.org/packagevariant"
+	// RolloutRepositoryIndexKey indexes the PackageRevisions with a rollout
+	// annotation by their repository
+	RolloutRepositoryIndexKey = "approval.nephio.org/rollout-repository"
+	// RepositoryClusterIndexKey indexes Repositories by the name of their
+	// workload cluster, see rolloutRepositories
+	RepositoryClusterIndexKey = "approval.nephio.org/repository-cluster"
+)
+
+// PackageKey returns the value of the PackageIndexKey of the PackageRevision
+func PackageKey(pr *porchv1alpha1.PackageRevision) string {
+	return pr.Spec.RepositoryName + "/" + pr.Spec.PackageName
+}
+
+// Indexes returns the indexes of the approval controller, the rollback
+// controller shares the PackageIndexKey
+func Indexes() []reconcilerinterface.Index {
+	return []reconcilerinterface.Index{
+		{Object: &porchv1alpha1.PackageRevision{}, Field: PackageIndexKey, Extract: packageIndex},
+		{Object: &porchv1alpha1.PackageRevision{}, Field: RolloutIndexKey, Extract: rolloutIndex},
+		{Object: &porchv1alpha1.PackageRevision{}, Field: PackageVariantIndexKey, Extract: packageVariantIndex},
+		{Object: &porchv1alpha1.PackageRevision{}, Field: RolloutRepositoryIndexKey, Extract: rolloutRepositoryIndex},
+		{Object: &porchconfig.Repository{}, Field: RepositoryClusterIndexKey, Extract: repositoryClusterIndex},
+	}
+}
+
+func packageIndex(o client.Object) []string {
+	pr, ok := o.(*porchv1alpha1.PackageRevision)
+	if !ok {
+		return nil
+	}
+	return []string{PackageKey(pr)}
+}
+
+func rolloutIndex(o client.Object) []string {
+	rollout, ok := o.GetAnnotations()[RolloutAnnotationName]
+	if !ok {
+		return nil
+	}
+	return []string{rollout}
+}
+
+func rolloutRepositoryIndex(o client.Object) []string {
+	pr, ok := o.(*porchv1alpha1.PackageRevision)
+	if !ok {
+		return nil
+	}
+	if _, ok := pr.GetAnnotations()[RolloutAnnotationName]; !ok {
+		return nil
+	}
+	return []string{pr.Spec.RepositoryName}
+}
+
+func repositoryClusterIndex(o client.Object) []string {
+	if clusterName, ok := o.GetLabels()[clusterNameLabelKey]; ok {
+		return []string{clusterName}
+	}
+	return []string{o.GetName()}
+}
+
+func packageVariantIndex(o client.Object) []string {
+	for _, ownerRef := range o.GetOwnerReferences() {
+		if ownerRef.Controller == nil || !*ownerRef.Controller {
+			continue
+		}
+		if ownerRef.APIVersion == porchconfig.GroupVersion.String() && ownerRef.Kind == "PackageVariant" {
+			return []string{ownerRef.Name}
+		}
+	}
+	return nil
+}
+
+// siblingHandler enqueues the PackageRevisions whose policies may depend on
+// the given PackageRevision, see mapSiblingPackageRevisions, when it is
+// Published or deleted.
+func (r *reconciler) siblingHandler() handler.EventHandler {
+	enqueue := func(ctx context.Context, o client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		for _, req := range r.mapSiblingPackageRevisions(ctx, o) {
+			q.Add(req)
+		}
+	}
+	isPublished := func(o client.Object) bool {
+		pr, ok := o.(*porchv1alpha1.PackageRevision)
+		return ok && porchv1alpha1.LifecycleIsPublished(pr.Spec.Lifecycle)
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if isPublished(e.Object) {
+				enqueue(ctx, e.Object, q)
+			}
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if isPublished(e.ObjectNew) {
+				enqueue(ctx, e.ObjectNew, q)
+			}
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+	}
+}
+
+// mapSiblingPackageRevisions returns the PackageRevisions whose policies may
+// depend on the given PackageRevision: the other revisions of the same
+// package, and the other revisions of the same rollout.
+func (r *reconciler) mapSiblingPackageRevisions(ctx context.Context, o client.Object) []reconcile.Request {
+	pr, ok := o.(*porchv1alpha1.PackageRevision)
+	if !ok {
+		return nil
+	}
+
+	fields := []client.MatchingFields{{PackageIndexKey: PackageKey(pr)}}
+	if rollout, ok := pr.GetAnnotations()[RolloutAnnotationName]; ok {
+		fields = append(fields, client.MatchingFields{RolloutIndexKey: rollout})
+	}
+
+	var requests []reconcile.Request
+	for _, f := range fields {
+		requests = append(requests, r.listRequests(ctx, pr.Namespace, f, pr.Name)...)
+	}
+	return requests
+}
+
+// mapClusterPackageRevisions enqueues the PackageRevisions of the rollouts
+// targeting a repository whose workload cluster is the given CAPI cluster, so
+// the later waves are reconsidered when it becomes Ready.
+func (r *reconciler) mapClusterPackageRevisions(ctx context.Context, o client.Object) []reconcile.Request {
+	var repoList porchconfig.RepositoryList
+	if err := r.baseClient.List(ctx, &repoList, client.MatchingFields{RepositoryClusterIndexKey: o.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "cannot list repositories")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, repo := range repoList.Items {
+		var prList porchv1alpha1.PackageRevisionList
+		if err := r.baseClient.List(ctx, &prList, client.InNamespace(repo.Namespace),
+			client.MatchingFields{RolloutRepositoryIndexKey: repo.Name}); err != nil {
+			log.FromContext(ctx).Error(err, "cannot list package revisions")
+			return nil
+		}
+		rollouts := sets.New[string]()
+		for _, pr := range prList.Items {
+			rollouts.Insert(pr.GetAnnotations()[RolloutAnnotationName])
+		}
+		for _, rollout := range sets.List(rollouts) {
+			requests = append(requests, r.listRequests(ctx, repo.Namespace, client.MatchingFields{RolloutIndexKey: rollout}, "")...)
+		}
+	}
+	return requests
+}
+
+// mapPackageVariantPackageRevisions enqueues the PackageRevisions controlled
+// by the given PackageVariant, so they are reconsidered once it is Ready.
+func (r *reconciler) mapPackageVariantPackageRevisions(ctx context.Context, o client.Object) []reconcile.Request {
+	return r.listRequests(ctx, o.GetNamespace(), client.MatchingFields{PackageVariantIndexKey: o.GetName()}, "")
+}
+
+func (r *reconciler) listRequests(ctx context.Context, namespace string, fields client.MatchingFields, skip string) []reconcile.Request {
+	var prList porchv1alpha1.PackageRevisionList
+	if err := r.baseClient.List(ctx, &prList, client.InNamespace(namespace), fields); err != nil {
+		log.FromContext(ctx).Error(err, "cannot list package revisions", "fields", fields)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, pr := range prList.Items {
+		if pr.Name == skip || porchv1alpha1.LifecycleIsPublished(pr.Spec.Lifecycle) {
+			continue
+		}
+		if _, ok := pr.GetAnnotations()[PolicyAnnotationName]; !ok {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: pr.Namespace, Name: pr.Name},
+		})
+	}
+	return requests
+}
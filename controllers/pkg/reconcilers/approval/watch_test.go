@@ -0,0 +1,120 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"context"
+	"testing"
+
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIndexes(t *testing.T) {
+	pr := &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"approval.nephio.org/rollout": "upf-v2",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "config.porch.kpt.dev/v1alpha1",
+					Kind:       "PackageVariant",
+					Name:       "not-controller",
+				},
+				{
+					APIVersion: "config.porch.kpt.dev/v1alpha1",
+					Kind:       "PackageVariant",
+					Name:       "edge01-upf",
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: "edge01",
+			PackageName:    "upf",
+		},
+	}
+	require.Equal(t, []string{"edge01/upf"}, packageIndex(pr))
+	require.Equal(t, []string{"upf-v2"}, rolloutIndex(pr))
+	require.Equal(t, []string{"edge01-upf"}, packageVariantIndex(pr))
+	require.Equal(t, []string{"edge01"}, rolloutRepositoryIndex(pr))
+
+	empty := &porchapi.PackageRevision{}
+	require.Nil(t, rolloutIndex(empty))
+	require.Nil(t, packageVariantIndex(empty))
+	require.Nil(t, rolloutRepositoryIndex(empty))
+
+	repo := rolloutRepository("edge01", "edge")
+	require.Equal(t, []string{"edge01"}, repositoryClusterIndex(repo))
+	repo.Labels[clusterNameLabelKey] = "edge01-cluster"
+	require.Equal(t, []string{"edge01-cluster"}, repositoryClusterIndex(repo))
+}
+
+func TestWatches(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, porchapi.AddToScheme(scheme))
+	require.NoError(t, porchconfig.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+
+	waves := "tier=canary; *"
+	canary := rolloutPackageRevision("canary", porchapi.PackageRevisionLifecycleDraft, waves)
+	edge01 := rolloutPackageRevision("edge01", porchapi.PackageRevisionLifecycleProposed, waves)
+	edge02 := rolloutPackageRevision("edge02", porchapi.PackageRevisionLifecycleProposed, waves)
+	edge02.Annotations[RolloutAnnotationName] = "smf-v1"
+	repo := rolloutRepository("canary", "canary")
+	repo.Labels[clusterNameLabelKey] = "canary-cluster"
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range Indexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	c := builder.WithObjects(canary, edge01, edge02, repo).Build()
+	r := &reconciler{baseClient: c}
+
+	names := func(requests []reconcile.Request) []string {
+		var names []string
+		for _, req := range requests {
+			names = append(names, req.Name)
+		}
+		return names
+	}
+
+	t.Run("sibling deleted", func(t *testing.T) {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+		r.siblingHandler().Update(context.TODO(), event.UpdateEvent{ObjectOld: canary, ObjectNew: canary}, q)
+		require.Equal(t, 0, q.Len())
+		r.siblingHandler().Delete(context.TODO(), event.DeleteEvent{Object: canary}, q)
+		require.Equal(t, 1, q.Len())
+		req, _ := q.Get()
+		require.Equal(t, edge01.Name, req.Name)
+	})
+
+	t.Run("cluster of a rollout", func(t *testing.T) {
+		cl := &capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "canary-cluster"}}
+		require.Equal(t, []string{canary.Name, edge01.Name}, names(r.mapClusterPackageRevisions(context.TODO(), cl)))
+		cl.Name = "canary"
+		require.Empty(t, r.mapClusterPackageRevisions(context.TODO(), cl))
+	})
+}
@@ -0,0 +1,195 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// maintenanceWindow opens at start (offset from midnight) on each of the
+// selected days and stays open for duration. A window may extend past
+// midnight into the next day.
+type maintenanceWindow struct {
+	days     [7]bool
+	start    time.Duration
+	duration time.Duration
+}
+
+// manageWindow returns how long to wait until the next maintenance window
+// opens, or 0 if now falls within one of the windows or no window is set.
+func manageWindow(pr *porchv1alpha1.PackageRevision, now time.Time) (time.Duration, error) {
+	value, ok := pr.GetAnnotations()[WindowAnnotationName]
+	if !ok {
+		// only wait for a window if there is a window annotation
+		return 0, nil
+	}
+
+	windows, err := parseWindows(value)
+	if err != nil {
+		return 0, err
+	}
+
+	loc := time.UTC
+	if tz, ok := pr.GetAnnotations()[WindowTimezoneAnnotationName]; ok {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timezone %q: %s", tz, err.Error())
+		}
+	}
+
+	return nextWindow(windows, now.In(loc)), nil
+}
+
+// nextWindow returns 0 if now is inside a window, otherwise the time until the
+// earliest upcoming window start.
+func nextWindow(windows []maintenanceWindow, now time.Time) time.Duration {
+	var next time.Duration
+	// start the day before, so that windows extending past midnight are found
+	for day := -1; day <= 7; day++ {
+		y, m, d := now.AddDate(0, 0, day).Date()
+		midnight := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+		for _, w := range windows {
+			if !w.days[midnight.Weekday()] {
+				continue
+			}
+			start := time.Date(y, m, d, int(w.start.Hours()), int(w.start.Minutes())%60, 0, 0, now.Location())
+			end := start.Add(w.duration)
+			if !now.Before(start) && now.Before(end) {
+				return 0
+			}
+			if wait := start.Sub(now); wait > 0 && (next == 0 || wait < next) {
+				next = wait
+			}
+		}
+	}
+	return next
+}
+
+// parseWindows parses a list of windows separated by ";". Each window has the
+// form "[<days>] <HH:MM>-<HH:MM>", where days is a comma separated list of
+// weekdays or weekday ranges, for example "Mon-Fri 22:00-04:00; Sat,Sun 08:00-20:00".
+// If the days are omitted, the window applies to every day.
+func parseWindows(value string) ([]maintenanceWindow, error) {
+	var windows []maintenanceWindow
+	for _, s := range strings.Split(value, ";") {
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var w maintenanceWindow
+		switch len(fields) {
+		case 1:
+			for i := range w.days {
+				w.days[i] = true
+			}
+		case 2:
+			days, err := parseWeekdays(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			w.days = days
+		default:
+			return nil, fmt.Errorf("invalid window %q", strings.TrimSpace(s))
+		}
+
+		startEnd := strings.Split(fields[len(fields)-1], "-")
+		if len(startEnd) != 2 {
+			return nil, fmt.Errorf("invalid window %q; expected <HH:MM>-<HH:MM>", strings.TrimSpace(s))
+		}
+		start, err := parseTimeOfDay(startEnd[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(startEnd[1])
+		if err != nil {
+			return nil, err
+		}
+		w.start = start
+		w.duration = end - start
+		if w.duration <= 0 {
+			w.duration += 24 * time.Hour
+		}
+		windows = append(windows, w)
+	}
+
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no maintenance window specified")
+	}
+	return windows, nil
+}
+
+func parseWeekdays(value string) ([7]bool, error) {
+	var days [7]bool
+	for _, s := range strings.Split(value, ",") {
+		fromTo := strings.Split(s, "-")
+		if len(fromTo) > 2 {
+			return days, fmt.Errorf("invalid weekday range %q", s)
+		}
+		from, err := parseWeekday(fromTo[0])
+		if err != nil {
+			return days, err
+		}
+		to := from
+		if len(fromTo) == 2 {
+			to, err = parseWeekday(fromTo[1])
+			if err != nil {
+				return days, err
+			}
+		}
+		// ranges may wrap around the end of the week, e.g. Fri-Mon
+		for d := from; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(value)
+	if len(name) >= 3 {
+		if d, ok := weekdays[name[:3]]; ok && (len(name) == 3 || name == strings.ToLower(d.String())) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", value)
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q; expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approval
+
+import (
+	"testing"
+	"time"
+
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestManageWindow(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2023, time.June, 7, 12, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		annotations     map[string]string
+		expectedRequeue time.Duration
+		expectedError   bool
+	}{
+		"no annotation": {
+			annotations:     nil,
+			expectedRequeue: 0,
+		},
+		"inside daily window": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "11:00-13:00",
+			},
+			expectedRequeue: 0,
+		},
+		"before daily window": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "14:30-15:00",
+			},
+			expectedRequeue: 150 * time.Minute,
+		},
+		"after daily window": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "08:00-09:00",
+			},
+			expectedRequeue: 20 * time.Hour,
+		},
+		"window crossing midnight from previous day": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "Tue 22:00-13:00",
+			},
+			expectedRequeue: 0,
+		},
+		"weekend window": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "Sat,Sun 00:00-06:00",
+			},
+			expectedRequeue: 60 * time.Hour,
+		},
+		"wrapping weekday range": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "Fri-Mon 10:00-11:00",
+			},
+			expectedRequeue: 46 * time.Hour,
+		},
+		"earliest of several windows": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "Thursday 01:00-02:00; Wed 20:00-21:00",
+			},
+			expectedRequeue: 8 * time.Hour,
+		},
+		"timezone": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window":          "Wed 16:00-17:00",
+				"approval.nephio.org/maintenance-window-timezone": "Asia/Tokyo",
+			},
+			// 12:00 UTC is 21:00 in Tokyo, so next Wednesday
+			expectedRequeue: 6*24*time.Hour + 19*time.Hour,
+		},
+		"invalid timezone": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window":          "11:00-13:00",
+				"approval.nephio.org/maintenance-window-timezone": "Nowhere/Special",
+			},
+			expectedError: true,
+		},
+		"invalid weekday": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "Mox 11:00-13:00",
+			},
+			expectedError: true,
+		},
+		"invalid time": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": "25:00-13:00",
+			},
+			expectedError: true,
+		},
+		"empty window": {
+			annotations: map[string]string{
+				"approval.nephio.org/maintenance-window": " ; ",
+			},
+			expectedError: true,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pr := &porchapi.PackageRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			actualRequeue, actualError := manageWindow(pr, now)
+			require.Equal(t, tc.expectedError, actualError != nil)
+			require.Equal(t, tc.expectedRequeue, actualRequeue)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrappackages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// install applies the resources of the package to the cluster with
+// server-side apply, CRDs and namespaces first, and prunes the objects of the
+// previous revision that are no longer part of the package. It returns the
+// result of the apply of each resource, nil when the package is installed on
+// the cluster by a newer revision and is left alone.
+func (r *reconciler) install(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterClient resource.APIPatchingApplicator, resources []unstructured.Unstructured) ([]bootstrapv1alpha1.ResourceResult, error) {
+	log := log.FromContext(ctx)
+	// the resources agree on the annotation, see checkPackageAnnotations
+	force := resources[0].GetAnnotations()[applyConflictsKey] != applyConflictsReport
+	clusterClient = clusterClient.WithServerSideApply(fieldManager, force)
+
+	previous, err := getInventory(ctx, clusterClient, cr)
+	if err != nil {
+		log.Error(err, "cannot get inventory")
+		return nil, err
+	}
+	if previous == nil {
+		previous = &inventory{objects: map[string]objectRef{}}
+	} else if previous.revision > cr.Spec.Revision {
+		log.Info("newer revision of the package installed", "revision", previous.revision)
+		return nil, nil
+	}
+	current := newInventory(cr.Spec.Revision, resources)
+	// the objects are recorded before they are applied, so the objects of an
+	// apply that fails halfway are pruned as well
+	if err := applyInventory(ctx, clusterClient, cr, previous.union(current)); err != nil {
+		log.Error(err, "cannot record inventory")
+		return nil, err
+	}
+
+	// all the resources are applied, a resource that fails does not prevent
+	// the others from being installed
+	sortResources(resources)
+	results := make([]bootstrapv1alpha1.ResourceResult, 0, len(resources))
+	failed := false
+	for i := range resources {
+		result := applyResource(ctx, clusterClient, &resources[i])
+		if result.Result == bootstrapv1alpha1.ApplyResultFailed {
+			failed = true
+		}
+		results = append(results, result)
+	}
+	// the objects of the previous revision are kept until the package is
+	// fully applied
+	if failed {
+		return results, nil
+	}
+
+	if err := deleteObjects(ctx, clusterClient, previous.stale(current)); err != nil {
+		log.Error(err, "cannot prune resources")
+		return nil, err
+	}
+	if err := applyInventory(ctx, clusterClient, cr, current); err != nil {
+		log.Error(err, "cannot record inventory")
+		return nil, err
+	}
+	return results, nil
+}
+
+// uninstall removes the package from the clusters it is installed on, and
+// releases the package revision
+func (r *reconciler) uninstall(ctx context.Context, cr *porchv1alpha1.PackageRevision) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	for _, clusterName := range installedClusters(cr) {
+		done, err := r.uninstallFrom(ctx, cr, clusterName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+	if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
+		log.Error(err, "cannot remove finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// uninstallFrom removes the package from the cluster, it returns false when
+// the cluster is not ready and the package has to be removed later
+func (r *reconciler) uninstallFrom(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterName string) (bool, error) {
+	log := log.FromContext(ctx).WithValues("cluster", clusterName)
+	clusterClient, ok, err := cluster.Cluster{Client: r.Client}.ResolveClusterClient(ctx, clusterName)
+	if err != nil {
+		msg := fmt.Sprintf("failed to get cluster Secret for: %s", clusterName)
+		log.Error(err, msg)
+		return false, errors.Wrap(err, msg)
+	}
+	if !ok {
+		// the cluster is gone, and the package with it
+		log.Info("cluster client not found, nothing to uninstall")
+		return true, nil
+	}
+	remoteClient, ready, err := clusterClient.GetClusterClient(ctx)
+	if err != nil {
+		msg := "cannot get clusterClient"
+		log.Error(err, msg)
+		return false, errors.Wrap(err, msg)
+	}
+	if !ready {
+		readiness := cluster.GetReadiness(ctx, clusterClient)
+		log.Info("cluster not ready", "reason", readiness.Reason, "message", readiness.Message)
+		return false, nil
+	}
+	if err := uninstallPackage(ctx, cr, remoteClient); err != nil {
+		log.Error(err, "cannot uninstall package")
+		return false, err
+	}
+	return true, nil
+}
+
+// uninstallPackage deletes the objects of the package from the cluster, in the
+// reverse order they are applied, if the package revision installed them
+func uninstallPackage(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterClient resource.APIPatchingApplicator) error {
+	inv, err := getInventory(ctx, clusterClient, cr)
+	if err != nil {
+		return err
+	}
+	if inv == nil || inv.revision != cr.Spec.Revision {
+		return nil
+	}
+	if err := deleteObjects(ctx, clusterClient, inv.stale(&inventory{})); err != nil {
+		return err
+	}
+	key := inventoryKey(cr)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+	if err := clusterClient.Delete(ctx, cm); resource.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("cannot delete inventory: %w", err)
+	}
+	return nil
+}
+
+// installedClusters returns the clusters the package is installed on
+func installedClusters(cr *porchv1alpha1.PackageRevision) []string {
+	clusters := []string{}
+	for _, clusterName := range strings.Split(cr.GetAnnotations()[installedClustersKey], ",") {
+		if clusterName != "" {
+			clusters = append(clusters, clusterName)
+		}
+	}
+	return clusters
+}
+
+// recordInstalledClusters adds the clusters to the clusters the package is
+// installed on
+func (r *reconciler) recordInstalledClusters(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterNames ...string) error {
+	clusters := installedClusters(cr)
+	changed := false
+	for _, clusterName := range clusterNames {
+		if !slices.Contains(clusters, clusterName) {
+			clusters = append(clusters, clusterName)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.setInstalledClusters(ctx, cr, clusters)
+}
+
+// forgetInstalledClusters removes the clusters from the clusters the package
+// is installed on
+func (r *reconciler) forgetInstalledClusters(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterNames ...string) error {
+	clusters := installedClusters(cr)
+	kept := slices.DeleteFunc(slices.Clone(clusters), func(clusterName string) bool {
+		return slices.Contains(clusterNames, clusterName)
+	})
+	if len(kept) == len(clusters) {
+		return nil
+	}
+	return r.setInstalledClusters(ctx, cr, kept)
+}
+
+func (r *reconciler) setInstalledClusters(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusters []string) error {
+	slices.Sort(clusters)
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[installedClustersKey] = strings.Join(clusters, ",")
+	cr.SetAnnotations(annotations)
+	return r.Update(ctx, cr)
+}
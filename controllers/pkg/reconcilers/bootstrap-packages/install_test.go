@@ -0,0 +1,250 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrappackages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newRemoteClient returns a fake cluster client. The fake client does not
+// support server-side apply, the applied objects replace the objects instead,
+// unless their data and spec are unchanged. The resources with the annotation
+// failAnnotation fail to apply.
+func newRemoteClient() resource.APIPatchingApplicator {
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			u := &unstructured.Unstructured{}
+			if err := json.Unmarshal(data, &u.Object); err != nil {
+				return err
+			}
+			if msg, ok := u.GetAnnotations()[failAnnotation]; ok {
+				return errors.New(msg)
+			}
+			current := &unstructured.Unstructured{}
+			current.SetGroupVersionKind(u.GroupVersionKind())
+			if err := c.Get(ctx, client.ObjectKeyFromObject(u), current); err != nil {
+				if resource.IgnoreNotFound(err) != nil {
+					return err
+				}
+				if err := c.Create(ctx, u); err != nil {
+					return err
+				}
+				return setObject(obj, u)
+			}
+			if equality.Semantic.DeepEqual(current.Object["data"], u.Object["data"]) &&
+				equality.Semantic.DeepEqual(current.Object["spec"], u.Object["spec"]) {
+				return setObject(obj, current)
+			}
+			u.SetResourceVersion(current.GetResourceVersion())
+			if err := c.Update(ctx, u); err != nil {
+				return err
+			}
+			return setObject(obj, u)
+		},
+	}).Build()
+	return resource.NewAPIPatchingApplicator(c)
+}
+
+const failAnnotation = "test.nephio.org/fail"
+
+// setObject returns the object of the cluster the way the apply does
+func setObject(obj client.Object, u *unstructured.Unstructured) error {
+	if o, ok := obj.(*unstructured.Unstructured); ok {
+		o.Object = u.Object
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
+
+func newManifest(apiVersion, kind, namespace, name string, data map[string]any) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]any{}}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if data != nil {
+		u.Object["data"] = data
+	}
+	return u
+}
+
+func TestSortResources(t *testing.T) {
+	resources := []unstructured.Unstructured{
+		newManifest("v1", "ConfigMap", "ns1", "b", nil),
+		newManifest("apps/v1", "Deployment", "ns1", "a", nil),
+		newManifest("v1", "Namespace", "", "ns1", nil),
+		newManifest("example.com/v1", "Example", "ns1", "a", nil),
+		newManifest("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "examples.example.com", nil),
+	}
+	sortResources(resources)
+	got := []string{}
+	for _, u := range resources {
+		got = append(got, u.GetKind()+"/"+u.GetName())
+	}
+	require.Equal(t, []string{
+		"CustomResourceDefinition/examples.example.com",
+		"Namespace/ns1",
+		"ConfigMap/b",
+		"Deployment/a",
+		"Example/a",
+	}, got)
+}
+
+func TestInstall(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteClient()
+	r := &reconciler{}
+	newPR := func(revision int) *porchv1alpha1.PackageRevision {
+		return &porchv1alpha1.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt-staging-configsync"},
+			Spec: porchv1alpha1.PackageRevisionSpec{
+				RepositoryName: "mgmt-staging",
+				PackageName:    "edge01-configsync",
+				Revision:       revision,
+			},
+		}
+	}
+	exists := func(apiVersion, kind, namespace, name string) bool {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		return remote.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, u) == nil
+	}
+
+	v1 := newPR(1)
+	_, err := r.install(ctx, v1, remote, []unstructured.Unstructured{
+		newManifest("v1", "ConfigMap", "ns1", "a", map[string]any{"k": "v1"}),
+		newManifest("v1", "ConfigMap", "ns1", "b", nil),
+		newManifest("v1", "Namespace", "", "ns1", nil),
+		newManifest("rbac.authorization.k8s.io/v1", "ClusterRole", "", "nephio:bootstrap", nil),
+	})
+	require.NoError(t, err)
+	require.True(t, exists("v1", "Namespace", "", "ns1"))
+	require.True(t, exists("v1", "ConfigMap", "ns1", "b"))
+	inv, err := getInventory(ctx, remote, v1)
+	require.NoError(t, err)
+	require.Equal(t, 1, inv.revision)
+	require.Len(t, inv.objects, 4)
+	// the names of the objects are not valid configMap keys
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, remote.Get(ctx, inventoryKey(v1), cm))
+	for key := range cm.Data {
+		require.Empty(t, validation.IsConfigMapKey(key))
+	}
+
+	// the objects removed from the package are pruned on upgrade
+	v2 := newPR(2)
+	_, err = r.install(ctx, v2, remote, []unstructured.Unstructured{
+		newManifest("v1", "ConfigMap", "ns1", "a", map[string]any{"k": "v2"}),
+		newManifest("v1", "Namespace", "", "ns1", nil),
+	})
+	require.NoError(t, err)
+	require.False(t, exists("v1", "ConfigMap", "ns1", "b"))
+	require.False(t, exists("rbac.authorization.k8s.io/v1", "ClusterRole", "", "nephio:bootstrap"))
+	require.NoError(t, remote.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "a"}, cm))
+	require.Equal(t, "v2", cm.Data["k"])
+	inv, err = getInventory(ctx, remote, v2)
+	require.NoError(t, err)
+	require.Equal(t, 2, inv.revision)
+	require.Len(t, inv.objects, 2)
+
+	// an older revision does not overwrite the newer one
+	results, err := r.install(ctx, v1, remote, []unstructured.Unstructured{
+		newManifest("v1", "ConfigMap", "ns1", "b", nil),
+	})
+	require.NoError(t, err)
+	require.Nil(t, results)
+	require.False(t, exists("v1", "ConfigMap", "ns1", "b"))
+
+	// only the revision that installed the package uninstalls it
+	require.NoError(t, uninstallPackage(ctx, v1, remote))
+	require.True(t, exists("v1", "ConfigMap", "ns1", "a"))
+	require.NoError(t, uninstallPackage(ctx, v2, remote))
+	require.False(t, exists("v1", "ConfigMap", "ns1", "a"))
+	require.False(t, exists("v1", "Namespace", "", "ns1"))
+	inv, err = getInventory(ctx, remote, v2)
+	require.NoError(t, err)
+	require.Nil(t, inv)
+}
+
+func TestUninstallDeselectedClusters(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, porchv1alpha1.AddToScheme(scheme))
+	require.NoError(t, bootstrapv1alpha1.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	cr := &porchv1alpha1.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt-staging-configsync"},
+		Spec:       porchv1alpha1.PackageRevisionSpec{Revision: 1},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cr).
+		WithStatusSubresource(&bootstrapv1alpha1.PackageInstallation{}).
+		WithIndex(&capiv1beta1.Cluster{}, cluster.ClusterNameIndex, cluster.IndexClusterByName).
+		WithIndex(&corev1.Secret{}, cluster.SecretClusterNameIndex, cluster.IndexSecretByClusterName).
+		Build()
+	r := &reconciler{Client: c}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cr), cr))
+
+	require.NoError(t, r.recordInstalledClusters(ctx, cr, "edge02", "edge01"))
+	require.Equal(t, []string{"edge01", "edge02"}, installedClusters(cr))
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge01", []bootstrapv1alpha1.ResourceResult{}))
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge02", []bootstrapv1alpha1.ResourceResult{}))
+
+	// the clusters no longer match the selector and are gone, the package is
+	// forgotten on them
+	selector, err := labels.Parse("region=eu-west")
+	require.NoError(t, err)
+	_, err = r.installOnSelectedClusters(ctx, cr, selector, []unstructured.Unstructured{
+		newManifest("v1", "ConfigMap", "ns1", "a", nil),
+	})
+	require.NoError(t, err)
+	require.Empty(t, installedClusters(cr))
+	stored := &porchv1alpha1.PackageRevision{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cr), stored))
+	require.Empty(t, installedClusters(stored))
+	pi := &bootstrapv1alpha1.PackageInstallation{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cr), pi))
+	require.Empty(t, pi.Status.Clusters)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrappackages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// readinessRequeue is the period the installation is checked again while
+// the objects of the package are not Current
+const readinessRequeue = 10 * time.Second
+
+// applyResource applies the resource to the cluster and returns the result of
+// the apply and the status of the object on the cluster
+func applyResource(ctx context.Context, c resource.APIPatchingApplicator, u *unstructured.Unstructured) bootstrapv1alpha1.ResourceResult {
+	log := log.FromContext(ctx)
+	log.Info("install manifest", "resource", fmt.Sprintf("%s.%s.%s", u.GetAPIVersion(), u.GetKind(), u.GetName()))
+	result := bootstrapv1alpha1.ResourceResult{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+		Result:     bootstrapv1alpha1.ApplyResultApplied,
+	}
+	// an apply that does not change the object keeps its resourceVersion
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(u.GroupVersionKind())
+	resourceVersion := ""
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), current); err == nil {
+		resourceVersion = current.GetResourceVersion()
+	}
+	// the resources are applied to every cluster, the object returned by the
+	// cluster must not leak into the next apply
+	obj := u.DeepCopy()
+	if err := c.Apply(ctx, obj); err != nil {
+		log.Error(err, "cannot apply resource to cluster", "resourceName", u.GetName())
+		result.Result = bootstrapv1alpha1.ApplyResultFailed
+		result.Status = bootstrapv1alpha1.ResourceStatusUnknown
+		result.Message = err.Error()
+		return result
+	}
+	if resourceVersion != "" && obj.GetResourceVersion() == resourceVersion {
+		result.Result = bootstrapv1alpha1.ApplyResultUnchanged
+	}
+	result.Status, result.Message = computeStatus(obj)
+	return result
+}
+
+// installResult returns the error of the resources that failed to apply, and
+// requeues the package revision until the objects are Current
+func installResult(results []bootstrapv1alpha1.ResourceResult) (ctrl.Result, error) {
+	failed := 0
+	current := true
+	for _, result := range results {
+		if result.Result == bootstrapv1alpha1.ApplyResultFailed {
+			failed++
+		}
+		if result.Status != bootstrapv1alpha1.ResourceStatusCurrent {
+			current = false
+		}
+	}
+	if failed > 0 {
+		return ctrl.Result{}, fmt.Errorf("cannot apply %d resources to cluster", failed)
+	}
+	if !current {
+		return ctrl.Result{RequeueAfter: readinessRequeue}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// recordInstallation records the results of the installation of the package
+// on the cluster in the PackageInstallation of the package revision
+func (r *reconciler) recordInstallation(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterName string, results []bootstrapv1alpha1.ResourceResult) error {
+	return r.updateInstallation(ctx, cr, func(status *bootstrapv1alpha1.PackageInstallationStatus) {
+		setClusterStatus(status, bootstrapv1alpha1.ClusterStatus{
+			Cluster:   clusterName,
+			Revision:  cr.Spec.Revision,
+			Resources: results,
+		})
+	})
+}
+
+// recordNotReady records in the PackageInstallation of the package revision
+// why the package is not installed on the cluster, the results of a previous
+// installation are kept
+func (r *reconciler) recordNotReady(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterName string, readiness cluster.Readiness) error {
+	return r.updateInstallation(ctx, cr, func(status *bootstrapv1alpha1.PackageInstallationStatus) {
+		clusterStatus := bootstrapv1alpha1.ClusterStatus{Cluster: clusterName}
+		for _, cs := range status.Clusters {
+			if cs.Cluster == clusterName {
+				clusterStatus = cs
+			}
+		}
+		clusterStatus.Message = fmt.Sprintf("cluster not ready: %s: %s", readiness.Reason, readiness.Message)
+		setClusterStatus(status, clusterStatus)
+	})
+}
+
+// forgetInstallation removes the clusters the package is no longer installed
+// on from the PackageInstallation of the package revision
+func (r *reconciler) forgetInstallation(ctx context.Context, cr *porchv1alpha1.PackageRevision, clusterNames ...string) error {
+	return r.updateInstallation(ctx, cr, func(status *bootstrapv1alpha1.PackageInstallationStatus) {
+		status.Clusters = slices.DeleteFunc(status.Clusters, func(cs bootstrapv1alpha1.ClusterStatus) bool {
+			return slices.Contains(clusterNames, cs.Cluster)
+		})
+	})
+}
+
+// updateInstallation updates the status of the PackageInstallation of the
+// package revision, created if it does not exist
+func (r *reconciler) updateInstallation(ctx context.Context, cr *porchv1alpha1.PackageRevision, update func(*bootstrapv1alpha1.PackageInstallationStatus)) error {
+	pi := &bootstrapv1alpha1.PackageInstallation{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(cr), pi); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot get package installation: %w", err)
+		}
+		pi = newPackageInstallation(cr)
+		if err := r.Create(ctx, pi); err != nil {
+			return fmt.Errorf("cannot create package installation: %w", err)
+		}
+	}
+	status := pi.Status.DeepCopy()
+	update(status)
+	setConditions(status, pi.GetGeneration())
+	if equality.Semantic.DeepEqual(status, &pi.Status) {
+		return nil
+	}
+	pi.Status = *status
+	if err := r.Status().Update(ctx, pi); err != nil {
+		return fmt.Errorf("cannot update package installation: %w", err)
+	}
+	return nil
+}
+
+func newPackageInstallation(cr *porchv1alpha1.PackageRevision) *bootstrapv1alpha1.PackageInstallation {
+	return &bootstrapv1alpha1.PackageInstallation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: bootstrapv1alpha1.GroupVersion.String(),
+			Kind:       bootstrapv1alpha1.PackageInstallationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cr.GetNamespace(),
+			Name:      cr.GetName(),
+			// the installation is garbage collected with the package revision
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: porchv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "PackageRevision",
+				Name:       cr.GetName(),
+				UID:        cr.GetUID(),
+			}},
+		},
+		Spec: bootstrapv1alpha1.PackageInstallationSpec{
+			PackageRevision: cr.GetName(),
+			RepositoryName:  cr.Spec.RepositoryName,
+			PackageName:     cr.Spec.PackageName,
+			Revision:        cr.Spec.Revision,
+		},
+	}
+}
+
+// setClusterStatus replaces the status of the cluster, the clusters are kept
+// in the order they are installed
+func setClusterStatus(status *bootstrapv1alpha1.PackageInstallationStatus, clusterStatus bootstrapv1alpha1.ClusterStatus) {
+	for i := range status.Clusters {
+		if status.Clusters[i].Cluster == clusterStatus.Cluster {
+			status.Clusters[i] = clusterStatus
+			return
+		}
+	}
+	status.Clusters = append(status.Clusters, clusterStatus)
+}
+
+// setConditions aggregates the results of all the clusters in the Applied
+// and Ready conditions, the message reports the first cluster that is not
+// ready or resource that is not applied or not Current
+func setConditions(status *bootstrapv1alpha1.PackageInstallationStatus, generation int64) {
+	applied := metav1.Condition{
+		Type:    bootstrapv1alpha1.ConditionTypeApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Applied",
+		Message: "all resources applied",
+	}
+	ready := metav1.Condition{
+		Type:    bootstrapv1alpha1.ConditionTypeReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Current",
+		Message: "all resources current",
+	}
+	for _, cluster := range status.Clusters {
+		if cluster.Message != "" && ready.Status == metav1.ConditionTrue {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = "ClusterNotReady"
+			ready.Message = fmt.Sprintf("cluster %s: %s", cluster.Cluster, cluster.Message)
+		}
+		for _, result := range cluster.Resources {
+			msg := fmt.Sprintf("cluster %s: %s %s: %s", cluster.Cluster, result.Kind, resourceName(result), result.Message)
+			if result.Result == bootstrapv1alpha1.ApplyResultFailed && applied.Status == metav1.ConditionTrue {
+				applied.Status = metav1.ConditionFalse
+				applied.Reason = "ApplyFailed"
+				applied.Message = msg
+			}
+			switch {
+			case result.Status == bootstrapv1alpha1.ResourceStatusCurrent:
+			case result.Status == bootstrapv1alpha1.ResourceStatusFailed && ready.Reason != "Failed":
+				ready.Status = metav1.ConditionFalse
+				ready.Reason = "Failed"
+				ready.Message = msg
+			case ready.Status == metav1.ConditionTrue:
+				ready.Status = metav1.ConditionFalse
+				ready.Reason = string(result.Status)
+				ready.Message = msg
+			}
+		}
+	}
+	for _, c := range []metav1.Condition{applied, ready} {
+		c.ObservedGeneration = generation
+		meta.SetStatusCondition(&status.Conditions, c)
+	}
+}
+
+func resourceName(result bootstrapv1alpha1.ResourceResult) string {
+	if result.Namespace == "" {
+		return result.Name
+	}
+	return result.Namespace + "/" + result.Name
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrappackages
+
+import (
+	"context"
+	"testing"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInstallResults(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteClient()
+	r := &reconciler{}
+	cr := &porchv1alpha1.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt-staging-configsync"},
+		Spec: porchv1alpha1.PackageRevisionSpec{
+			RepositoryName: "mgmt-staging",
+			PackageName:    "edge01-configsync",
+			Revision:       1,
+		},
+	}
+	failing := newManifest("v1", "ConfigMap", "ns1", "b", nil)
+	failing.SetAnnotations(map[string]string{failAnnotation: "conflict"})
+	resources := func() []unstructured.Unstructured {
+		return []unstructured.Unstructured{
+			newManifest("v1", "ConfigMap", "ns1", "a", map[string]any{"k": "v1"}),
+			*failing.DeepCopy(),
+		}
+	}
+
+	// a resource that fails does not prevent the others from being applied
+	results, err := r.install(ctx, cr, remote, resources())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, bootstrapv1alpha1.ApplyResultApplied, results[0].Result)
+	require.Equal(t, bootstrapv1alpha1.ResourceStatusCurrent, results[0].Status)
+	require.Equal(t, bootstrapv1alpha1.ApplyResultFailed, results[1].Result)
+	require.Equal(t, bootstrapv1alpha1.ResourceStatusUnknown, results[1].Status)
+	require.Contains(t, results[1].Message, "conflict")
+	_, err = installResult(results)
+	require.Error(t, err)
+
+	// the resources already up to date are unchanged
+	results, err = r.install(ctx, cr, remote, resources()[:1])
+	require.NoError(t, err)
+	require.Equal(t, bootstrapv1alpha1.ApplyResultUnchanged, results[0].Result)
+	result, err := installResult(results)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+
+	// the objects that are not Current are checked again
+	deployment := newManifest("apps/v1", "Deployment", "ns1", "a", nil)
+	deployment.Object["spec"] = map[string]any{"replicas": int64(1)}
+	results, err = r.install(ctx, cr, remote, []unstructured.Unstructured{deployment})
+	require.NoError(t, err)
+	require.Equal(t, bootstrapv1alpha1.ResourceStatusInProgress, results[0].Status)
+	result, err = installResult(results)
+	require.NoError(t, err)
+	require.Equal(t, readinessRequeue, result.RequeueAfter)
+}
+
+func TestRecordInstallation(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, porchv1alpha1.AddToScheme(scheme))
+	require.NoError(t, bootstrapv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&bootstrapv1alpha1.PackageInstallation{}).Build()
+	r := &reconciler{Client: c}
+	cr := &porchv1alpha1.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mgmt-staging-configsync", UID: "uid"},
+		Spec: porchv1alpha1.PackageRevisionSpec{
+			RepositoryName: "mgmt-staging",
+			PackageName:    "edge01-configsync",
+			Revision:       1,
+		},
+	}
+	newResult := func(name string, result bootstrapv1alpha1.ApplyResult, status bootstrapv1alpha1.ResourceStatus) bootstrapv1alpha1.ResourceResult {
+		return bootstrapv1alpha1.ResourceResult{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns1", Name: name, Result: result, Status: status}
+	}
+	get := func() *bootstrapv1alpha1.PackageInstallation {
+		pi := &bootstrapv1alpha1.PackageInstallation{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cr), pi))
+		return pi
+	}
+
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge01", []bootstrapv1alpha1.ResourceResult{
+		newResult("a", bootstrapv1alpha1.ApplyResultApplied, bootstrapv1alpha1.ResourceStatusCurrent),
+	}))
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge02", []bootstrapv1alpha1.ResourceResult{
+		newResult("a", bootstrapv1alpha1.ApplyResultApplied, bootstrapv1alpha1.ResourceStatusCurrent),
+		newResult("b", bootstrapv1alpha1.ApplyResultFailed, bootstrapv1alpha1.ResourceStatusUnknown),
+	}))
+	pi := get()
+	require.Equal(t, "mgmt-staging-configsync", pi.Spec.PackageRevision)
+	require.Equal(t, "uid", string(pi.GetOwnerReferences()[0].UID))
+	require.Len(t, pi.Status.Clusters, 2)
+	applied := meta.FindStatusCondition(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeApplied)
+	require.Equal(t, metav1.ConditionFalse, applied.Status)
+	require.Equal(t, "ApplyFailed", applied.Reason)
+	require.Contains(t, applied.Message, "cluster edge02: ConfigMap ns1/b")
+	require.False(t, meta.IsStatusConditionTrue(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeReady))
+
+	// the status of the cluster is replaced
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge02", []bootstrapv1alpha1.ResourceResult{
+		newResult("a", bootstrapv1alpha1.ApplyResultUnchanged, bootstrapv1alpha1.ResourceStatusCurrent),
+		newResult("b", bootstrapv1alpha1.ApplyResultApplied, bootstrapv1alpha1.ResourceStatusCurrent),
+	}))
+	pi = get()
+	require.Len(t, pi.Status.Clusters, 2)
+	require.True(t, meta.IsStatusConditionTrue(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeApplied))
+	require.True(t, meta.IsStatusConditionTrue(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeReady))
+
+	// the clusters that are not ready keep their results and report why
+	require.NoError(t, r.recordNotReady(ctx, cr, "edge02", cluster.Readiness{Reason: "ControlPlaneNotReady", Message: "condition ControlPlaneReady is False"}))
+	require.NoError(t, r.recordNotReady(ctx, cr, "edge03", cluster.Readiness{Reason: "NotReady", Message: "condition Ready is False"}))
+	pi = get()
+	require.Len(t, pi.Status.Clusters, 3)
+	require.Len(t, pi.Status.Clusters[1].Resources, 2)
+	require.Equal(t, "cluster not ready: ControlPlaneNotReady: condition ControlPlaneReady is False", pi.Status.Clusters[1].Message)
+	ready := meta.FindStatusCondition(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeReady)
+	require.Equal(t, metav1.ConditionFalse, ready.Status)
+	require.Equal(t, "ClusterNotReady", ready.Reason)
+	require.Contains(t, ready.Message, "cluster edge02")
+
+	// the message is cleared when the package is installed
+	require.NoError(t, r.recordInstallation(ctx, cr, "edge02", []bootstrapv1alpha1.ResourceResult{
+		newResult("a", bootstrapv1alpha1.ApplyResultUnchanged, bootstrapv1alpha1.ResourceStatusCurrent),
+	}))
+	pi = get()
+	require.Empty(t, pi.Status.Clusters[1].Message)
+	ready = meta.FindStatusCondition(pi.Status.Conditions, bootstrapv1alpha1.ConditionTypeReady)
+	require.Contains(t, ready.Message, "cluster edge03")
+}
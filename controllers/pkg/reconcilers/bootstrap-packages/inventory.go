@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrappackages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// the inventory of a package is a configMap in the inventoryNamespace of
+	// the cluster
+	inventoryNamespace = "default"
+	inventoryLabelKey  = "nephio.org/inventory"
+	inventoryLabel     = "bootstrap-packages"
+	repositoryKey      = "nephio.org/repository"
+	packageKey         = "nephio.org/package"
+	revisionKey        = "nephio.org/revision"
+)
+
+// objectRef identifies an object installed on the cluster, it is recorded as
+// JSON in the inventory
+type objectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func refFor(u *unstructured.Unstructured) objectRef {
+	return objectRef{APIVersion: u.GetAPIVersion(), Kind: u.GetKind(), Namespace: u.GetNamespace(), Name: u.GetName()}
+}
+
+// key returns the key of the object in the inventory, the version of the
+// object is not part of its identity
+func (r objectRef) key() string {
+	gv, _ := schema.ParseGroupVersion(r.APIVersion)
+	return strings.Join([]string{gv.Group, r.Kind, r.Namespace, r.Name}, "_")
+}
+
+// dataKey returns the key of the object in the data of the inventory
+// configMap, a hash of its key as the names of the objects can hold characters
+// that are not valid in a configMap key, e.g. the ':' of a ClusterRole
+func (r objectRef) dataKey() string {
+	hash := sha256.Sum256([]byte(r.key()))
+	return fmt.Sprintf("%x", hash[:16])
+}
+
+// rank orders the objects to apply: CRDs first, then namespaces, then the
+// other objects
+func (r objectRef) rank() int {
+	gv, _ := schema.ParseGroupVersion(r.APIVersion)
+	switch {
+	case gv.Group == "apiextensions.k8s.io" && r.Kind == "CustomResourceDefinition":
+		return 0
+	case gv.Group == "" && r.Kind == "Namespace":
+		return 1
+	}
+	return 2
+}
+
+func compareRefs(a, b objectRef) int {
+	if a.rank() != b.rank() {
+		return a.rank() - b.rank()
+	}
+	return strings.Compare(a.key(), b.key())
+}
+
+// sortResources sorts the resources in the order they are applied
+func sortResources(resources []unstructured.Unstructured) {
+	slices.SortStableFunc(resources, func(a, b unstructured.Unstructured) int {
+		return compareRefs(refFor(&a), refFor(&b))
+	})
+}
+
+// inventory records the objects of a package installed on a cluster, and the
+// revision of the package that installed them
+type inventory struct {
+	revision int
+	objects  map[string]objectRef
+}
+
+func newInventory(revision int, resources []unstructured.Unstructured) *inventory {
+	inv := &inventory{revision: revision, objects: map[string]objectRef{}}
+	for i := range resources {
+		ref := refFor(&resources[i])
+		inv.objects[ref.key()] = ref
+	}
+	return inv
+}
+
+// stale returns the objects of the inventory that are not in the other
+// inventory, in the reverse order they are applied
+func (r *inventory) stale(other *inventory) []objectRef {
+	refs := []objectRef{}
+	for key, ref := range r.objects {
+		if _, ok := other.objects[key]; !ok {
+			refs = append(refs, ref)
+		}
+	}
+	slices.SortFunc(refs, func(a, b objectRef) int { return compareRefs(b, a) })
+	return refs
+}
+
+// union returns an inventory with the objects of both inventories
+func (r *inventory) union(other *inventory) *inventory {
+	inv := &inventory{revision: other.revision, objects: map[string]objectRef{}}
+	for key, ref := range r.objects {
+		inv.objects[key] = ref
+	}
+	for key, ref := range other.objects {
+		inv.objects[key] = ref
+	}
+	return inv
+}
+
+// inventoryKey returns the key of the inventory configMap of the package
+func inventoryKey(pr *porchv1alpha1.PackageRevision) types.NamespacedName {
+	hash := sha256.Sum256([]byte(pr.Spec.RepositoryName + "/" + pr.Spec.PackageName))
+	return types.NamespacedName{Namespace: inventoryNamespace, Name: fmt.Sprintf("nephio-bootstrap-%x", hash[:8])}
+}
+
+// getInventory returns the inventory of the package on the cluster, nil if the
+// package is not installed
+func getInventory(ctx context.Context, c client.Client, pr *porchv1alpha1.PackageRevision) (*inventory, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, inventoryKey(pr), cm); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("cannot get inventory: %w", err)
+		}
+		return nil, nil
+	}
+	revision, err := strconv.Atoi(cm.GetAnnotations()[revisionKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision in inventory %s: %w", cm.GetName(), err)
+	}
+	inv := &inventory{revision: revision, objects: map[string]objectRef{}}
+	for key, data := range cm.Data {
+		ref := objectRef{}
+		if err := json.Unmarshal([]byte(data), &ref); err != nil {
+			log.FromContext(ctx).Info("invalid object in inventory", "inventory", cm.GetName(), "key", key, "error", err.Error())
+			continue
+		}
+		inv.objects[ref.key()] = ref
+	}
+	return inv, nil
+}
+
+// applyInventory records the inventory of the package on the cluster
+func applyInventory(ctx context.Context, c resource.APIPatchingApplicator, pr *porchv1alpha1.PackageRevision, inv *inventory) error {
+	key := inventoryKey(pr)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    map[string]string{inventoryLabelKey: inventoryLabel},
+			Annotations: map[string]string{
+				repositoryKey: pr.Spec.RepositoryName,
+				packageKey:    pr.Spec.PackageName,
+				revisionKey:   strconv.Itoa(inv.revision),
+			},
+		},
+		Data: map[string]string{},
+	}
+	for _, ref := range inv.objects {
+		data, err := json.Marshal(ref)
+		if err != nil {
+			return fmt.Errorf("cannot record %s %s in inventory: %w", ref.Kind, ref.Name, err)
+		}
+		cm.Data[ref.dataKey()] = string(data)
+	}
+	if err := c.Apply(ctx, cm); err != nil {
+		return fmt.Errorf("cannot apply inventory: %w", err)
+	}
+	return nil
+}
+
+// deleteObjects deletes the objects from the cluster, in the order of the refs
+func deleteObjects(ctx context.Context, c client.Client, refs []objectRef) error {
+	for _, ref := range refs {
+		log.FromContext(ctx).Info("delete manifest", "resource", fmt.Sprintf("%s.%s.%s", ref.APIVersion, ref.Kind, ref.Name))
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		u.SetNamespace(ref.Namespace)
+		u.SetName(ref.Name)
+		err := c.Delete(ctx, u, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		// the objects of a CRD that was removed are gone
+		if err != nil && resource.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("cannot delete %s %s: %w", ref.Kind, ref.Name, err)
+		}
+	}
+	return nil
+}
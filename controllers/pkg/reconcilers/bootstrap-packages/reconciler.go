@@ -19,11 +19,14 @@ package bootstrappackages
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
 	"github.com/nephio-project/nephio/controllers/pkg/cluster"
 	ctrlconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
 	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
@@ -31,14 +34,18 @@ import (
 	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
 	porchconfigv1alpha1 "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/filters"
 	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
@@ -48,12 +55,38 @@ func init() {
 	reconcilerinterface.Register("bootstrappackages", &reconciler{})
 }
 
+const (
+	// fieldManager is the field manager of the resources applied to the
+	// clusters with server-side apply
+	fieldManager = "nephio-bootstrap-packages"
+	// clusterNameKey names, on every resource of a package, the cluster the
+	// package is installed on
+	clusterNameKey = "nephio.org/cluster-name"
+	// applyConflictsKey selects, on every resource of a package, how
+	// conflicts with fields owned by other field managers on the cluster are
+	// handled: "force" (the default) takes the ownership of the fields,
+	// "report" fails the installation of the package
+	applyConflictsKey    = "nephio.org/apply-conflicts"
+	applyConflictsReport = "report"
+	// installedClustersKey records on the package revision the clusters the
+	// package is installed on, as a comma separated list
+	installedClustersKey = "nephio.org/installed-clusters"
+	// finalizer removes the package from the clusters before the package
+	// revision is deleted
+	finalizer = "bootstrap.nephio.org/finalizer"
+)
+
 //+kubebuilder:rbac:groups="*",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get
-//+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get
+//+kubebuilder:rbac:groups=focom.nephio.org,resources=focomprovisioningrequests,verbs=get
+//+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions/status,verbs=get
 //+kubebuilder:rbac:groups=config.porch.kpt.dev,resources=repositories,verbs=get;list;watch
+//+kubebuilder:rbac:groups=bootstrap.nephio.org,resources=packageinstallations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=bootstrap.nephio.org,resources=packageinstallations/status,verbs=get;update;patch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c any) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
@@ -68,19 +101,31 @@ func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c a
 	if err := porchconfigv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return nil, err
 	}
+	if err := bootstrapv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
 
 	r.Client = mgr.GetClient()
 	r.porchClient = cfg.PorchClient
+	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
 
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("BootstrapPackageController").
 		For(&porchv1alpha1.PackageRevision{}).
+		Watches(&capiv1beta1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.stagingPackageRevisions),
+			builder.WithPredicates(cluster.SelectionChanged())).
 		Complete(r)
 }
 
+// Indexes returns the indexes resolving the clusters by name.
+func (r *reconciler) Indexes() []reconcilerinterface.Index {
+	return cluster.Indexes()
+}
+
 type reconciler struct {
 	client.Client
 	porchClient client.Client
+	finalizer   *resource.APIFinalizer
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -97,6 +142,12 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// the package is removed from the clusters it is installed on before the
+	// package revision is deleted
+	if resource.WasDeleted(cr) {
+		return r.uninstall(ctx, cr)
+	}
+
 	// check if the packagerevision is part of a staging repository
 	// if not we can ignore this package revision
 	stagingPR, err := r.IsStagingPackageRevision(ctx, cr.Spec.RepositoryName)
@@ -115,43 +166,70 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, errors.Wrap(err, msg)
 		}
 		if len(resources) > 0 {
-			// we expect the clusterName to be applied to all resources in the
-			// package revision resources, so we find the cluster name by looking at the
-			// first resource in the resource list
-			clusterName, ok := resources[0].GetAnnotations()["nephio.org/cluster-name"]
+			if err := r.finalizer.AddFinalizer(ctx, cr); err != nil {
+				log.Error(err, "cannot add finalizer")
+				return ctrl.Result{}, err
+			}
+			// the annotations applying to the package as a whole are set
+			// on all its resources, so they can be read from any of them
+			if err := checkPackageAnnotations(resources); err != nil {
+				log.Error(err, "invalid package")
+				return ctrl.Result{}, nil
+			}
+			// the clusters can be selected with a label selector over the
+			// CAPI clusters
+			selector, ok, err := cluster.GetSelector(&resources[0])
+			if err != nil {
+				log.Error(err, "cannot select clusters")
+				return ctrl.Result{}, nil
+			}
+			if ok {
+				return r.installOnSelectedClusters(ctx, cr, selector, resources)
+			}
+			clusterName, ok := resources[0].GetAnnotations()[clusterNameKey]
 			if !ok {
 				log.Info("clusterName not found",
 					"resource", fmt.Sprintf("%s.%s.%s", resources[0].GetAPIVersion(), resources[0].GetKind(), resources[0].GetName()),
 					"annotations", resources[0].GetAnnotations())
 				return ctrl.Result{}, nil
 			}
-			clusterClient, ok, err := r.GetClusterClient(ctx, clusterName)
+			clusterClient, ok, err := cluster.Cluster{Client: r.Client}.ResolveClusterClient(ctx, clusterName)
 			if err != nil {
 				msg := fmt.Sprintf("failed to get cluster Secret for: %s", clusterName)
 				log.Error(err, msg)
 				return ctrl.Result{}, errors.Wrap(err, msg)
 			}
 			if ok {
-				clusterClient, ready, err := clusterClient.GetClusterClient(ctx)
+				remoteClient, ready, err := clusterClient.GetClusterClient(ctx)
 				if err != nil {
 					msg := "cannot get clusterClient"
 					log.Error(err, msg)
 					return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
 				}
 				if !ready {
-					log.Info("cluster not ready")
+					readiness := cluster.GetReadiness(ctx, clusterClient)
+					log.Info("cluster not ready", "reason", readiness.Reason, "message", readiness.Message)
+					if err := r.recordNotReady(ctx, cr, clusterName, readiness); err != nil {
+						log.Error(err, "cannot record installation")
+						return ctrl.Result{}, err
+					}
 					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 				}
-				// install the resources to the cluster
-				for _, resource := range resources {
-					resource := resource // required to prevent gosec warning: G601 (CWE-118): Implicit memory aliasing in for loop
-					log.Info("install manifest", "resource", fmt.Sprintf("%s.%s.%s", resource.GetAPIVersion(), resource.GetKind(), resource.GetName()))
-					if err := clusterClient.Apply(ctx, &resource); err != nil {
-						msg := fmt.Sprintf("cannot apply resource to cluster: resourceName: %s", resource.GetName())
-						log.Error(err, msg)
-						return ctrl.Result{}, errors.Wrap(err, msg)
+				results, err := r.install(ctx, cr, remoteClient, resources)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if results != nil {
+					if err := r.recordInstallation(ctx, cr, clusterName, results); err != nil {
+						log.Error(err, "cannot record installation")
+						return ctrl.Result{}, err
 					}
 				}
+				if err := r.recordInstalledClusters(ctx, cr, clusterName); err != nil {
+					log.Error(err, "cannot record installed clusters")
+					return ctrl.Result{}, err
+				}
+				return installResult(results)
 			} else {
 				// the clusterClient was not found, we retry
 				log.Info("cluster client not found, retry...")
@@ -162,27 +240,133 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
-func (r *reconciler) GetClusterClient(ctx context.Context, clusterName string) (cluster.ClusterClient, bool, error) {
-	// we need to find the cluster client secret
-	secrets := &corev1.SecretList{}
-	if err := r.List(ctx, secrets); err != nil {
-		return nil, false, err
+// installOnSelectedClusters installs the resources on the ready CAPI clusters
+// matching the selector. The clusters that are not ready get the resources
+// when they become ready, and the package is uninstalled from the clusters
+// that no longer match the selector.
+func (r *reconciler) installOnSelectedClusters(ctx context.Context, cr *porchv1alpha1.PackageRevision, selector labels.Selector, resources []unstructured.Unstructured) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	clusters, err := cluster.Cluster{Client: r.Client}.SelectClusters(ctx, selector)
+	if err != nil {
+		log.Error(err, "cannot select clusters")
+		return ctrl.Result{}, err
 	}
-	clusterSecret := corev1.Secret{}
-	for _, secret := range secrets.Items {
-		if strings.Contains(secret.GetName(), clusterName) {
-			clusterSecret = secret
-
-			clusterClient, ok := cluster.Cluster{
-				Client: r.Client,
-			}.GetClusterClient(&clusterSecret)
-
-			if ok {
-				return clusterClient, ok, nil
+	pending := false
+	selected := map[string]bool{}
+	installed := []string{}
+	all := []bootstrapv1alpha1.ResourceResult{}
+	for i := range clusters {
+		cl := &clusters[i]
+		selected[cl.GetName()] = true
+		log := log.WithValues("cluster", client.ObjectKeyFromObject(cl))
+		clusterClient, ok, err := cluster.Cluster{Client: r.Client}.GetClusterClientFor(ctx, client.ObjectKeyFromObject(cl))
+		if err != nil {
+			msg := fmt.Sprintf("failed to get cluster Secret for: %s", cl.GetName())
+			log.Error(err, msg)
+			return ctrl.Result{}, errors.Wrap(err, msg)
+		}
+		if !ok {
+			log.Info("cluster kubeconfig not found")
+			pending = true
+			continue
+		}
+		remoteClient, ready, err := clusterClient.GetClusterClient(ctx)
+		if err != nil {
+			msg := "cannot get clusterClient"
+			log.Error(err, msg)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
+		}
+		if !ready {
+			readiness := cluster.GetReadiness(ctx, clusterClient)
+			log.Info("cluster not ready", "reason", readiness.Reason, "message", readiness.Message)
+			if err := r.recordNotReady(ctx, cr, cl.GetName(), readiness); err != nil {
+				log.Error(err, "cannot record installation")
+				return ctrl.Result{}, err
 			}
+			pending = true
+			continue
+		}
+		results, err := r.install(ctx, cr, remoteClient, resources)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if results != nil {
+			if err := r.recordInstallation(ctx, cr, cl.GetName(), results); err != nil {
+				log.Error(err, "cannot record installation")
+				return ctrl.Result{}, err
+			}
+		}
+		installed = append(installed, cl.GetName())
+		all = append(all, results...)
+	}
+	if err := r.recordInstalledClusters(ctx, cr, installed...); err != nil {
+		log.Error(err, "cannot record installed clusters")
+		return ctrl.Result{}, err
+	}
+	// the package is removed from the clusters that no longer match the
+	// selector, a cluster that is not ready keeps it until it becomes ready
+	deselected := []string{}
+	for _, clusterName := range installedClusters(cr) {
+		if selected[clusterName] {
+			continue
+		}
+		done, err := r.uninstallFrom(ctx, cr, clusterName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			pending = true
+			continue
+		}
+		deselected = append(deselected, clusterName)
+	}
+	if len(deselected) > 0 {
+		if err := r.forgetInstallation(ctx, cr, deselected...); err != nil {
+			log.Error(err, "cannot record installation")
+			return ctrl.Result{}, err
+		}
+		if err := r.forgetInstalledClusters(ctx, cr, deselected...); err != nil {
+			log.Error(err, "cannot record installed clusters")
+			return ctrl.Result{}, err
 		}
 	}
-	return nil, false, nil
+	result, err := installResult(all)
+	if err == nil && pending {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return result, err
+}
+
+// stagingPackageRevisions returns the published package revisions of the
+// staging repositories, whose resources can select the CAPI cluster
+func (r *reconciler) stagingPackageRevisions(ctx context.Context, _ client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+	repos := &porchconfigv1alpha1.RepositoryList{}
+	if err := r.porchClient.List(ctx, repos); err != nil {
+		log.Error(err, "cannot list repositories")
+		return nil
+	}
+	staging := map[string]bool{}
+	for _, repo := range repos.Items {
+		if _, ok := repo.Annotations["nephio.org/staging"]; ok {
+			staging[repo.GetName()] = true
+		}
+	}
+	if len(staging) == 0 {
+		return nil
+	}
+	prs := &porchv1alpha1.PackageRevisionList{}
+	if err := r.List(ctx, prs); err != nil {
+		log.Error(err, "cannot list package revisions")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, pr := range prs.Items {
+		if staging[pr.Spec.RepositoryName] && porchv1alpha1.LifecycleIsPublished(pr.Spec.Lifecycle) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&pr)})
+		}
+	}
+	return requests
 }
 
 func (r *reconciler) IsStagingPackageRevision(ctx context.Context, repositoryName string) (bool, error) {
@@ -214,6 +398,27 @@ func (r *reconciler) getPrResources(ctx context.Context, req ctrl.Request) ([]un
 	return r.filterNonLocalResources(ctx, PackageRevisionResources.Spec.Resources)
 }
 
+// packageAnnotations are the annotations applying to a package as a whole
+var packageAnnotations = []string{clusterNameKey, cluster.SelectorAnnotation, applyConflictsKey}
+
+// checkPackageAnnotations returns an error if the resources of the package do
+// not agree on the annotations applying to the package as a whole: each of
+// them must be set to the same value on all the resources, or on none
+func checkPackageAnnotations(resources []unstructured.Unstructured) error {
+	for _, key := range packageAnnotations {
+		want, wantOk := resources[0].GetAnnotations()[key]
+		for i := range resources[1:] {
+			u := &resources[i+1]
+			if got, ok := u.GetAnnotations()[key]; ok != wantOk || got != want {
+				return fmt.Errorf("resources %s.%s.%s and %s.%s.%s disagree on the annotation %s",
+					resources[0].GetAPIVersion(), resources[0].GetKind(), resources[0].GetName(),
+					u.GetAPIVersion(), u.GetKind(), u.GetName(), key)
+			}
+		}
+	}
+	return nil
+}
+
 func includedFileTypes(path string, match []string) bool {
 	for _, m := range match {
 		file := filepath.Base(path)
@@ -226,7 +431,10 @@ func includedFileTypes(path string, match []string) bool {
 
 func (r *reconciler) filterNonLocalResources(ctx context.Context, resources map[string]string) ([]unstructured.Unstructured, error) {
 	inputs := []kio.Reader{}
-	for path, data := range resources {
+	// the resources are read in the order of their paths, so the package is
+	// installed the same way on every reconcile
+	for _, path := range slices.Sorted(maps.Keys(resources)) {
+		data := resources[path]
 		if includedFileTypes(path, []string{"*.yaml", "*.yml", "Kptfile"}) {
 			inputs = append(inputs, &kio.ByteReader{
 				Reader: strings.NewReader(data),
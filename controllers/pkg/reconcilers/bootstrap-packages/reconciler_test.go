@@ -19,8 +19,20 @@ package bootstrappackages
 import (
 	"context"
 	"fmt"
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfigv1alpha1 "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestGetResourcesPRR(t *testing.T) {
@@ -98,3 +110,93 @@ spec:
 		})
 	}
 }
+
+func TestCheckPackageAnnotations(t *testing.T) {
+	annotated := func(name string, annotations map[string]string) unstructured.Unstructured {
+		u := newManifest("v1", "ConfigMap", "ns1", name, nil)
+		u.SetAnnotations(annotations)
+		return u
+	}
+	edge01 := map[string]string{clusterNameKey: "edge01", applyConflictsKey: applyConflictsReport}
+
+	cases := map[string]struct {
+		resources   []unstructured.Unstructured
+		expectedErr bool
+	}{
+		"Agree": {
+			resources: []unstructured.Unstructured{annotated("a", edge01), annotated("b", edge01)},
+		},
+		"None": {
+			resources: []unstructured.Unstructured{annotated("a", nil), annotated("b", map[string]string{"other": "x"})},
+		},
+		"DifferentValue": {
+			resources: []unstructured.Unstructured{
+				annotated("a", edge01),
+				annotated("b", map[string]string{clusterNameKey: "edge02", applyConflictsKey: applyConflictsReport}),
+			},
+			expectedErr: true,
+		},
+		"NotSetOnAll": {
+			resources:   []unstructured.Unstructured{annotated("a", edge01), annotated("b", map[string]string{clusterNameKey: "edge01"})},
+			expectedErr: true,
+		},
+		"NotSetOnFirst": {
+			resources:   []unstructured.Unstructured{annotated("a", nil), annotated("b", map[string]string{cluster.SelectorAnnotation: "tier=edge"})},
+			expectedErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkPackageAnnotations(tc.resources)
+			if tc.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetResourcesOrder(t *testing.T) {
+	resources := map[string]string{}
+	for _, name := range []string{"c", "a", "d", "b"} {
+		resources[name+".yaml"] = fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", name)
+	}
+	r := reconciler{}
+	for range 5 {
+		us, err := r.filterNonLocalResources(context.Background(), resources)
+		require.NoError(t, err)
+		var names []string
+		for _, u := range us {
+			names = append(names, u.GetName())
+		}
+		require.Equal(t, []string{"a", "b", "c", "d"}, names)
+	}
+}
+
+func TestStagingPackageRevisions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, porchv1alpha1.AddToScheme(scheme))
+	require.NoError(t, porchconfigv1alpha1.AddToScheme(scheme))
+	newPR := func(name, repo string, lifecycle porchv1alpha1.PackageRevisionLifecycle) *porchv1alpha1.PackageRevision {
+		return &porchv1alpha1.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       porchv1alpha1.PackageRevisionSpec{RepositoryName: repo, Lifecycle: lifecycle},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&porchconfigv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "mgmt-staging",
+			Annotations: map[string]string{"nephio.org/staging": "true"},
+		}},
+		&porchconfigv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "catalog"}},
+		newPR("staging-published", "mgmt-staging", porchv1alpha1.PackageRevisionLifecyclePublished),
+		newPR("staging-draft", "mgmt-staging", porchv1alpha1.PackageRevisionLifecycleDraft),
+		newPR("catalog-published", "catalog", porchv1alpha1.PackageRevisionLifecyclePublished),
+	).Build()
+	r := reconciler{Client: c, porchClient: c}
+
+	got := r.stagingPackageRevisions(context.Background(), &capiv1beta1.Cluster{})
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "staging-published"}}}, got)
+}
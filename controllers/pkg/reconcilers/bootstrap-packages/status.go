@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrappackages
+
+import (
+	"fmt"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// computeStatus computes the status of an object on the cluster the way
+// kstatus does: the well known workloads are Current when their replicas are
+// updated and available, the other objects when they observed their
+// generation and their conditions do not report they are reconciling or
+// stalled. Objects without status are Current once they exist.
+func computeStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if u.GetDeletionTimestamp() != nil {
+		return bootstrapv1alpha1.ResourceStatusTerminating, "resource scheduled for deletion"
+	}
+	if observed, ok, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); ok && observed < u.GetGeneration() {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("generation %d not observed, observed generation %d", u.GetGeneration(), observed)
+	}
+	gk := u.GroupVersionKind().GroupKind()
+	switch {
+	case gk.Group == "apps" && gk.Kind == "Deployment":
+		return deploymentStatus(u)
+	case gk.Group == "apps" && gk.Kind == "StatefulSet":
+		return statefulSetStatus(u)
+	case gk.Group == "apps" && gk.Kind == "DaemonSet":
+		return daemonSetStatus(u)
+	case gk.Group == "batch" && gk.Kind == "Job":
+		return jobStatus(u)
+	case gk.Group == "apiextensions.k8s.io" && gk.Kind == "CustomResourceDefinition":
+		return crdStatus(u)
+	case gk.Group == "" && gk.Kind == "Namespace":
+		return phaseStatus(u, "Active")
+	case gk.Group == "" && gk.Kind == "PersistentVolumeClaim":
+		return phaseStatus(u, "Bound")
+	case gk.Group == "" && gk.Kind == "Pod":
+		return podStatus(u)
+	case gk.Group == "" && gk.Kind == "Service":
+		return serviceStatus(u)
+	}
+	return genericStatus(u)
+}
+
+func deploymentStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if c, ok := getCondition(u, "Progressing"); ok && c.reason == "ProgressDeadlineExceeded" {
+		return bootstrapv1alpha1.ResourceStatusFailed, c.message
+	}
+	replicas := specReplicas(u)
+	if updated := statusInt(u, "updatedReplicas"); updated < replicas {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("updated: %d/%d", updated, replicas)
+	}
+	if total := statusInt(u, "replicas"); total > replicas {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("pending termination: %d", total-replicas)
+	}
+	if available := statusInt(u, "availableReplicas"); available < replicas {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("available: %d/%d", available, replicas)
+	}
+	if ready := statusInt(u, "readyReplicas"); ready < replicas {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("ready: %d/%d", ready, replicas)
+	}
+	return bootstrapv1alpha1.ResourceStatusCurrent, fmt.Sprintf("deployment is available, replicas: %d", replicas)
+}
+
+func statefulSetStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	replicas := specReplicas(u)
+	if ready := statusInt(u, "readyReplicas"); ready < replicas {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("ready: %d/%d", ready, replicas)
+	}
+	if strategy, _, _ := unstructured.NestedString(u.Object, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+		return bootstrapv1alpha1.ResourceStatusCurrent, fmt.Sprintf("statefulset is ready, replicas: %d", replicas)
+	}
+	partition, _, _ := unstructured.NestedInt64(u.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if updated := statusInt(u, "updatedReplicas"); updated < replicas-partition {
+		return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("updated: %d/%d", updated, replicas-partition)
+	}
+	if partition == 0 {
+		current, _, _ := unstructured.NestedString(u.Object, "status", "currentRevision")
+		update, _, _ := unstructured.NestedString(u.Object, "status", "updateRevision")
+		if current != update {
+			return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("waiting for revision %s, current revision %s", update, current)
+		}
+	}
+	return bootstrapv1alpha1.ResourceStatusCurrent, fmt.Sprintf("statefulset is ready, replicas: %d", replicas)
+}
+
+func daemonSetStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	desired := statusInt(u, "desiredNumberScheduled")
+	for _, field := range []string{"currentNumberScheduled", "updatedNumberScheduled", "numberAvailable", "numberReady"} {
+		if n := statusInt(u, field); n < desired {
+			return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("%s: %d/%d", field, n, desired)
+		}
+	}
+	return bootstrapv1alpha1.ResourceStatusCurrent, fmt.Sprintf("daemonset is ready, scheduled: %d", desired)
+}
+
+func jobStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if c, ok := getCondition(u, "Failed"); ok && c.status == "True" {
+		return bootstrapv1alpha1.ResourceStatusFailed, c.message
+	}
+	if c, ok := getCondition(u, "Complete"); ok && c.status == "True" {
+		return bootstrapv1alpha1.ResourceStatusCurrent, "job completed"
+	}
+	return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("job in progress, active: %d", statusInt(u, "active"))
+}
+
+func crdStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if c, ok := getCondition(u, "NamesAccepted"); ok && c.status == "False" {
+		return bootstrapv1alpha1.ResourceStatusFailed, c.message
+	}
+	if c, ok := getCondition(u, "Established"); ok && c.status == "True" {
+		return bootstrapv1alpha1.ResourceStatusCurrent, "crd is established"
+	}
+	return bootstrapv1alpha1.ResourceStatusInProgress, "crd is not established"
+}
+
+// phaseStatus returns Current when the object is in the phase
+func phaseStatus(u *unstructured.Unstructured, phase string) (bootstrapv1alpha1.ResourceStatus, string) {
+	current, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	if current == phase {
+		return bootstrapv1alpha1.ResourceStatusCurrent, fmt.Sprintf("phase: %s", current)
+	}
+	if current == "Terminating" {
+		return bootstrapv1alpha1.ResourceStatusTerminating, fmt.Sprintf("phase: %s", current)
+	}
+	return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("phase: %s", current)
+}
+
+func podStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return bootstrapv1alpha1.ResourceStatusCurrent, "pod succeeded"
+	case "Failed":
+		return bootstrapv1alpha1.ResourceStatusFailed, "pod failed"
+	case "Running":
+		if c, ok := getCondition(u, "Ready"); ok && c.status == "True" {
+			return bootstrapv1alpha1.ResourceStatusCurrent, "pod is ready"
+		}
+	}
+	return bootstrapv1alpha1.ResourceStatusInProgress, fmt.Sprintf("pod is not ready, phase: %s", phase)
+}
+
+func serviceStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if t, _, _ := unstructured.NestedString(u.Object, "spec", "type"); t == "LoadBalancer" {
+		ingress, _, _ := unstructured.NestedSlice(u.Object, "status", "loadBalancer", "ingress")
+		if len(ingress) == 0 {
+			return bootstrapv1alpha1.ResourceStatusInProgress, "load balancer ingress not assigned"
+		}
+	}
+	return bootstrapv1alpha1.ResourceStatusCurrent, "service is ready"
+}
+
+// genericStatus computes the status of the objects from the Stalled,
+// Reconciling and Ready conditions
+func genericStatus(u *unstructured.Unstructured) (bootstrapv1alpha1.ResourceStatus, string) {
+	if c, ok := getCondition(u, "Stalled"); ok && c.status == "True" {
+		return bootstrapv1alpha1.ResourceStatusFailed, c.message
+	}
+	if c, ok := getCondition(u, "Reconciling"); ok && c.status == "True" {
+		return bootstrapv1alpha1.ResourceStatusInProgress, c.message
+	}
+	if c, ok := getCondition(u, "Ready"); ok && c.status != "True" {
+		return bootstrapv1alpha1.ResourceStatusInProgress, c.message
+	}
+	return bootstrapv1alpha1.ResourceStatusCurrent, "resource is current"
+}
+
+// condition is a condition of an unstructured object
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+func getCondition(u *unstructured.Unstructured, conditionType string) (condition, bool) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if !ok || m["type"] != conditionType {
+			continue
+		}
+		status, _ := m["status"].(string)
+		reason, _ := m["reason"].(string)
+		message, _ := m["message"].(string)
+		return condition{status: status, reason: reason, message: message}, true
+	}
+	return condition{}, false
+}
+
+// specReplicas returns the desired replicas of a workload, 1 when not set
+func specReplicas(u *unstructured.Unstructured) int64 {
+	if replicas, ok, _ := unstructured.NestedInt64(u.Object, "spec", "replicas"); ok {
+		return replicas
+	}
+	return 1
+}
+
+func statusInt(u *unstructured.Unstructured, field string) int64 {
+	n, _, _ := unstructured.NestedInt64(u.Object, "status", field)
+	return n
+}
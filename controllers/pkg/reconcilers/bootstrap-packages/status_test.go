@@ -0,0 +1,130 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrappackages
+
+import (
+	"testing"
+
+	bootstrapv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/bootstrap/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComputeStatus(t *testing.T) {
+	newObject := func(apiVersion, kind string, generation int64, fields map[string]any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: fields}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName("a")
+		u.SetGeneration(generation)
+		return u
+	}
+	conditions := func(conditions ...map[string]any) map[string]any {
+		items := []any{}
+		for _, c := range conditions {
+			items = append(items, c)
+		}
+		return map[string]any{"conditions": items}
+	}
+	deploymentStatus := func(updated, available int64) map[string]any {
+		return map[string]any{
+			"observedGeneration": int64(2),
+			"replicas":           int64(2),
+			"updatedReplicas":    updated,
+			"readyReplicas":      available,
+			"availableReplicas":  available,
+		}
+	}
+
+	cases := map[string]struct {
+		object *unstructured.Unstructured
+		want   bootstrapv1alpha1.ResourceStatus
+	}{
+		"ConfigMap": {
+			object: newObject("v1", "ConfigMap", 0, map[string]any{"data": map[string]any{"k": "v"}}),
+			want:   bootstrapv1alpha1.ResourceStatusCurrent,
+		},
+		"GenerationNotObserved": {
+			object: newObject("apps/v1", "Deployment", 3, map[string]any{"spec": map[string]any{"replicas": int64(2)}, "status": deploymentStatus(2, 2)}),
+			want:   bootstrapv1alpha1.ResourceStatusInProgress,
+		},
+		"DeploymentAvailable": {
+			object: newObject("apps/v1", "Deployment", 2, map[string]any{"spec": map[string]any{"replicas": int64(2)}, "status": deploymentStatus(2, 2)}),
+			want:   bootstrapv1alpha1.ResourceStatusCurrent,
+		},
+		"DeploymentRollingOut": {
+			object: newObject("apps/v1", "Deployment", 2, map[string]any{"spec": map[string]any{"replicas": int64(2)}, "status": deploymentStatus(1, 2)}),
+			want:   bootstrapv1alpha1.ResourceStatusInProgress,
+		},
+		"DeploymentProgressDeadlineExceeded": {
+			object: newObject("apps/v1", "Deployment", 2, map[string]any{"spec": map[string]any{"replicas": int64(2)}, "status": conditions(
+				map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"},
+			)}),
+			want: bootstrapv1alpha1.ResourceStatusFailed,
+		},
+		"DaemonSetScheduling": {
+			object: newObject("apps/v1", "DaemonSet", 1, map[string]any{"status": map[string]any{
+				"desiredNumberScheduled": int64(3),
+				"currentNumberScheduled": int64(3),
+				"updatedNumberScheduled": int64(3),
+				"numberAvailable":        int64(2),
+				"numberReady":            int64(2),
+			}}),
+			want: bootstrapv1alpha1.ResourceStatusInProgress,
+		},
+		"StatefulSetReady": {
+			object: newObject("apps/v1", "StatefulSet", 1, map[string]any{"spec": map[string]any{"replicas": int64(1)}, "status": map[string]any{
+				"readyReplicas":   int64(1),
+				"updatedReplicas": int64(1),
+				"currentRevision": "r1",
+				"updateRevision":  "r1",
+			}}),
+			want: bootstrapv1alpha1.ResourceStatusCurrent,
+		},
+		"JobFailed": {
+			object: newObject("batch/v1", "Job", 1, map[string]any{"status": conditions(
+				map[string]any{"type": "Failed", "status": "True", "message": "backoff limit exceeded"},
+			)}),
+			want: bootstrapv1alpha1.ResourceStatusFailed,
+		},
+		"CRDNotEstablished": {
+			object: newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", 1, map[string]any{}),
+			want:   bootstrapv1alpha1.ResourceStatusInProgress,
+		},
+		"NamespaceActive": {
+			object: newObject("v1", "Namespace", 0, map[string]any{"status": map[string]any{"phase": "Active"}}),
+			want:   bootstrapv1alpha1.ResourceStatusCurrent,
+		},
+		"CustomResourceNotReady": {
+			object: newObject("example.com/v1", "Example", 1, map[string]any{"status": conditions(
+				map[string]any{"type": "Ready", "status": "False", "message": "waiting"},
+			)}),
+			want: bootstrapv1alpha1.ResourceStatusInProgress,
+		},
+		"CustomResourceStalled": {
+			object: newObject("example.com/v1", "Example", 1, map[string]any{"status": conditions(
+				map[string]any{"type": "Stalled", "status": "True", "message": "invalid spec"},
+			)}),
+			want: bootstrapv1alpha1.ResourceStatusFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, _ := computeStatus(tc.object)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// decryptionKeyKey is the annotation of a CAPI cluster, or of the secret
+	// holding the credentials of a cluster of another provider, naming the
+	// secret, as <namespace>/<name> on the workload cluster, that holds the
+	// age identity decrypting the values encrypted for the cluster
+	decryptionKeyKey = "nephio.org/decryption-key"
+	// identityKey is the key of the identity in the decryption key secret
+	identityKey = "identity"
+	// ageHeader starts a value encrypted by age in the binary format
+	ageHeader = "age-encryption.org/v1\n"
+)
+
+// getDecryptionKey returns the identities referenced by the cluster with the
+// name, read from the workload cluster so the management cluster never stores
+// them
+func (r *reconciler) getDecryptionKey(ctx context.Context, clusterName string, clusterClient client.Reader) ([]age.Identity, error) {
+	cl, err := cluster.Cluster{Client: r.Client}.ResolveClusterObject(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cl == nil {
+		return nil, fmt.Errorf("cluster %s not found", clusterName)
+	}
+	ref, ok := cl.GetAnnotations()[decryptionKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s has no %s annotation", clusterName, decryptionKeyKey)
+	}
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid %s annotation %q, expecting <namespace>/<name>", decryptionKeyKey, ref)
+	}
+	secret := &corev1.Secret{}
+	if err := clusterClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("cannot get decryption key: %w", err)
+	}
+	identities, err := age.ParseIdentities(bytes.NewReader(secret.Data[identityKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid decryption key: %w", err)
+	}
+	return identities, nil
+}
+
+// isEncryptedValue returns true if the value is encrypted by age, armored or
+// not
+func isEncryptedValue(v []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(v), []byte(armor.Header)) || bytes.HasPrefix(v, []byte(ageHeader))
+}
+
+func isEncrypted(data map[string][]byte) bool {
+	for _, v := range data {
+		if isEncryptedValue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// decryptData returns the data with the encrypted values decrypted, the other
+// values are kept as is
+func decryptData(data map[string][]byte, identities ...age.Identity) (map[string][]byte, error) {
+	decrypted := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if !isEncryptedValue(v) {
+			decrypted[k] = v
+			continue
+		}
+		value, err := decrypt(v, identities...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decrypt %s: %w", k, err)
+		}
+		decrypted[k] = value
+	}
+	return decrypted, nil
+}
+
+func decrypt(v []byte, identities ...age.Identity) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(v)
+	if !bytes.HasPrefix(v, []byte(ageHeader)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(v)))
+	}
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
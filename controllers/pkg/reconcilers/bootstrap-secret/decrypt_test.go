@@ -0,0 +1,176 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrapsecret
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileEncrypted(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	edge01Key, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	edge02Key, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	// the values are encrypted as "age -a -r <recipient>" and "age -r <recipient>" do
+	password := encrypt(t, true, "s3cr3t", edge01Key.Recipient(), edge02Key.Recipient())
+	token := encrypt(t, false, "t0k3n", edge01Key.Recipient(), edge02Key.Recipient())
+
+	key := types.NamespacedName{Namespace: "default", Name: "configsync"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Annotations: map[string]string{
+				nephioAppKey:   syncApp,
+				clusterNameKey: "edge01,edge02",
+			},
+		},
+		Data: map[string][]byte{"username": []byte("nephio"), "password": password, "token": token},
+	}
+	mgmt := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&capiv1beta1.Cluster{}, cluster.ClusterNameIndex, cluster.IndexClusterByName).
+		WithObjects(
+			secret,
+			&capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "edge01",
+				Annotations: map[string]string{decryptionKeyKey: "nephio-system/decryption-key"},
+			}},
+			// edge02 does not reference its key
+			&capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge02"}},
+		).Build()
+	clusters := map[string]*testCluster{
+		"edge01": newCluster(clusterReady, key.Namespace),
+		"edge02": newCluster(clusterReady, key.Namespace),
+	}
+	// the key is held by the workload cluster
+	require.NoError(t, clusters["edge01"].client.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "nephio-system", Name: "decryption-key"},
+		Data:       map[string][]byte{identityKey: []byte("# created: 2026-10-17\n" + edge01Key.String() + "\n")},
+	}))
+	r := newTestReconciler(mgmt, clusters)
+	r.decryptionKey = r.getDecryptionKey
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, errorRequeue, result.RequeueAfter)
+	remote := getSecret(t, clusters["edge01"].client, key)
+	require.Equal(t, map[string][]byte{"username": []byte("nephio"), "password": []byte("s3cr3t"), "token": []byte("t0k3n")}, remote.Data)
+	require.Error(t, clusters["edge02"].client.Get(ctx, key, &corev1.Secret{}))
+
+	// the management cluster keeps the encrypted value
+	require.Equal(t, password, getSecret(t, mgmt, key).Data["password"])
+	require.Equal(t, "edge01/default", getSecret(t, mgmt, key).GetAnnotations()[syncedClustersKey])
+
+	// the decrypted secret is not seen as drifted
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, remote.GetResourceVersion(), getSecret(t, clusters["edge01"].client, key).GetResourceVersion())
+}
+
+func TestGetDecryptionKey(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	ref := map[string]string{decryptionKeyKey: "nephio-system/decryption-key"}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range cluster.Indexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	mgmt := builder.WithObjects(
+		&capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge01", Annotations: ref}},
+		// a cluster of another provider references its key from its credentials
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "argocd",
+				Name:        "cluster-edge02",
+				Labels:      map[string]string{"argocd.argoproj.io/secret-type": "cluster"},
+				Annotations: ref,
+			},
+			Data: map[string][]byte{"name": []byte("edge02")},
+		},
+		&capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge03"}},
+	).Build()
+	remote := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "nephio-system", Name: "decryption-key"},
+		Data:       map[string][]byte{identityKey: []byte(identity.String())},
+	}).Build()
+	r := newTestReconciler(mgmt, nil)
+
+	cases := map[string]struct {
+		clusterName string
+		wantErr     bool
+	}{
+		"Capi":         {clusterName: "edge01"},
+		"ArgoCD":       {clusterName: "edge02"},
+		"NoAnnotation": {clusterName: "edge03", wantErr: true},
+		"NotFound":     {clusterName: "edge04", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			identities, err := r.getDecryptionKey(ctx, tc.clusterName, remote)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []age.Identity{identity}, identities)
+		})
+	}
+}
+
+func encrypt(t *testing.T, armored bool, value string, recipients ...age.Recipient) []byte {
+	buf := &bytes.Buffer{}
+	var out io.WriteCloser = nopCloser{buf}
+	if armored {
+		out = armor.NewWriter(buf)
+	}
+	w, err := age.Encrypt(out, recipients...)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, value)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, out.Close())
+	return buf.Bytes()
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
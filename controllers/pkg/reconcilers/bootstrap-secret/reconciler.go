@@ -18,21 +18,25 @@ package bootstrapsecret
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"filippo.io/age"
 	"github.com/nephio-project/nephio/controllers/pkg/cluster"
 	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
-
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -45,26 +49,100 @@ const (
 	clusterNameKey     = "nephio.org/cluster-name"
 	nephioAppKey       = "nephio.org/app"
 	remoteNamespaceKey = "nephio.org/remote-namespace"
-	syncApp            = "tobeinstalledonremotecluster"
-	bootstrapApp       = "bootstrap"
+	// syncedClustersKey records the clusters the secret is installed on, as a
+	// comma separated list of <cluster>/<namespace>
+	syncedClustersKey = "nephio.org/synced-clusters"
+	syncApp           = "tobeinstalledonremotecluster"
+	bootstrapApp      = "bootstrap"
+	// finalizer removes the secret from the remote clusters before the
+	// secret is deleted
+	finalizer = "bootstrap.nephio.org/finalizer"
+
+	// syncClusterIndex indexes the secrets to be installed on remote
+	// clusters by the names of the clusters they select or are installed on
+	syncClusterIndex = "bootstrap.nephio.org/sync-cluster"
+	// syncSelectorIndex indexes the secrets to be installed on the remote
+	// clusters matching their cluster selector
+	syncSelectorIndex = "bootstrap.nephio.org/sync-selector"
+
+	// requeue periods
+	pendingRequeue    = 10 * time.Second
+	errorRequeue      = 30 * time.Second
+	driftResyncPeriod = 5 * time.Minute
 )
 
-//+kubebuilder:rbac:groups="*",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="*",resources=secrets,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get
+//+kubebuilder:rbac:groups=focom.nephio.org,resources=focomprovisioningrequests,verbs=get
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c any) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
 	r.Client = mgr.GetClient()
+	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
+	r.recorder = mgr.GetEventRecorderFor("bootstrap-secret-controller")
+	r.remoteClient = r.getRemoteClient
+	r.decryptionKey = r.getDecryptionKey
 
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("BootstrapSecretController").
 		For(&corev1.Secret{}).
+		Watches(&capiv1beta1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.selectingSecrets),
+			builder.WithPredicates(cluster.SelectionChanged())).
 		Complete(r)
 }
 
+// Indexes returns the indexes resolving the clusters by name, and the indexes
+// finding the secrets a cluster is relevant to.
+func (r *reconciler) Indexes() []reconcilerinterface.Index {
+	return append(cluster.Indexes(),
+		reconcilerinterface.Index{Object: &corev1.Secret{}, Field: syncClusterIndex, Extract: indexSyncCluster},
+		reconcilerinterface.Index{Object: &corev1.Secret{}, Field: syncSelectorIndex, Extract: indexSyncSelector},
+	)
+}
+
+func indexSyncCluster(obj client.Object) []string {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.GetAnnotations()[nephioAppKey] != syncApp {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(secret.GetAnnotations()[clusterNameKey], ",") {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for name := range syncedClusters(secret) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func indexSyncSelector(obj client.Object) []string {
+	if obj.GetAnnotations()[nephioAppKey] != syncApp {
+		return nil
+	}
+	if _, ok := obj.GetAnnotations()[cluster.SelectorAnnotation]; !ok {
+		return nil
+	}
+	return []string{"true"}
+}
+
 type reconciler struct {
 	client.Client
+	finalizer *resource.APIFinalizer
+	recorder  record.EventRecorder
+	// remoteClient returns the client of the named workload cluster, and why
+	// the cluster is not ready
+	remoteClient func(ctx context.Context, clusterName string) (resource.APIPatchingApplicator, clusterState, string, error)
+	// decryptionKey returns the identities that decrypt the encrypted values
+	// for the named workload cluster, read with the client of the cluster
+	decryptionKey func(ctx context.Context, clusterName string, clusterClient client.Reader) ([]age.Identity, error)
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -81,110 +159,189 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reconcile.Result{}, nil
 	}
 
-	// if the secret is being deleted don't do anything for now
-	if resource.WasDeleted(cr) {
-		return reconcile.Result{}, nil
-	}
-
 	// this branch handles installing the secrets to the remote cluster
 	// the secret is relevant to be installed in the workload cluster if:
-	// annotation key "nephio.org/app" == configsync
+	// annotation key "nephio.org/app" == tobeinstalledonremotecluster
 	// annotation key "nephio.org/cluster-name" different then "" and different then management
-	if cr.GetAnnotations()[nephioAppKey] == syncApp &&
-		cr.GetAnnotations()[clusterNameKey] != "" &&
-		cr.GetAnnotations()[clusterNameKey] != "mgmt" {
-		log.Info("reconcile secret")
-		// a clusterName can be modelled as multiple clusters to allow
-		// the secret to be deployed on multiple clusters
-		// syntax: nephio.org/cluster-name = cluster01,cluster02,cluster03
-		clusterName := cr.GetAnnotations()[clusterNameKey]
-		clusterNames := strings.Split(clusterName, ",")
-
-		// for each cluster specified in the annotation, we need to find the
-		// corresponding cluster credentials to access th remote cluster
-		// we walk through the secrets and check if the cluster api client accepts them
-		// if so we check is the assigned namespace is available and if so we apply the
-		// secret to the remote cluster.
-		for _, clusterName := range clusterNames {
-			secrets := &corev1.SecretList{}
-			if err := r.List(ctx, secrets); err != nil {
-				msg := "cannot list secrets"
-				log.Error(err, msg)
-				return ctrl.Result{}, errors.Wrap(err, msg)
-			}
-			found := false
-			for _, secret := range secrets.Items {
-				if strings.Contains(secret.GetName(), clusterName) {
-					secret := secret // required to prevent gosec warning: G601 (CWE-118): Implicit memory aliasing in for loop
-					clusterClient, ok := cluster.Cluster{Client: r.Client}.GetClusterClient(&secret)
-					if ok {
-						found = true
-						clusterClient, ready, err := clusterClient.GetClusterClient(ctx)
-						if err != nil {
-							msg := "cannot get clusterClient"
-							log.Error(err, msg)
-							return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
-						}
-						if !ready {
-							log.Info("cluster not ready")
-							return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-						}
-
-						// remoteNamespace holds the namespace of the remote cluster
-						// on which this secret is to be installed.
-						// e.g. configsync requires it to be installed in configsync-management
-						// but the mgmt cluster already has configsync-management in use, so
-						// we need the ability to change the namespace of the remote cluster
-						// for this secret
-						// the controller uses the namespace of the cr by default and if the
-						// remoteNamespace annotation `"nephio.org/remote-namespace"` is set
-						// it will use the value of the  annotation as the remote namespace
-						remoteNamespace := cr.Namespace
-						if rns, ok := cr.GetAnnotations()[remoteNamespaceKey]; ok {
-							remoteNamespace = rns
-						}
-						// check if the remote namespace exists, if not retry
-						ns := &corev1.Namespace{}
-						if err = clusterClient.Get(ctx, types.NamespacedName{Name: remoteNamespace}, ns); err != nil {
-							if resource.IgnoreNotFound(err) != nil {
-								msg := fmt.Sprintf("cannot get namespace: %s", remoteNamespace)
-								log.Error(err, msg)
-								return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
-							}
-							msg := fmt.Sprintf("namespace: %s, does not exist, retry...", remoteNamespace)
-							log.Info(msg)
-							return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-						}
-
-						newcr := cr.DeepCopy()
-						// we overwrite 2 annotations that have specific information
-						newcr.Annotations[nephioAppKey] = bootstrapApp
-						newcr.Annotations[clusterNameKey] = clusterName
-
-						newcr.ResourceVersion = ""
-						newcr.UID = ""
-						newcr.Namespace = remoteNamespace
-						log.Info("secret info", "secret", newcr.Annotations)
-						if err := clusterClient.Apply(ctx, newcr); err != nil {
-							msg := fmt.Sprintf("cannot apply secret to cluster %s", clusterName)
-							log.Error(err, msg)
-							return ctrl.Result{}, errors.Wrap(err, msg)
-						}
-					}
-				}
-				if found {
-					// speeds up the loop
-					break
-				}
-			}
-			// we need to find a cluster for each clusterName, so
-			// even in case of multiple
-			if !found {
-				// the cluster client was not found, we retry
-				log.Info("cluster client not found, retry...")
-				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	// The secret is removed from the clusters it was installed on when it is
+	// deleted, or when the clusters are no longer selected.
+	desired, err := r.desiredClusters(ctx, cr)
+	if err != nil {
+		log.Error(err, "cannot select clusters")
+		r.recorder.Event(cr, corev1.EventTypeWarning, reasonFailed, err.Error())
+		return ctrl.Result{}, nil
+	}
+	synced := syncedClusters(cr)
+	if len(desired) == 0 && len(synced) == 0 {
+		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
+			log.Error(err, "cannot remove finalizer")
+			return ctrl.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	log.Info("reconcile secret", "clusters", desired, "synced", synced)
+
+	// add finalizer to remove the secret from the remote clusters before
+	// the secret is deleted
+	if len(desired) > 0 {
+		if err := r.finalizer.AddFinalizer(ctx, cr); err != nil {
+			log.Error(err, "cannot add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	result := syncResult{}
+	for clusterName, namespace := range desired {
+		if err := r.syncCluster(ctx, cr, clusterName, namespace); err != nil {
+			result.add(err)
+			continue
+		}
+		// remove the secret from the namespace it was installed in before
+		if old, ok := synced[clusterName]; ok && old != namespace {
+			if err := r.removeCluster(ctx, cr, clusterName, old); err != nil {
+				result.add(err)
 			}
 		}
+		synced[clusterName] = namespace
+	}
+	for clusterName, namespace := range synced {
+		if _, ok := desired[clusterName]; ok {
+			continue
+		}
+		if err := r.removeCluster(ctx, cr, clusterName, namespace); err != nil {
+			result.add(err)
+			continue
+		}
+		delete(synced, clusterName)
+	}
+
+	if err := r.recordSyncedClusters(ctx, cr, synced); err != nil {
+		log.Error(err, "cannot record synced clusters")
+		return ctrl.Result{}, err
+	}
+	if result.pending() {
+		return result.requeue(), nil
+	}
+	if len(desired) == 0 {
+		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
+			log.Error(err, "cannot remove finalizer")
+			return ctrl.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	// resync to correct changes made to the secrets on the remote clusters
+	return ctrl.Result{RequeueAfter: driftResyncPeriod}, nil
+}
+
+// desiredClusters returns the clusters, and the namespaces in these
+// clusters, the secret is to be installed on. No clusters are returned for a
+// secret that is deleted.
+func (r *reconciler) desiredClusters(ctx context.Context, cr *corev1.Secret) (map[string]string, error) {
+	clusters := map[string]string{}
+	if resource.WasDeleted(cr) || cr.GetAnnotations()[nephioAppKey] != syncApp {
+		return clusters, nil
+	}
+	// remoteNamespace holds the namespace of the remote cluster
+	// on which this secret is to be installed.
+	// e.g. configsync requires it to be installed in configsync-management
+	// but the mgmt cluster already has configsync-management in use, so
+	// we need the ability to change the namespace of the remote cluster
+	// for this secret
+	// the controller uses the namespace of the cr by default and if the
+	// remoteNamespace annotation `"nephio.org/remote-namespace"` is set
+	// it will use the value of the  annotation as the remote namespace
+	remoteNamespace := cr.Namespace
+	if rns, ok := cr.GetAnnotations()[remoteNamespaceKey]; ok {
+		remoteNamespace = rns
+	}
+	// a clusterName can be modelled as multiple clusters to allow
+	// the secret to be deployed on multiple clusters
+	// syntax: nephio.org/cluster-name = cluster01,cluster02,cluster03
+	for _, clusterName := range strings.Split(cr.GetAnnotations()[clusterNameKey], ",") {
+		if clusterName != "" && clusterName != "mgmt" {
+			clusters[clusterName] = remoteNamespace
+		}
+	}
+	// the clusters can also be selected with a label selector over the CAPI
+	// clusters, syntax: nephio.org/cluster-selector = region=eu-west,tier=edge
+	selector, ok, err := cluster.GetSelector(cr)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		selected, err := cluster.Cluster{Client: r.Client}.SelectClusters(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, cl := range selected {
+			clusters[cl.GetName()] = remoteNamespace
+		}
+	}
+	return clusters, nil
+}
+
+// selectingSecrets returns the secrets to be installed on the CAPI cluster,
+// by name or by selector, and the secrets installed on it
+func (r *reconciler) selectingSecrets(ctx context.Context, obj client.Object) []reconcile.Request {
+	named := &corev1.SecretList{}
+	if err := r.List(ctx, named, client.MatchingFields{syncClusterIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "cannot list secrets")
+		return nil
+	}
+	selecting := &corev1.SecretList{}
+	if err := r.List(ctx, selecting, client.MatchingFields{syncSelectorIndex: "true"}); err != nil {
+		log.FromContext(ctx).Error(err, "cannot list secrets")
+		return nil
+	}
+	var requests []reconcile.Request
+	add := func(secret *corev1.Secret) {
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(secret)}
+		if !slices.Contains(requests, req) {
+			requests = append(requests, req)
+		}
+	}
+	for i := range named.Items {
+		add(&named.Items[i])
+	}
+	for i := range selecting.Items {
+		if selector, ok, err := cluster.GetSelector(&selecting.Items[i]); ok && err == nil && selector.Matches(labels.Set(obj.GetLabels())) {
+			add(&selecting.Items[i])
+		}
+	}
+	return requests
+}
+
+// syncedClusters returns the clusters, and the namespaces in these clusters,
+// the secret is installed on
+func syncedClusters(cr *corev1.Secret) map[string]string {
+	clusters := map[string]string{}
+	for _, entry := range strings.Split(cr.GetAnnotations()[syncedClustersKey], ",") {
+		if clusterName, namespace, ok := strings.Cut(entry, "/"); ok {
+			clusters[clusterName] = namespace
+		}
+	}
+	return clusters
+}
+
+func (r *reconciler) recordSyncedClusters(ctx context.Context, cr *corev1.Secret, synced map[string]string) error {
+	entries := make([]string, 0, len(synced))
+	for clusterName, namespace := range synced {
+		entries = append(entries, clusterName+"/"+namespace)
+	}
+	slices.Sort(entries)
+	value := strings.Join(entries, ",")
+	if cr.GetAnnotations()[syncedClustersKey] == value {
+		return nil
+	}
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(annotations, syncedClustersKey)
+	} else {
+		annotations[syncedClustersKey] = value
 	}
-	return ctrl.Result{}, nil
+	cr.SetAnnotations(annotations)
+	return r.Update(ctx, cr)
 }
@@ -0,0 +1,283 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrapsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testCluster struct {
+	client client.Client
+	state  clusterState
+}
+
+func newTestReconciler(mgmt client.Client, clusters map[string]*testCluster) *reconciler {
+	return &reconciler{
+		Client:    mgmt,
+		finalizer: resource.NewAPIFinalizer(mgmt, finalizer),
+		recorder:  record.NewFakeRecorder(100),
+		remoteClient: func(ctx context.Context, clusterName string) (resource.APIPatchingApplicator, clusterState, string, error) {
+			c, ok := clusters[clusterName]
+			if !ok {
+				return resource.APIPatchingApplicator{}, clusterNotFound, "", nil
+			}
+			return resource.NewAPIPatchingApplicator(c.client), c.state, "", nil
+		},
+	}
+}
+
+func newCluster(state clusterState, namespaces ...string) *testCluster {
+	objs := []client.Object{}
+	for _, ns := range namespaces {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+	return &testCluster{client: fake.NewClientBuilder().WithObjects(objs...).Build(), state: state}
+}
+
+func getSecret(t *testing.T, c client.Client, key types.NamespacedName) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), key, secret))
+	return secret
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "configsync"}
+	remoteKey := types.NamespacedName{Namespace: "config-management-system", Name: "configsync"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Annotations: map[string]string{
+				nephioAppKey:       syncApp,
+				clusterNameKey:     "edge01,edge02,mgmt",
+				remoteNamespaceKey: remoteKey.Namespace,
+			},
+		},
+		Data: map[string][]byte{"token": []byte("t1")},
+	}
+	mgmt := fake.NewClientBuilder().WithObjects(secret).Build()
+	clusters := map[string]*testCluster{
+		"edge01": newCluster(clusterReady, remoteKey.Namespace),
+		"edge02": newCluster(clusterNotReady, remoteKey.Namespace),
+	}
+	r := newTestReconciler(mgmt, clusters)
+	req := ctrl.Request{NamespacedName: key}
+
+	// the secret is installed on the ready cluster, the other one is retried
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, pendingRequeue, result.RequeueAfter)
+	cr := getSecret(t, mgmt, key)
+	require.Equal(t, []string{finalizer}, cr.GetFinalizers())
+	require.Equal(t, "edge01/config-management-system", cr.GetAnnotations()[syncedClustersKey])
+	remote := getSecret(t, clusters["edge01"].client, remoteKey)
+	require.Equal(t, secret.Data, remote.Data)
+	require.Equal(t, bootstrapApp, remote.GetAnnotations()[nephioAppKey])
+	require.Equal(t, "edge01", remote.GetAnnotations()[clusterNameKey])
+	require.NotContains(t, remote.GetAnnotations(), syncedClustersKey)
+
+	clusters["edge02"].state = clusterReady
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, driftResyncPeriod, result.RequeueAfter)
+	cr = getSecret(t, mgmt, key)
+	require.Equal(t, "edge01/config-management-system,edge02/config-management-system", cr.GetAnnotations()[syncedClustersKey])
+	getSecret(t, clusters["edge02"].client, remoteKey)
+
+	// changes made on the remote cluster are corrected
+	remote = getSecret(t, clusters["edge01"].client, remoteKey)
+	remote.Data["extra"] = []byte("x")
+	require.NoError(t, clusters["edge01"].client.Update(ctx, remote))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, secret.Data, getSecret(t, clusters["edge01"].client, remoteKey).Data)
+
+	// the secret is removed from the clusters that are no longer selected
+	cr.Annotations[clusterNameKey] = "edge01"
+	require.NoError(t, mgmt.Update(ctx, cr))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "edge01/config-management-system", getSecret(t, mgmt, key).GetAnnotations()[syncedClustersKey])
+	require.Error(t, clusters["edge02"].client.Get(ctx, remoteKey, &corev1.Secret{}))
+
+	// the secret is removed from all clusters before it is deleted
+	require.NoError(t, mgmt.Delete(ctx, getSecret(t, mgmt, key)))
+	clusters["edge01"].state = clusterNotReady
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, pendingRequeue, result.RequeueAfter)
+	getSecret(t, mgmt, key)
+
+	clusters["edge01"].state = clusterReady
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Error(t, clusters["edge01"].client.Get(ctx, remoteKey, &corev1.Secret{}))
+	require.Error(t, mgmt.Get(ctx, key, &corev1.Secret{}))
+}
+
+func TestReconcileRemoteNamespace(t *testing.T) {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "configsync"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Annotations: map[string]string{
+				nephioAppKey:   syncApp,
+				clusterNameKey: "edge01",
+			},
+		},
+	}
+	mgmt := fake.NewClientBuilder().WithObjects(secret).Build()
+	clusters := map[string]*testCluster{"edge01": newCluster(clusterReady)}
+	r := newTestReconciler(mgmt, clusters)
+	req := ctrl.Request{NamespacedName: key}
+
+	// the secret waits for its namespace
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, pendingRequeue, result.RequeueAfter)
+	require.NotContains(t, getSecret(t, mgmt, key).GetAnnotations(), syncedClustersKey)
+
+	for _, ns := range []string{"default", "other"} {
+		require.NoError(t, clusters["edge01"].client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}))
+	}
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	getSecret(t, clusters["edge01"].client, key)
+
+	// the secret moves to the new remote namespace
+	cr := getSecret(t, mgmt, key)
+	cr.Annotations[remoteNamespaceKey] = "other"
+	require.NoError(t, mgmt.Update(ctx, cr))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "edge01/other", getSecret(t, mgmt, key).GetAnnotations()[syncedClustersKey])
+	require.Error(t, clusters["edge01"].client.Get(ctx, key, &corev1.Secret{}))
+	getSecret(t, clusters["edge01"].client, types.NamespacedName{Namespace: "other", Name: key.Name})
+}
+
+func TestReconcileSelector(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	key := types.NamespacedName{Namespace: "default", Name: "configsync"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Annotations: map[string]string{
+				nephioAppKey:               syncApp,
+				cluster.SelectorAnnotation: "region=eu-west,tier=edge",
+			},
+		},
+	}
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   key.Namespace,
+			Name:        "other",
+			Annotations: map[string]string{nephioAppKey: syncApp, clusterNameKey: "core01"},
+		},
+	}
+	edge01 := &capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "edge01",
+		Labels:    map[string]string{"region": "eu-west", "tier": "edge"},
+	}}
+	core01 := &capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "core01",
+		Labels:    map[string]string{"region": "eu-west", "tier": "core"},
+	}}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range (&reconciler{}).Indexes() {
+		builder = builder.WithIndex(index.Object, index.Field, index.Extract)
+	}
+	mgmt := builder.WithObjects(secret, other, edge01, core01).Build()
+	clusters := map[string]*testCluster{
+		"edge01": newCluster(clusterReady, key.Namespace),
+		"core01": newCluster(clusterReady, key.Namespace),
+	}
+	r := newTestReconciler(mgmt, clusters)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Equal(t, "edge01/default", getSecret(t, mgmt, key).GetAnnotations()[syncedClustersKey])
+	getSecret(t, clusters["edge01"].client, key)
+	require.Error(t, clusters["core01"].client.Get(ctx, key, &corev1.Secret{}))
+
+	// the secrets selecting a cluster are reconciled on its changes
+	require.Equal(t, []reconcile.Request{{NamespacedName: key}}, r.selectingSecrets(ctx, edge01))
+	require.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(other)}}, r.selectingSecrets(ctx, core01))
+
+	// an invalid selector is reported, the installed secrets are left alone
+	cr := getSecret(t, mgmt, key)
+	cr.Annotations[cluster.SelectorAnnotation] = "region in eu-west"
+	require.NoError(t, mgmt.Update(ctx, cr))
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Equal(t, "edge01/default", getSecret(t, mgmt, key).GetAnnotations()[syncedClustersKey])
+}
+
+func TestSyncIndexes(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		clusters    []string
+		selector    []string
+	}{
+		"NotSynced": {
+			annotations: map[string]string{clusterNameKey: "edge01", cluster.SelectorAnnotation: "tier=edge"},
+		},
+		"Named": {
+			annotations: map[string]string{nephioAppKey: syncApp, clusterNameKey: "edge01,edge02,edge01"},
+			clusters:    []string{"edge01", "edge02"},
+		},
+		"Installed": {
+			annotations: map[string]string{nephioAppKey: syncApp, clusterNameKey: "edge01", syncedClustersKey: "edge01/default,core01/default"},
+			clusters:    []string{"edge01", "core01"},
+		},
+		"Selector": {
+			annotations: map[string]string{nephioAppKey: syncApp, cluster.SelectorAnnotation: "tier=edge"},
+			selector:    []string{"true"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "configsync", Annotations: tc.annotations}}
+			require.ElementsMatch(t, tc.clusters, indexSyncCluster(secret))
+			require.Equal(t, tc.selector, indexSyncSelector(secret))
+		})
+	}
+}
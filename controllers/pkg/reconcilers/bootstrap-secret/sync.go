@@ -0,0 +1,248 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reasons of the events recorded on the secret for each cluster
+const (
+	reasonSynced         = "Synced"
+	reasonDriftCorrected = "DriftCorrected"
+	reasonRemoved        = "Removed"
+	reasonPending        = "SyncPending"
+	reasonFailed         = "SyncFailed"
+)
+
+type clusterState int
+
+const (
+	// clusterNotFound is the state of a cluster that does not exist (anymore)
+	clusterNotFound clusterState = iota
+	clusterNotReady
+	clusterReady
+)
+
+// errPending reports a cluster the secret cannot be synced to yet
+var errPending = errors.New("pending")
+
+// syncResult collects the errors of the clusters of a secret
+type syncResult struct {
+	pendings int
+	failures int
+}
+
+func (r *syncResult) add(err error) {
+	if errors.Is(err, errPending) {
+		r.pendings++
+		return
+	}
+	r.failures++
+}
+
+func (r *syncResult) pending() bool {
+	return r.pendings > 0 || r.failures > 0
+}
+
+func (r *syncResult) requeue() ctrl.Result {
+	if r.failures > 0 {
+		return ctrl.Result{RequeueAfter: errorRequeue}
+	}
+	return ctrl.Result{RequeueAfter: pendingRequeue}
+}
+
+// syncCluster installs the secret in the namespace of the cluster, and
+// corrects any change made to it on the cluster
+func (r *reconciler) syncCluster(ctx context.Context, cr *corev1.Secret, clusterName, namespace string) error {
+	log := log.FromContext(ctx).WithValues("cluster", clusterName, "namespace", namespace)
+	clusterClient, state, reason, err := r.remoteClient(ctx, clusterName)
+	if err != nil {
+		log.Error(err, "cannot get clusterClient")
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot get client of cluster %s: %s", clusterName, err.Error())
+		return err
+	}
+	switch state {
+	case clusterNotFound:
+		log.Info("cluster client not found, retry...")
+		r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonPending, "cluster %s not found", clusterName)
+		return errPending
+	case clusterNotReady:
+		log.Info("cluster not ready", "reason", reason)
+		r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonPending, "cluster %s not ready: %s", clusterName, reason)
+		return errPending
+	}
+
+	// check if the remote namespace exists, if not retry
+	ns := &corev1.Namespace{}
+	if err := clusterClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			log.Error(err, "cannot get namespace")
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot get namespace %s of cluster %s: %s", namespace, clusterName, err.Error())
+			return err
+		}
+		log.Info("namespace does not exist, retry...")
+		r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonPending, "namespace %s does not exist in cluster %s", namespace, clusterName)
+		return errPending
+	}
+
+	want := remoteSecret(cr, clusterName, namespace)
+	if isEncrypted(want.Data) {
+		// the values are decrypted for the cluster only
+		identities, err := r.decryptionKey(ctx, clusterName, clusterClient)
+		if err == nil {
+			want.Data, err = decryptData(want.Data, identities...)
+		}
+		if err != nil {
+			log.Error(err, "cannot decrypt secret")
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot decrypt secret for cluster %s: %s", clusterName, err.Error())
+			return err
+		}
+	}
+	got := &corev1.Secret{}
+	if err := clusterClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: want.GetName()}, got); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			log.Error(err, "cannot get secret")
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot get secret from cluster %s: %s", clusterName, err.Error())
+			return err
+		}
+		if err := clusterClient.Create(ctx, want); err != nil {
+			log.Error(err, "cannot apply secret to cluster")
+			r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot apply secret to cluster %s: %s", clusterName, err.Error())
+			return err
+		}
+		log.Info("secret installed")
+		r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonSynced, "secret installed in namespace %s of cluster %s", namespace, clusterName)
+		return nil
+	}
+	if secretMatches(got, want) {
+		return nil
+	}
+	// the secret is updated rather than patched so keys added on the
+	// cluster are removed
+	got.Type = want.Type
+	got.Data = want.Data
+	got.Labels = want.Labels
+	got.Annotations = want.Annotations
+	if err := clusterClient.Update(ctx, got); err != nil {
+		log.Error(err, "cannot update secret in cluster")
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot update secret in cluster %s: %s", clusterName, err.Error())
+		return err
+	}
+	log.Info("secret updated")
+	r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonDriftCorrected, "secret updated in namespace %s of cluster %s", namespace, clusterName)
+	return nil
+}
+
+// removeCluster removes the secret from the namespace of the cluster. A
+// cluster that no longer exists took the secret with it.
+func (r *reconciler) removeCluster(ctx context.Context, cr *corev1.Secret, clusterName, namespace string) error {
+	log := log.FromContext(ctx).WithValues("cluster", clusterName, "namespace", namespace)
+	clusterClient, state, reason, err := r.remoteClient(ctx, clusterName)
+	if err != nil {
+		log.Error(err, "cannot get clusterClient")
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot get client of cluster %s: %s", clusterName, err.Error())
+		return err
+	}
+	switch state {
+	case clusterNotFound:
+		log.Info("cluster not found, nothing to remove")
+		return nil
+	case clusterNotReady:
+		log.Info("cluster not ready", "reason", reason)
+		r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonPending, "cannot remove secret, cluster %s not ready: %s", clusterName, reason)
+		return errPending
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: cr.GetName()}}
+	if err := clusterClient.Delete(ctx, secret); resource.IgnoreNotFound(err) != nil {
+		log.Error(err, "cannot remove secret from cluster")
+		r.recorder.Eventf(cr, corev1.EventTypeWarning, reasonFailed, "cannot remove secret from cluster %s: %s", clusterName, err.Error())
+		return err
+	}
+	log.Info("secret removed")
+	r.recorder.Eventf(cr, corev1.EventTypeNormal, reasonRemoved, "secret removed from namespace %s of cluster %s", namespace, clusterName)
+	return nil
+}
+
+// remoteSecret returns the copy of the secret installed on the cluster
+func remoteSecret(cr *corev1.Secret, clusterName, namespace string) *corev1.Secret {
+	annotations := maps.Clone(cr.GetAnnotations())
+	// we overwrite 2 annotations that have specific information
+	annotations[nephioAppKey] = bootstrapApp
+	annotations[clusterNameKey] = clusterName
+	delete(annotations, syncedClustersKey)
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        cr.GetName(),
+			Labels:      maps.Clone(cr.GetLabels()),
+			Annotations: annotations,
+		},
+		Type: cr.Type,
+		Data: maps.Clone(cr.Data),
+	}
+}
+
+func secretMatches(got, want *corev1.Secret) bool {
+	return got.Type == want.Type &&
+		maps.EqualFunc(got.Data, want.Data, func(a, b []byte) bool { return string(a) == string(b) }) &&
+		maps.Equal(got.Labels, want.Labels) &&
+		maps.Equal(got.Annotations, want.Annotations)
+}
+
+// getRemoteClient returns the client of the cluster with the name, resolved
+// to the <name>-kubeconfig secret of the CAPI cluster with the name, or to the
+// credentials of the cluster of another provider, and why the cluster is not
+// ready
+func (r *reconciler) getRemoteClient(ctx context.Context, clusterName string) (resource.APIPatchingApplicator, clusterState, string, error) {
+	clusterClient, ok, err := cluster.Cluster{Client: r.Client}.ResolveClusterClient(ctx, clusterName)
+	if err != nil {
+		return resource.APIPatchingApplicator{}, clusterNotFound, "", fmt.Errorf("cannot get kubeconfig: %w", err)
+	}
+	if !ok {
+		// the kubeconfig of a CAPI cluster that is being provisioned
+		cl, err := cluster.Cluster{Client: r.Client}.GetCapiCluster(ctx, clusterName)
+		if err != nil {
+			return resource.APIPatchingApplicator{}, clusterNotFound, "", err
+		}
+		if cl != nil {
+			return resource.APIPatchingApplicator{}, clusterNotReady, "kubeconfig not found, cluster being provisioned", nil
+		}
+		return resource.APIPatchingApplicator{}, clusterNotFound, "", nil
+	}
+	remoteClient, ready, err := clusterClient.GetClusterClient(ctx)
+	if err != nil {
+		return resource.APIPatchingApplicator{}, clusterNotReady, "", err
+	}
+	if !ready {
+		readiness := cluster.GetReadiness(ctx, clusterClient)
+		return resource.APIPatchingApplicator{}, clusterNotReady, fmt.Sprintf("%s: %s", readiness.Reason, readiness.Message), nil
+	}
+	return remoteClient, clusterReady, "", nil
+}
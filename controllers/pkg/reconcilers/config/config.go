@@ -36,4 +36,6 @@ type ControllerConfig struct {
 	IpamClientProxy         clientproxy.Proxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim]
 	VlanClientProxy         clientproxy.Proxy[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim]
 	ApprovalRequeueDuration int64
+	ApprovalRecordRetention time.Duration
+	RollbackRequeueDuration int64
 }
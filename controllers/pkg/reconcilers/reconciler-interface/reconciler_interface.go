@@ -17,8 +17,12 @@ package reconcilerinterface
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -35,3 +39,41 @@ var Reconcilers = map[string]Reconciler{}
 func Register(name string, r Reconciler) {
 	Reconcilers[name] = r
 }
+
+// Index is a field index on the objects of a type in the cache of the manager
+type Index struct {
+	Object  client.Object
+	Field   string
+	Extract client.IndexerFunc
+}
+
+// Indexer is implemented by the reconcilers that list objects from the cache
+// of the manager with field indexes. The indexes are registered with
+// SetupIndexes before the reconcilers are set up.
+type Indexer interface {
+	Indexes() []Index
+}
+
+// SetupIndexes registers the indexes of the reconcilers with the field
+// indexer of the manager. An index shared by several reconcilers is
+// registered once.
+func SetupIndexes(ctx context.Context, indexer client.FieldIndexer, reconcilers []Reconciler) error {
+	registered := map[string]bool{}
+	for _, r := range reconcilers {
+		i, ok := r.(Indexer)
+		if !ok {
+			continue
+		}
+		for _, index := range i.Indexes() {
+			key := fmt.Sprintf("%s/%s", reflect.TypeOf(index.Object), index.Field)
+			if registered[key] {
+				continue
+			}
+			if err := indexer.IndexField(ctx, index.Object, index.Field, index.Extract); err != nil {
+				return fmt.Errorf("cannot index %s: %w", key, err)
+			}
+			registered[key] = true
+		}
+	}
+	return nil
+}
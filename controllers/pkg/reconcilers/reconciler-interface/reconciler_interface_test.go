@@ -0,0 +1,64 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcilerinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fieldIndexer struct {
+	fields []string
+}
+
+func (r *fieldIndexer) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	r.fields = append(r.fields, field)
+	return nil
+}
+
+type indexingReconciler struct {
+	Reconciler
+	indexes []Index
+}
+
+func (r indexingReconciler) Indexes() []Index {
+	return r.indexes
+}
+
+type plainReconciler struct {
+	Reconciler
+}
+
+func TestSetupIndexes(t *testing.T) {
+	name := func(client.Object) []string { return nil }
+	indexer := &fieldIndexer{}
+	require.NoError(t, SetupIndexes(context.Background(), indexer, []Reconciler{
+		indexingReconciler{indexes: []Index{
+			{Object: &corev1.Secret{}, Field: "a", Extract: name},
+			{Object: &corev1.Secret{}, Field: "b", Extract: name},
+		}},
+		plainReconciler{},
+		// the indexes shared by several reconcilers are registered once
+		indexingReconciler{indexes: []Index{
+			{Object: &corev1.Secret{}, Field: "b", Extract: name},
+			{Object: &corev1.ConfigMap{}, Field: "b", Extract: name},
+		}},
+	}))
+	require.Equal(t, []string{"a", "b", "b"}, indexer.fields)
+}
@@ -0,0 +1,84 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package repository
+
+import (
+	"fmt"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AdoptAnnotation allows the Repository to take ownership of a repository
+	// that already exists on the git server, if set to "true". Without it the
+	// Repository fails when a repository with the same name exists.
+	AdoptAnnotation = "infra.nephio.org/adopt"
+
+	// ConditionTypeOrigin reports in its reason whether the repository managed
+	// by the Repository was Created or Adopted
+	ConditionTypeOrigin = "Origin"
+	OriginCreated       = "Created"
+	OriginAdopted       = "Adopted"
+)
+
+// repoOrigin returns how the repository came to be managed by the Repository,
+// or an empty string if the Repository does not manage a repository
+func repoOrigin(cr *infrav1alpha1.Repository) string {
+	if c := cr.GetCondition(ConditionTypeOrigin); c.Status == metav1.ConditionTrue {
+		return c.Reason
+	}
+	// Repositories reconciled before the origin was recorded only have the
+	// URL of the repository they created
+	if cr.Status.URL != nil {
+		return OriginCreated
+	}
+	return ""
+}
+
+// adoptRepo returns the origin of an existing repository, adopting it if it
+// is not managed by the Repository yet and adoption is allowed
+func adoptRepo(owner string, cr *infrav1alpha1.Repository) (string, error) {
+	if origin := repoOrigin(cr); origin != "" {
+		return origin, nil
+	}
+	if cr.GetAnnotations()[AdoptAnnotation] != "true" {
+		return "", fmt.Errorf("repository %s/%s already exists and is not managed by this Repository, set %s to adopt it",
+			owner, cr.GetName(), AdoptAnnotation)
+	}
+	return OriginAdopted, nil
+}
+
+func originCondition(origin string) infrav1alpha1.Condition {
+	return infrav1alpha1.Condition{Condition: metav1.Condition{
+		Type:               ConditionTypeOrigin,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             origin,
+	}}
+}
+
+// notCreatedCondition withdraws the Created origin recorded before a repo
+// creation that failed
+func notCreatedCondition() infrav1alpha1.Condition {
+	return infrav1alpha1.Condition{Condition: metav1.Condition{
+		Type:               ConditionTypeOrigin,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "NotCreated",
+	}}
+}
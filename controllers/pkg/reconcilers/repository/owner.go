@@ -0,0 +1,189 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// OwnerAnnotation sets the organization owning the repository; the
+	// repository is owned by the authenticated user if not set
+	OwnerAnnotation = "infra.nephio.org/owner"
+	// CreateOwnerAnnotation allows the organization to be created when it
+	// does not exist, if set to "true"
+	CreateOwnerAnnotation = "infra.nephio.org/create-owner"
+	// TeamsAnnotation lists the teams of the organization with access to the
+	// repository as <team>[=<permission>],...
+	TeamsAnnotation = "infra.nephio.org/teams"
+	// CollaboratorsAnnotation lists the users with access to the repository
+	// as <user>[=<permission>],...
+	CollaboratorsAnnotation = "infra.nephio.org/collaborators"
+
+	// ConditionTypeOwner reports the effective owner of the repository in
+	// its message, and whether it is a User or an Organization in its reason
+	ConditionTypeOwner = "Owner"
+
+	ownerReasonUser         = "User"
+	ownerReasonOrganization = "Organization"
+)
+
+// repoOwner returns the owner of the repository and whether the owner is an
+// organization
+func repoOwner(u *gitprovider.User, cr *infrav1alpha1.Repository) (string, bool) {
+	owner := cr.GetAnnotations()[OwnerAnnotation]
+	if owner == "" || owner == u.UserName {
+		return u.UserName, false
+	}
+	return owner, true
+}
+
+// recordedOwner returns the owner recorded in the Owner condition, the owner
+// of the repository on the git server, and whether it is an organization. The
+// owner is empty if it was not recorded yet.
+func recordedOwner(cr *infrav1alpha1.Repository) (string, bool) {
+	c := cr.GetCondition(ConditionTypeOwner)
+	if c.Status != metav1.ConditionTrue {
+		return "", false
+	}
+	return c.Message, c.Reason == ownerReasonOrganization
+}
+
+// checkOwner returns an error if the owner of the annotations is not the
+// owner of the repository managed by the Repository: the repository is not
+// moved between owners
+func checkOwner(u *gitprovider.User, cr *infrav1alpha1.Repository) error {
+	recorded, _ := recordedOwner(cr)
+	if recorded == "" || repoOrigin(cr) == "" {
+		return nil
+	}
+	if owner, _ := repoOwner(u, cr); owner != recorded {
+		return fmt.Errorf("repository %s is owned by %s, it cannot be moved to %s, restore the %s annotation",
+			cr.GetName(), recorded, owner, OwnerAnnotation)
+	}
+	return nil
+}
+
+// ensureOwner returns the owner of the repository, creating the organization
+// owning the repository if it does not exist and its creation is allowed
+func ensureOwner(ctx context.Context, giteaClient gitprovider.GitProvider, u *gitprovider.User, cr *infrav1alpha1.Repository) (string, bool, error) {
+	owner, isOrg := repoOwner(u, cr)
+	if !isOrg {
+		return owner, false, nil
+	}
+	_, err := giteaClient.GetOrg(owner)
+	if err == nil {
+		return owner, true, nil
+	}
+	if !gitprovider.IsNotFound(err) {
+		return "", false, err
+	}
+	if cr.GetAnnotations()[CreateOwnerAnnotation] != "true" {
+		return "", false, fmt.Errorf("organization %s does not exist and %s is not set", owner, CreateOwnerAnnotation)
+	}
+	if _, err := giteaClient.CreateOrg(gitprovider.CreateOrgOption{Name: owner}); err != nil {
+		return "", false, err
+	}
+	log.FromContext(ctx).Info("organization created", "name", owner)
+	return owner, true, nil
+}
+
+// grantAccess grants the teams and collaborators of the annotations access
+// to the repository. Access granted outside the annotations is left as is.
+func grantAccess(ctx context.Context, giteaClient gitprovider.GitProvider, owner string, isOrg bool, cr *infrav1alpha1.Repository) error {
+	if err := grantRepoAccess(giteaClient, owner, isOrg, cr); err != nil {
+		log.FromContext(ctx).Error(err, "cannot grant repo access")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	return nil
+}
+
+func grantRepoAccess(giteaClient gitprovider.GitProvider, owner string, isOrg bool, cr *infrav1alpha1.Repository) error {
+	teams, err := parseGrants(cr.GetAnnotations()[TeamsAnnotation])
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %s", TeamsAnnotation, err.Error())
+	}
+	if len(teams) > 0 && !isOrg {
+		return fmt.Errorf("%s requires the repository to be owned by an organization", TeamsAnnotation)
+	}
+	collaborators, err := parseGrants(cr.GetAnnotations()[CollaboratorsAnnotation])
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %s", CollaboratorsAnnotation, err.Error())
+	}
+
+	for _, team := range slices.Sorted(maps.Keys(teams)) {
+		if err := giteaClient.AddRepoTeam(owner, cr.GetName(), team, teams[team]); err != nil {
+			return fmt.Errorf("cannot grant team %s access: %s", team, err.Error())
+		}
+	}
+	for _, user := range slices.Sorted(maps.Keys(collaborators)) {
+		if err := giteaClient.AddRepoCollaborator(owner, cr.GetName(), user, collaborators[user]); err != nil {
+			return fmt.Errorf("cannot grant collaborator %s access: %s", user, err.Error())
+		}
+	}
+	return nil
+}
+
+// parseGrants parses a comma separated list of <name>[=<permission>], the
+// permission defaults to read
+func parseGrants(value string) (map[string]gitprovider.Permission, error) {
+	grants := map[string]gitprovider.Permission{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		name, perm, found := strings.Cut(s, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("missing name in %q", s)
+		}
+		permission := gitprovider.PermissionRead
+		if found {
+			var err error
+			if permission, err = gitprovider.ParsePermission(strings.TrimSpace(perm)); err != nil {
+				return nil, err
+			}
+		}
+		grants[name] = permission
+	}
+	return grants, nil
+}
+
+func ownerCondition(owner string, isOrg bool) infrav1alpha1.Condition {
+	reason := ownerReasonUser
+	if isOrg {
+		reason = ownerReasonOrganization
+	}
+	return infrav1alpha1.Condition{Condition: metav1.Condition{
+		Type:               ConditionTypeOwner,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            owner,
+	}}
+}
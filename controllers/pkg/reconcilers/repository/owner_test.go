@@ -0,0 +1,154 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseGrants(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    map[string]gitprovider.Permission
+		wantErr bool
+	}{
+		"Empty": {
+			value: "",
+			want:  map[string]gitprovider.Permission{},
+		},
+		"DefaultRead": {
+			value: "viewers, deployers=write,admins = admin",
+			want: map[string]gitprovider.Permission{
+				"viewers":   gitprovider.PermissionRead,
+				"deployers": gitprovider.PermissionWrite,
+				"admins":    gitprovider.PermissionAdmin,
+			},
+		},
+		"InvalidPermission": {
+			value:   "deployers=owner",
+			wantErr: true,
+		},
+		"MissingName": {
+			value:   "=write",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseGrants(tc.value)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUpsertOrgRepo(t *testing.T) {
+	newRepo := func(annotations map[string]string) *infrav1alpha1.Repository {
+		return &infrav1alpha1.Repository{
+			ObjectMeta: v1.ObjectMeta{Name: "edge01", Annotations: annotations},
+		}
+	}
+
+	t.Run("MissingOrg", func(t *testing.T) {
+		provider := gitprovider.NewFake("nephio")
+		cr := newRepo(map[string]string{OwnerAnnotation: "deployments"})
+		r := newTestReconciler(t, cr)
+		require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), "does not exist")
+		require.Equal(t, string(infrav1alpha1.ConditionReasonFailed), cr.GetCondition(infrav1alpha1.ConditionTypeReady).Reason)
+	})
+
+	t.Run("CreateOrg", func(t *testing.T) {
+		provider := gitprovider.NewFake("nephio")
+		cr := newRepo(map[string]string{
+			OwnerAnnotation:         "deployments",
+			CreateOwnerAnnotation:   "true",
+			CollaboratorsAnnotation: "alice=admin",
+		})
+		r := newTestReconciler(t, cr)
+		require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+		_, err := provider.GetOrg("deployments")
+		require.NoError(t, err)
+		repo, err := provider.GetRepo("deployments", "edge01")
+		require.NoError(t, err)
+		require.Equal(t, repo.CloneURL, *cr.Status.URL)
+		require.Equal(t, map[string]gitprovider.Permission{"alice": gitprovider.PermissionAdmin},
+			provider.RepoCollaborators("deployments", "edge01"))
+
+		owner := cr.GetCondition(ConditionTypeOwner)
+		require.Equal(t, "Organization", owner.Reason)
+		require.Equal(t, "deployments", owner.Message)
+
+		require.NoError(t, r.deleteRepo(context.Background(), provider, cr))
+		_, err = provider.GetRepo("deployments", "edge01")
+		require.True(t, gitprovider.IsNotFound(err))
+	})
+
+	t.Run("Teams", func(t *testing.T) {
+		provider := gitprovider.NewFake("nephio")
+		_, err := provider.CreateOrg(gitprovider.CreateOrgOption{Name: "deployments"})
+		require.NoError(t, err)
+		require.NoError(t, provider.AddTeam("deployments", "operators"))
+
+		cr := newRepo(map[string]string{
+			OwnerAnnotation: "deployments",
+			TeamsAnnotation: "operators=write",
+		})
+		r := newTestReconciler(t, cr)
+		require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+		require.Equal(t, map[string]gitprovider.Permission{"operators": gitprovider.PermissionWrite},
+			provider.RepoTeams("deployments", "edge01"))
+
+		// an unknown team fails the reconcile after the repo is updated
+		cr.Annotations[TeamsAnnotation] = "operators=write,auditors"
+		require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), "auditors")
+	})
+
+	t.Run("OwnerChanged", func(t *testing.T) {
+		provider := gitprovider.NewFake("nephio")
+		cr := newRepo(map[string]string{OwnerAnnotation: "deployments", CreateOwnerAnnotation: "true"})
+		r := newTestReconciler(t, cr)
+		require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+
+		// the repo is not moved, nor created again for the new owner
+		cr.Annotations[OwnerAnnotation] = "infra"
+		require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), OwnerAnnotation)
+		_, err := provider.GetOrg("infra")
+		require.True(t, gitprovider.IsNotFound(err))
+		require.Equal(t, "deployments", cr.GetCondition(ConditionTypeOwner).Message)
+
+		// the repo of the recorded owner is deleted
+		require.NoError(t, r.deleteRepo(context.Background(), provider, cr))
+		_, err = provider.GetRepo("deployments", "edge01")
+		require.True(t, gitprovider.IsNotFound(err))
+	})
+
+	t.Run("TeamsRequireOrg", func(t *testing.T) {
+		provider := gitprovider.NewFake("nephio")
+		cr := newRepo(map[string]string{TeamsAnnotation: "operators"})
+		r := newTestReconciler(t, cr)
+		require.Error(t, r.upsertRepo(context.Background(), provider, cr))
+		require.Equal(t, "User", cr.GetCondition(ConditionTypeOwner).Reason)
+	})
+}
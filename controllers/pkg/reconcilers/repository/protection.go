@@ -0,0 +1,221 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// BranchProtectionAnnotation holds the protected branches of the
+	// repository as a JSON list of branchProtection
+	BranchProtectionAnnotation = "infra.nephio.org/branch-protection"
+	// WebhooksAnnotation holds the webhooks of the repository as a JSON list
+	// of webhook
+	WebhooksAnnotation = "infra.nephio.org/webhooks"
+	// webhookSecretKey is the key of the webhook secret in the Secret
+	// referenced by a webhook
+	webhookSecretKey = "secret"
+	// driftResyncPeriod is the period the branch protections and webhooks are
+	// checked for changes made on the git server
+	driftResyncPeriod = 5 * time.Minute
+)
+
+type branchProtection struct {
+	Branch            string   `json:"branch"`
+	RequiredApprovals int64    `json:"requiredApprovals,omitempty"`
+	StatusChecks      []string `json:"statusChecks,omitempty"`
+	// AllowForcePush allows force pushes to the branch, they are blocked by
+	// default
+	AllowForcePush bool `json:"allowForcePush,omitempty"`
+}
+
+type webhook struct {
+	URL string `json:"url"`
+	// Events defaults to push
+	Events []string `json:"events,omitempty"`
+	// ContentType defaults to json
+	ContentType string `json:"contentType,omitempty"`
+	// SecretRef is the name of a Secret in the namespace of the Repository
+	// holding the secret signing the payloads
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// applyRepoSettings grants access to the repository and enforces its branch
+// protections and webhooks
+func (r *reconciler) applyRepoSettings(ctx context.Context, giteaClient gitprovider.GitProvider, owner string, isOrg bool, cr *infrav1alpha1.Repository) error {
+	if err := grantAccess(ctx, giteaClient, owner, isOrg, cr); err != nil {
+		return err
+	}
+	if err := protectBranches(ctx, giteaClient, owner, cr); err != nil {
+		log.FromContext(ctx).Error(err, "cannot protect branches")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	if err := r.reconcileHooks(ctx, giteaClient, owner, cr); err != nil {
+		log.FromContext(ctx).Error(err, "cannot reconcile webhooks")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	return nil
+}
+
+// protectBranches protects the branches of the annotation, correcting any
+// protection changed on the git server. Protections of other branches are
+// left as is.
+func protectBranches(ctx context.Context, giteaClient gitprovider.GitProvider, owner string, cr *infrav1alpha1.Repository) error {
+	var protections []branchProtection
+	if err := parseAnnotation(cr, BranchProtectionAnnotation, &protections); err != nil {
+		return err
+	}
+	for _, p := range protections {
+		if p.Branch == "" {
+			return fmt.Errorf("invalid %s annotation: missing branch", BranchProtectionAnnotation)
+		}
+		want := gitprovider.BranchProtection{
+			Branch:            p.Branch,
+			RequiredApprovals: p.RequiredApprovals,
+			StatusChecks:      p.StatusChecks,
+			BlockForcePush:    !p.AllowForcePush,
+		}
+		got, err := giteaClient.GetBranchProtection(owner, cr.GetName(), p.Branch)
+		if err != nil && !gitprovider.IsNotFound(err) {
+			return err
+		}
+		if err == nil && equalProtection(*got, want) {
+			continue
+		}
+		if err := giteaClient.SetBranchProtection(owner, cr.GetName(), want); err != nil {
+			return fmt.Errorf("cannot protect branch %s: %s", p.Branch, err.Error())
+		}
+		log.FromContext(ctx).Info("branch protection updated", "branch", p.Branch, "drift", err == nil)
+	}
+	return nil
+}
+
+// reconcileHooks creates the webhooks of the annotation, identified by their
+// URL, and corrects any webhook changed on the git server. The git servers
+// do not disclose hook secrets, so hooks with a secret are always updated.
+// Other webhooks are left as is.
+func (r *reconciler) reconcileHooks(ctx context.Context, giteaClient gitprovider.GitProvider, owner string, cr *infrav1alpha1.Repository) error {
+	var webhooks []webhook
+	if err := parseAnnotation(cr, WebhooksAnnotation, &webhooks); err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	existing, err := giteaClient.ListRepoHooks(owner, cr.GetName())
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		want, err := r.desiredHook(ctx, cr, w)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(existing, func(h *gitprovider.Hook) bool { return h.URL == w.URL })
+		if i < 0 {
+			if _, err := giteaClient.CreateRepoHook(owner, cr.GetName(), want); err != nil {
+				return fmt.Errorf("cannot create webhook %s: %s", w.URL, err.Error())
+			}
+			log.FromContext(ctx).Info("webhook created", "url", w.URL)
+			continue
+		}
+		if want.Secret == "" && equalHook(*existing[i], want) {
+			continue
+		}
+		want.ID = existing[i].ID
+		if err := giteaClient.EditRepoHook(owner, cr.GetName(), want); err != nil {
+			return fmt.Errorf("cannot update webhook %s: %s", w.URL, err.Error())
+		}
+		log.FromContext(ctx).Info("webhook updated", "url", w.URL)
+	}
+	return nil
+}
+
+func (r *reconciler) desiredHook(ctx context.Context, cr *infrav1alpha1.Repository, w webhook) (gitprovider.Hook, error) {
+	if w.URL == "" {
+		return gitprovider.Hook{}, fmt.Errorf("invalid %s annotation: missing url", WebhooksAnnotation)
+	}
+	hook := gitprovider.Hook{
+		URL:         w.URL,
+		ContentType: w.ContentType,
+		Events:      slices.Sorted(slices.Values(w.Events)),
+		Active:      true,
+	}
+	if hook.ContentType == "" {
+		hook.ContentType = "json"
+	}
+	if len(hook.Events) == 0 {
+		hook.Events = []string{gitprovider.HookEventPush}
+	}
+	if w.SecretRef != "" {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: w.SecretRef}, secret); err != nil {
+			return gitprovider.Hook{}, fmt.Errorf("cannot get secret of webhook %s: %s", w.URL, err.Error())
+		}
+		hook.Secret = string(secret.Data[webhookSecretKey])
+		if hook.Secret == "" {
+			return gitprovider.Hook{}, fmt.Errorf("secret %s has no %s key", w.SecretRef, webhookSecretKey)
+		}
+	}
+	return hook, nil
+}
+
+// resyncPeriod returns the period the repository is reconciled to correct
+// drift, or 0 if it has no branch protections nor webhooks
+func resyncPeriod(cr *infrav1alpha1.Repository) time.Duration {
+	annotations := cr.GetAnnotations()
+	if annotations[BranchProtectionAnnotation] != "" || annotations[WebhooksAnnotation] != "" {
+		return driftResyncPeriod
+	}
+	return 0
+}
+
+func parseAnnotation(cr *infrav1alpha1.Repository, key string, v any) error {
+	value := cr.GetAnnotations()[key]
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return fmt.Errorf("invalid %s annotation: %s", key, err.Error())
+	}
+	return nil
+}
+
+func equalProtection(a, b gitprovider.BranchProtection) bool {
+	return a.RequiredApprovals == b.RequiredApprovals &&
+		a.BlockForcePush == b.BlockForcePush &&
+		slices.Equal(slices.Sorted(slices.Values(a.StatusChecks)), slices.Sorted(slices.Values(b.StatusChecks)))
+}
+
+func equalHook(a, b gitprovider.Hook) bool {
+	return a.ContentType == b.ContentType &&
+		a.Active == b.Active &&
+		slices.Equal(slices.Sorted(slices.Values(a.Events)), slices.Sorted(slices.Values(b.Events)))
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpsertRepoProtection(t *testing.T) {
+	provider := gitprovider.NewFake("nephio")
+	cr := &infrav1alpha1.Repository{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "mgmt",
+			Annotations: map[string]string{
+				BranchProtectionAnnotation: `[{"branch": "main", "requiredApprovals": 1, "statusChecks": ["ci"]}]`,
+			},
+		},
+	}
+	r := newTestReconciler(t, cr)
+
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	want := &gitprovider.BranchProtection{
+		Branch:            "main",
+		RequiredApprovals: 1,
+		StatusChecks:      []string{"ci"},
+		BlockForcePush:    true,
+	}
+	got, err := provider.GetBranchProtection("nephio", "mgmt", "main")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Equal(t, driftResyncPeriod, resyncPeriod(cr))
+
+	// a protection changed on the git server is restored
+	require.NoError(t, provider.SetBranchProtection("nephio", "mgmt", gitprovider.BranchProtection{Branch: "main"}))
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	got, err = provider.GetBranchProtection("nephio", "mgmt", "main")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	cr.Annotations[BranchProtectionAnnotation] = `{"branch": "main"}`
+	require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), BranchProtectionAnnotation)
+	require.Equal(t, string(infrav1alpha1.ConditionReasonFailed), cr.GetCondition(infrav1alpha1.ConditionTypeReady).Reason)
+}
+
+func TestUpsertRepoWebhooks(t *testing.T) {
+	const porchURL = "http://porch-server.porch-system.svc/webhook"
+	const configSyncURL = "http://config-sync.svc/webhook"
+
+	provider := gitprovider.NewFake("nephio")
+	cr := &infrav1alpha1.Repository{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "mgmt",
+			Namespace: "default",
+			Annotations: map[string]string{
+				WebhooksAnnotation: `[{"url": "` + porchURL + `"},
+					{"url": "` + configSyncURL + `", "events": ["push", "create"], "secretRef": "config-sync-webhook"}]`,
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "config-sync-webhook", Namespace: "default"},
+		Data:       map[string][]byte{webhookSecretKey: []byte("s3cr3t")},
+	}
+	r := newTestReconciler(t, cr, secret)
+
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	hooks, err := provider.ListRepoHooks("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	require.Equal(t, &gitprovider.Hook{
+		ID:          hooks[0].ID,
+		URL:         porchURL,
+		ContentType: "json",
+		Events:      []string{gitprovider.HookEventPush},
+		Active:      true,
+	}, hooks[0])
+	require.Equal(t, []string{gitprovider.HookEventCreate, gitprovider.HookEventPush}, hooks[1].Events)
+	require.Equal(t, "s3cr3t", provider.RepoHookSecret("nephio", "mgmt", hooks[1].ID))
+
+	// hooks changed on the git server are restored, other hooks are kept
+	require.NoError(t, provider.EditRepoHook("nephio", "mgmt", gitprovider.Hook{ID: hooks[0].ID, URL: porchURL}))
+	_, err = provider.CreateRepoHook("nephio", "mgmt", gitprovider.Hook{URL: "http://other", Active: true})
+	require.NoError(t, err)
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	got, err := provider.ListRepoHooks("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.Equal(t, hooks[0], got[0])
+
+	// a missing secret fails the reconcile
+	cr.Annotations[WebhooksAnnotation] = `[{"url": "` + porchURL + `", "secretRef": "missing"}]`
+	require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), "missing")
+}
@@ -21,19 +21,24 @@ import (
 	"fmt"
 	"reflect"
 
-	"code.gitea.io/sdk/gitea"
 	commonv1alpha1 "github.com/nephio-project/api/common/v1alpha1"
 	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	gitv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/git/v1alpha1"
 	"github.com/nephio-project/nephio/controllers/pkg/giteaclient"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 	ctrlconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
 	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 func init() {
@@ -47,17 +52,16 @@ const (
 
 //+kubebuilder:rbac:groups=infra.nephio.org,resources=repositories,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infra.nephio.org,resources=repositories/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=git.nephio.org,resources=gitservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups="*",resources=secrets,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c interface{}) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
 	cfg, ok := c.(*ctrlconfig.ControllerConfig)
 	// Sending the porchclient to gitea, this will be used to get
-	// the secret objects for gitea client authentication. The client
-	// of the manager of this controller cannot be used at this point.
-	// Should this be conditional ? Only if we have repo/token reconciler
+	// the secret objects for gitea client authentication.
 
-	var e error
-	r.giteaClient, e = giteaclient.GetClient(ctx, resource.NewAPIPatchingApplicator(cfg.PorchClient))
+	defaultClient, e := giteaclient.GetClient(ctx, resource.NewAPIPatchingApplicator(cfg.PorchClient))
 	if e != nil {
 		return nil, e
 	}
@@ -69,20 +73,38 @@ func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c i
 	if err := infrav1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return nil, err
 	}
+	if err := gitv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+	if err := defaultClient.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
 
 	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
 	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
+	r.gitClients = giteaclient.NewRegistry(mgr.GetClient(), defaultClient)
+	// reconcile the Repositories of a GitServer with its rebuilt client
+	// when the GitServer or its credentials change
+	newList := func() client.ObjectList { return &infrav1alpha1.RepositoryList{} }
+	gitServerRequests := giteaclient.EnqueueSelecting(mgr.GetClient(), newList)
 
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("RepositoryController").
 		For(&infrav1alpha1.Repository{}).
+		Watches(&gitv1alpha1.GitServer{}, handler.EnqueueRequestsFromMapFunc(gitServerRequests)).
+		Watches(&gitv1alpha1.GitServer{}, r.gitClients.EvictDeleted()).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(gitServerRequests)).
+		// reconcile the objects waiting for the default client when it
+		// becomes ready
+		WatchesRawSource(source.Channel(defaultClient.Subscribe(),
+			handler.EnqueueRequestsFromMapFunc(giteaclient.EnqueueDefault(mgr.GetClient(), newList)))).
 		Complete(r)
 }
 
 type reconciler struct {
 	resource.APIPatchingApplicator
-	giteaClient giteaclient.GiteaClient
-	finalizer   *resource.APIFinalizer
+	gitClients *giteaclient.Registry
+	finalizer  *resource.APIFinalizer
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -99,24 +121,37 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
-	// check if client exists otherwise retry
-	if !r.giteaClient.IsInitialized() {
-		err := fmt.Errorf("gitea server unreachable")
-		log.Error(err, "cannot connect to git server")
+	// get the client of the git server of the CR and check it exists
+	// otherwise retry
+	giteaClient, err := r.gitClients.ClientFor(ctx, cr)
+	if err != nil {
+		log.Error(err, "cannot get git client")
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
+	if !giteaClient.IsInitialized() {
+		// the CR is reconciled again when the client becomes ready
+		err := fmt.Errorf("git server unreachable")
+		log.Error(err, "cannot connect to git server")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
 
 	if resource.WasDeleted(cr) {
-		// TODO DELETION POLICY: "orphan" deletion policy
 		// repo being deleted
-		// Delete the repo from the git server
+		// Delete the repo from the git server unless it is orphaned, a repo
+		// the Repository does not manage is never deleted
 		// when successful remove the finalizer
-		if cr.Spec.Lifecycle.DeletionPolicy == commonv1alpha1.DeletionDelete {
-			if err := r.deleteRepo(ctx, r.giteaClient, cr); err != nil {
+		switch {
+		case repoOrigin(cr) == "":
+			log.Info("repo not managed, nothing to delete", "name", cr.GetName())
+		case cr.Spec.Lifecycle.DeletionPolicy == commonv1alpha1.DeletionDelete:
+			if err := r.deleteRepo(ctx, giteaClient, cr); err != nil {
 				log.Error(err, "cannot delete repo in git server")
 				return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 			}
+		default:
+			log.Info("repo orphaned", "name", cr.GetName())
 		}
 
 		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
@@ -137,26 +172,49 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// upsert repo in git server
-	if err := r.upsertRepo(ctx, r.giteaClient, cr); err != nil {
+	if err := r.upsertRepo(ctx, giteaClient, cr); err != nil {
 		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 	cr.SetConditions(infrav1alpha1.Ready())
-	return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	// requeue to correct changes of the branch protections and webhooks made
+	// on the git server
+	return ctrl.Result{RequeueAfter: resyncPeriod(cr)}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 }
 
-func (r *reconciler) upsertRepo(ctx context.Context, giteaClient giteaclient.GiteaClient, cr *infrav1alpha1.Repository) error {
+func (r *reconciler) upsertRepo(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Repository) error {
 	log := log.FromContext(ctx)
-	u, _, err := giteaClient.GetMyUserInfo()
+	u, err := giteaClient.GetMyUserInfo()
 	if err != nil {
 		log.Error(err, "cannot get user info")
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 		return err
 	}
 
-	_, _, err = giteaClient.GetRepo(u.UserName, cr.GetName())
+	if err := checkOwner(u, cr); err != nil {
+		log.Error(err, "cannot change repo owner")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	owner, isOrg, err := ensureOwner(ctx, giteaClient, u, cr)
+	if err != nil {
+		log.Error(err, "cannot get repo owner")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	cr.SetConditions(ownerCondition(owner, isOrg))
+
+	_, err = giteaClient.GetRepo(owner, cr.GetName())
+	if err != nil && !gitprovider.IsNotFound(err) {
+		log.Error(err, "cannot get repo")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
 	if err != nil {
 		// create repo
-		createRepo := gitea.CreateRepoOption{Name: cr.GetName()}
+		createRepo := gitprovider.CreateRepoOption{Name: cr.GetName()}
+		if isOrg {
+			createRepo.Owner = owner
+		}
 		if cr.Spec.Description != nil {
 			createRepo.Description = *cr.Spec.Description
 		}
@@ -179,24 +237,46 @@ func (r *reconciler) upsertRepo(ctx context.Context, giteaClient giteaclient.Git
 			createRepo.DefaultBranch = *cr.Spec.DefaultBranch
 		}
 		if cr.Spec.TrustModel != nil {
-			createRepo.TrustModel = gitea.TrustModel(*cr.Spec.TrustModel)
+			createRepo.TrustModel = string(*cr.Spec.TrustModel)
 		}
 		createRepo.AutoInit = true
 		log.Info("repository", "config", createRepo)
 
-		repo, _, err := giteaClient.CreateRepo(createRepo)
+		// the origin is recorded before the repo is created, so a repo
+		// created by a reconcile whose final status update is lost is not
+		// taken for a repo this Repository does not manage
+		cr.SetConditions(originCondition(OriginCreated))
+		if err := r.Status().Update(ctx, cr); err != nil {
+			log.Error(err, "cannot record repo origin")
+			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+			return err
+		}
+		repo, err := giteaClient.CreateRepo(createRepo)
 		if err != nil {
 			log.Error(err, "cannot create repo")
+			cr.SetConditions(notCreatedCondition())
 			// Here we don't provide the full error since the message change every time and this will re-trigger
 			// a new reconcile loop
 			cr.SetConditions(infrav1alpha1.Failed("cannot create repo"))
 			return err
 		}
-		log.Info("repo created", "name", cr.GetName())
+		log.Info("repo created", "name", cr.GetName(), "owner", owner)
 		cr.Status.URL = &repo.CloneURL
-		return nil
+		return r.applyRepoSettings(ctx, giteaClient, owner, isOrg, cr)
+	}
+	// never take over a repo with the same name unless asked to
+	origin, err := adoptRepo(owner, cr)
+	if err != nil {
+		log.Error(err, "cannot manage repo")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
 	}
-	editRepo := gitea.EditRepoOption{Name: ptr.To(cr.GetName())}
+	if repoOrigin(cr) == "" {
+		log.Info("repo adopted", "name", cr.GetName(), "owner", owner)
+	}
+	cr.SetConditions(originCondition(origin))
+
+	editRepo := gitprovider.EditRepoOption{Name: ptr.To(cr.GetName())}
 	if cr.Spec.Description != nil {
 		editRepo.Description = cr.Spec.Description
 	} else {
@@ -207,7 +287,7 @@ func (r *reconciler) upsertRepo(ctx context.Context, giteaClient giteaclient.Git
 	} else {
 		editRepo.Private = nil
 	}
-	repo, _, err := giteaClient.EditRepo(u.UserName, cr.GetName(), editRepo)
+	repo, err := giteaClient.EditRepo(owner, cr.GetName(), editRepo)
 	if err != nil {
 		log.Error(err, "cannot update repo")
 		// Here we don't provide the full error since the message change every time and this will re-trigger
@@ -215,27 +295,35 @@ func (r *reconciler) upsertRepo(ctx context.Context, giteaClient giteaclient.Git
 		cr.SetConditions(infrav1alpha1.Failed("cannot update repo"))
 		return err
 	}
-	log.Info("repo updated", "name", cr.GetName())
+	log.Info("repo updated", "name", cr.GetName(), "owner", owner)
 	cr.Status.URL = &repo.CloneURL
 
-	return nil
+	return r.applyRepoSettings(ctx, giteaClient, owner, isOrg, cr)
 }
 
-func (r *reconciler) deleteRepo(ctx context.Context, giteaClient giteaclient.GiteaClient, cr *infrav1alpha1.Repository) error {
+// deleteRepo deletes the repository of the owner it was created or adopted
+// with, whatever the owner annotation says now
+func (r *reconciler) deleteRepo(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Repository) error {
 	log := log.FromContext(ctx)
-	u, _, err := giteaClient.GetMyUserInfo()
-	if err != nil {
-		log.Error(err, "cannot get user info")
-		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
-		return err
+	owner, _ := recordedOwner(cr)
+	if owner == "" {
+		// Repositories reconciled before the owner was recorded are owned
+		// by the user
+		u, err := giteaClient.GetMyUserInfo()
+		if err != nil {
+			log.Error(err, "cannot get user info")
+			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+			return err
+		}
+		owner = u.UserName
 	}
 
-	_, err = giteaClient.DeleteRepo(u.UserName, cr.GetName())
+	err := giteaClient.DeleteRepo(owner, cr.GetName())
 	if err != nil {
 		log.Error(err, "cannot delete repo")
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 		return err
 	}
-	log.Info("repo deleted", "name", cr.GetName())
+	log.Info("repo deleted", "name", cr.GetName(), "owner", owner)
 	return nil
 }
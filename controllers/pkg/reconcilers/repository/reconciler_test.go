@@ -21,12 +21,17 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/nephio-project/nephio/controllers/pkg/giteaclient"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	"github.com/nephio-project/nephio/testing/mockeryutils"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	"code.gitea.io/sdk/gitea"
 	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
 )
 
@@ -49,10 +54,23 @@ type repoTest struct {
 	wantErr bool
 }
 
+// newTestReconciler returns a reconciler whose client holds the objects, the
+// Repositories whose status is updated must be among them
+func newTestReconciler(t *testing.T, objs ...client.Object) *reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, infrav1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&infrav1alpha1.Repository{}).
+		WithObjects(objs...).Build()
+	return &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+}
+
 func TestUpsertRepo(t *testing.T) {
 	dummyString := "Dummy String"
 	dummyBool := true
 	dummyTrustModel := infrav1alpha1.TrustModel("Trust Model")
+	managed := infrav1alpha1.RepositoryStatus{ConditionedStatus: *infrav1alpha1.NewConditionedStatus(originCondition(OriginCreated))}
 
 	tests := []repoTest{
 		{
@@ -60,18 +78,18 @@ func TestUpsertRepo(t *testing.T) {
 			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil, log.FromContext(context.Background())},
 			args:   args{nil, nil, &infrav1alpha1.Repository{}},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{nil, nil, fmt.Errorf("error getting User Information")}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{nil, fmt.Errorf("error getting User Information")}},
 			},
 			wantErr: true,
 		},
 		{
 			name:   "Repo exists, cr spec fields blank",
 			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil, log.FromContext(context.Background())},
-			args:   args{nil, nil, &infrav1alpha1.Repository{Status: infrav1alpha1.RepositoryStatus{}}},
+			args:   args{nil, nil, &infrav1alpha1.Repository{Status: managed}},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
-				{MethodName: "EditRepo", ArgType: []string{"string", "string", "gitea.EditRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
+				{MethodName: "EditRepo", ArgType: []string{"string", "string", "gitprovider.EditRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
 			},
 			wantErr: false,
 		},
@@ -86,13 +104,13 @@ func TestUpsertRepo(t *testing.T) {
 						Description: &dummyString,
 						Private:     &dummyBool,
 					},
-					Status: infrav1alpha1.RepositoryStatus{},
+					Status: managed,
 				},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
-				{MethodName: "EditRepo", ArgType: []string{"string", "string", "gitea.EditRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
+				{MethodName: "EditRepo", ArgType: []string{"string", "string", "gitprovider.EditRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
 			},
 			wantErr: false,
 		},
@@ -102,13 +120,48 @@ func TestUpsertRepo(t *testing.T) {
 			args: args{
 				nil,
 				nil,
-				&infrav1alpha1.Repository{},
+				&infrav1alpha1.Repository{Status: managed},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
 				{MethodName: "EditRepo", ArgType: []string{"string", "string",
-					"gitea.EditRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, fmt.Errorf("error updating repo")}},
+					"gitprovider.EditRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, fmt.Errorf("error updating repo")}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Repo exists, not managed",
+			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil, log.FromContext(context.Background())},
+			args:   args{nil, nil, &infrav1alpha1.Repository{}},
+			mocks: []mockeryutils.MockHelper{
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Repo exists, adopted",
+			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil, log.FromContext(context.Background())},
+			args: args{
+				nil,
+				nil,
+				&infrav1alpha1.Repository{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{AdoptAnnotation: "true"}}},
+			},
+			mocks: []mockeryutils.MockHelper{
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
+				{MethodName: "EditRepo", ArgType: []string{"string", "string", "gitprovider.EditRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Get repo fails",
+			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil, log.FromContext(context.Background())},
+			args:   args{nil, nil, &infrav1alpha1.Repository{}},
+			mocks: []mockeryutils.MockHelper{
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{nil, fmt.Errorf("server unavailable")}},
 			},
 			wantErr: true,
 		},
@@ -132,9 +185,9 @@ func TestUpsertRepo(t *testing.T) {
 				},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, fmt.Errorf("repo does not exist")}},
-				{MethodName: "CreateRepo", ArgType: []string{"gitea.CreateRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, fmt.Errorf("%w: repo", gitprovider.ErrNotFound)}},
+				{MethodName: "CreateRepo", ArgType: []string{"gitprovider.CreateRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
 			},
 			wantErr: false,
 		},
@@ -147,9 +200,9 @@ func TestUpsertRepo(t *testing.T) {
 				&infrav1alpha1.Repository{},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, fmt.Errorf("repo does not exist")}},
-				{MethodName: "CreateRepo", ArgType: []string{"gitea.CreateRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, fmt.Errorf("%w: repo", gitprovider.ErrNotFound)}},
+				{MethodName: "CreateRepo", ArgType: []string{"gitprovider.CreateRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, nil}},
 			},
 			wantErr: false,
 		},
@@ -162,19 +215,17 @@ func TestUpsertRepo(t *testing.T) {
 				&infrav1alpha1.Repository{},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Repository{}, nil, fmt.Errorf("repo does not exist")}},
-				{MethodName: "CreateRepo", ArgType: []string{"gitea.CreateRepoOption"}, RetArgList: []interface{}{&gitea.Repository{}, nil, fmt.Errorf("repo creation fails")}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitprovider.Repository{}, fmt.Errorf("%w: repo", gitprovider.ErrNotFound)}},
+				{MethodName: "CreateRepo", ArgType: []string{"gitprovider.CreateRepoOption"}, RetArgList: []interface{}{&gitprovider.Repository{}, fmt.Errorf("repo creation fails")}},
 			},
 			wantErr: true,
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := &reconciler{
-				APIPatchingApplicator: tt.fields.APIPatchingApplicator,
-				giteaClient:           tt.fields.giteaClient,
-				finalizer:             tt.fields.finalizer,
-			}
+			tt.args.cr.Name = "repo-name"
+			r := newTestReconciler(t, tt.args.cr)
+			r.gitClients = giteaclient.NewRegistry(nil, tt.fields.giteaClient)
 
 			initMockeryMocks(&tt)
 
@@ -200,8 +251,8 @@ func TestDeleteRepo(t *testing.T) {
 				},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "DeleteRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Response{}, nil, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "DeleteRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{nil}},
 			},
 			wantErr: false,
 		}, {
@@ -217,7 +268,7 @@ func TestDeleteRepo(t *testing.T) {
 				},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, fmt.Errorf("Error getting User Information")}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{nil, fmt.Errorf("Error getting User Information")}},
 			},
 			wantErr: true,
 		}, {
@@ -233,8 +284,8 @@ func TestDeleteRepo(t *testing.T) {
 				},
 			},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
-				{MethodName: "DeleteRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{&gitea.Response{}, fmt.Errorf("Error deleting repo")}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+				{MethodName: "DeleteRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{fmt.Errorf("Error deleting repo")}},
 			},
 			wantErr: true,
 		}}
@@ -242,7 +293,7 @@ func TestDeleteRepo(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &reconciler{
 				APIPatchingApplicator: tt.fields.APIPatchingApplicator,
-				giteaClient:           tt.fields.giteaClient,
+				gitClients:            giteaclient.NewRegistry(nil, tt.fields.giteaClient),
 				finalizer:             tt.fields.finalizer,
 			}
 
@@ -255,9 +306,84 @@ func TestDeleteRepo(t *testing.T) {
 	}
 }
 
+func TestRepoLifecycleWithFakeProvider(t *testing.T) {
+	description := "deployment repo"
+	cr := &infrav1alpha1.Repository{
+		ObjectMeta: v1.ObjectMeta{Name: "repo-name"},
+		Spec:       infrav1alpha1.RepositorySpec{Description: &description},
+	}
+	provider := gitprovider.NewFake("nephio")
+	r := newTestReconciler(t, cr)
+
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	require.NotNil(t, cr.Status.URL)
+	require.Equal(t, "http://git.fake/nephio/repo-name.git", *cr.Status.URL)
+
+	private := true
+	cr.Spec.Private = &private
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	repo, err := provider.GetRepo("nephio", "repo-name")
+	require.NoError(t, err)
+	require.True(t, repo.Private)
+	require.Equal(t, description, repo.Description)
+
+	require.NoError(t, r.deleteRepo(context.Background(), provider, cr))
+	_, err = provider.GetRepo("nephio", "repo-name")
+	require.True(t, gitprovider.IsNotFound(err))
+	require.Error(t, r.deleteRepo(context.Background(), provider, cr))
+}
+
 func initMockeryMocks(tt *repoTest) {
 	mockGClient := new(giteaclient.MockGiteaClient)
 	tt.args.giteaClient = mockGClient
 	tt.fields.giteaClient = mockGClient
 	mockeryutils.InitMocks(&mockGClient.Mock, tt.mocks)
 }
+
+func TestRepoOrigin(t *testing.T) {
+	url := "http://git.fake/nephio/repo-name.git"
+	provider := gitprovider.NewFake("nephio")
+	_, err := provider.CreateRepo(gitprovider.CreateRepoOption{Name: "existing", Description: "keep"})
+	require.NoError(t, err)
+	created := &infrav1alpha1.Repository{ObjectMeta: v1.ObjectMeta{Name: "created"}}
+	existing := &infrav1alpha1.Repository{ObjectMeta: v1.ObjectMeta{Name: "existing"}}
+	r := newTestReconciler(t, created, existing)
+
+	// a Repository reconciled before the origin was recorded has a URL
+	require.Equal(t, OriginCreated, repoOrigin(&infrav1alpha1.Repository{Status: infrav1alpha1.RepositoryStatus{URL: &url}}))
+
+	cr := created
+	require.Equal(t, "", repoOrigin(cr))
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	require.Equal(t, OriginCreated, repoOrigin(cr))
+	// the origin is stored before the repo is created
+	stored := &infrav1alpha1.Repository{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(cr), stored))
+	require.Equal(t, OriginCreated, repoOrigin(stored))
+
+	cr = existing
+	require.ErrorContains(t, r.upsertRepo(context.Background(), provider, cr), AdoptAnnotation)
+	require.Equal(t, "", repoOrigin(cr))
+
+	cr.Annotations = map[string]string{AdoptAnnotation: "true"}
+	require.NoError(t, r.upsertRepo(context.Background(), provider, cr))
+	require.Equal(t, OriginAdopted, repoOrigin(cr))
+	repo, err := provider.GetRepo("nephio", "existing")
+	require.NoError(t, err)
+	require.Equal(t, "keep", repo.Description)
+	require.Equal(t, repo.CloneURL, *cr.Status.URL)
+}
+
+func TestRepoOriginCreateFails(t *testing.T) {
+	cr := &infrav1alpha1.Repository{ObjectMeta: v1.ObjectMeta{Name: "repo-name"}}
+	mockGClient := new(giteaclient.MockGiteaClient)
+	mockeryutils.InitMocks(&mockGClient.Mock, []mockeryutils.MockHelper{
+		{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
+		{MethodName: "GetRepo", ArgType: []string{"string", "string"}, RetArgList: []interface{}{nil, fmt.Errorf("%w: repo", gitprovider.ErrNotFound)}},
+		{MethodName: "CreateRepo", ArgType: []string{"gitprovider.CreateRepoOption"}, RetArgList: []interface{}{nil, fmt.Errorf("repo creation fails")}},
+	})
+	r := newTestReconciler(t, cr)
+
+	require.Error(t, r.upsertRepo(context.Background(), mockGClient, cr))
+	require.Equal(t, "", repoOrigin(cr))
+}
@@ -0,0 +1,144 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	rootSyncListGVK = schema.GroupVersionKind{Group: "configsync.gke.io", Version: "v1beta1", Kind: "RootSyncList"}
+	repoSyncListGVK = schema.GroupVersionKind{Group: "configsync.gke.io", Version: "v1beta1", Kind: "RepoSyncList"}
+)
+
+// healthCheck references a resource on the workload cluster that must exist
+// and be Ready or Available
+type healthCheck struct {
+	gvk schema.GroupVersionKind
+	key types.NamespacedName
+}
+
+// parseHealthChecks parses a comma separated list of health checks of the
+// form <apiVersion>/<kind>/<namespace>/<name>, e.g. apps/v1/Deployment/free5gc/upf.
+func parseHealthChecks(value string) ([]healthCheck, error) {
+	var checks []healthCheck
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		parts := strings.Split(s, "/")
+		if len(parts) < 4 || len(parts) > 5 {
+			return nil, fmt.Errorf("invalid health check %q; expected <apiVersion>/<kind>/<namespace>/<name>", s)
+		}
+		n := len(parts)
+		gv, err := schema.ParseGroupVersion(strings.Join(parts[:n-3], "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid health check %q: %s", s, err.Error())
+		}
+		checks = append(checks, healthCheck{
+			gvk: gv.WithKind(parts[n-3]),
+			key: types.NamespacedName{Namespace: parts[n-2], Name: parts[n-1]},
+		})
+	}
+	return checks, nil
+}
+
+// checkClusterHealth reports the cluster as unhealthy if any config-sync
+// RootSync or RepoSync reports errors, or if any of the health checks fail.
+// The reason explains why the cluster is unhealthy.
+func checkClusterHealth(ctx context.Context, c client.Reader, checks []healthCheck) (bool, string, error) {
+	for _, gvk := range []schema.GroupVersionKind{rootSyncListGVK, repoSyncListGVK} {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := c.List(ctx, l); err != nil {
+			if meta.IsNoMatchError(err) {
+				// config-sync is not installed on the cluster
+				continue
+			}
+			return false, "", err
+		}
+		for _, item := range l.Items {
+			if reason, ok := syncHealthy(&item); !ok {
+				return false, fmt.Sprintf("%s %s/%s %s", item.GetKind(), item.GetNamespace(), item.GetName(), reason), nil
+			}
+		}
+	}
+
+	for _, check := range checks {
+		gvk := check.gvk
+		u := resource.GetUnstructuredFromGVK(&gvk)
+		if err := c.Get(ctx, check.key, u); err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				return false, "", err
+			}
+			return false, fmt.Sprintf("%s %s not found", gvk.Kind, check.key), nil
+		}
+		if reason, ok := resourceHealthy(u); !ok {
+			return false, fmt.Sprintf("%s %s %s", gvk.Kind, check.key, reason), nil
+		}
+	}
+	return true, "", nil
+}
+
+// syncHealthy checks a RootSync or RepoSync for errors in fetching, rendering
+// or syncing the source, and for the Stalled condition
+func syncHealthy(u *unstructured.Unstructured) (string, bool) {
+	for _, field := range []string{"source", "rendering", "sync"} {
+		count, _, _ := unstructured.NestedInt64(u.Object, "status", field, "errorSummary", "totalCount")
+		if count > 0 {
+			return fmt.Sprintf("has %d %s error(s)", count, field), false
+		}
+	}
+	if status, ok := conditionStatus(u, "Stalled"); ok && status == "True" {
+		return "is stalled", false
+	}
+	return "", true
+}
+
+// resourceHealthy checks the Ready and Available conditions of a resource; a
+// resource without either condition is healthy
+func resourceHealthy(u *unstructured.Unstructured) (string, bool) {
+	for _, condType := range []string{"Ready", "Available"} {
+		if status, ok := conditionStatus(u, condType); ok && status != "True" {
+			return fmt.Sprintf("is not %s", condType), false
+		}
+	}
+	return "", true
+}
+
+func conditionStatus(u *unstructured.Unstructured, condType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != condType {
+			continue
+		}
+		status, _ := cond["status"].(string)
+		return status, true
+	}
+	return "", false
+}
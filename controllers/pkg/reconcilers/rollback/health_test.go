@@ -0,0 +1,117 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseHealthChecks(t *testing.T) {
+	checks, err := parseHealthChecks("apps/v1/Deployment/free5gc/upf, v1/Service/free5gc/nrf")
+	require.NoError(t, err)
+	require.Equal(t, []healthCheck{
+		{
+			gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			key: types.NamespacedName{Namespace: "free5gc", Name: "upf"},
+		},
+		{
+			gvk: schema.GroupVersionKind{Version: "v1", Kind: "Service"},
+			key: types.NamespacedName{Namespace: "free5gc", Name: "nrf"},
+		},
+	}, checks)
+
+	checks, err = parseHealthChecks("")
+	require.NoError(t, err)
+	require.Empty(t, checks)
+
+	_, err = parseHealthChecks("Deployment/upf")
+	require.Error(t, err)
+}
+
+func TestSyncHealthy(t *testing.T) {
+	testCases := map[string]struct {
+		status   map[string]any
+		expected bool
+	}{
+		"no status": {
+			status:   nil,
+			expected: true,
+		},
+		"synced": {
+			status: map[string]any{
+				"sync": map[string]any{"errorSummary": map[string]any{"totalCount": int64(0)}},
+				"conditions": []any{
+					map[string]any{"type": "Syncing", "status": "False"},
+					map[string]any{"type": "Stalled", "status": "False"},
+				},
+			},
+			expected: true,
+		},
+		"rendering errors": {
+			status: map[string]any{
+				"rendering": map[string]any{"errorSummary": map[string]any{"totalCount": int64(2)}},
+			},
+			expected: false,
+		},
+		"stalled": {
+			status: map[string]any{
+				"conditions": []any{
+					map[string]any{"type": "Stalled", "status": "True"},
+				},
+			},
+			expected: false,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: map[string]any{}}
+			if tc.status != nil {
+				u.Object["status"] = tc.status
+			}
+			_, actual := syncHealthy(u)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestResourceHealthy(t *testing.T) {
+	available := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+			},
+		},
+	}}
+	_, ok := resourceHealthy(available)
+	require.True(t, ok)
+
+	notReady := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False"},
+			},
+		},
+	}}
+	_, ok = resourceHealthy(notReady)
+	require.False(t, ok)
+
+	_, ok = resourceHealthy(&unstructured.Unstructured{Object: map[string]any{}})
+	require.True(t, ok)
+}
@@ -0,0 +1,335 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	porchclient "github.com/nephio-project/nephio/controllers/pkg/porch/client"
+	"github.com/nephio-project/nephio/controllers/pkg/reconcilers/approval"
+	ctrlconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
+	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	porchv1alpha1 "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	WindowAnnotationName       = "approval.nephio.org/rollback-window"
+	HealthChecksAnnotationName = "approval.nephio.org/rollback-health-checks"
+	RollbackOfAnnotationName   = "approval.nephio.org/rollback-of"
+
+	clusterNameKey = "nephio.org/cluster-name"
+)
+
+// errClusterUnavailable is returned by checkHealth if the health of the
+// cluster cannot be checked because the cluster is not ready or has no
+// client (yet). The package revision is not rolled back then.
+var errClusterUnavailable = errors.New("cluster unavailable")
+
+func init() {
+	reconcilerinterface.Register("rollback", &reconciler{})
+}
+
+//+kubebuilder:rbac:groups="*",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get
+//+kubebuilder:rbac:groups=focom.nephio.org,resources=focomprovisioningrequests,verbs=get
+//+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions/status,verbs=get
+//+kubebuilder:rbac:groups=porch.kpt.dev,resources=packagerevisions/approval,verbs=get;update;patch
+//+kubebuilder:rbac:groups=config.porch.kpt.dev,resources=repositories,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c any) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
+	cfg, ok := c.(*ctrlconfig.ControllerConfig)
+	if !ok {
+		return nil, fmt.Errorf("cannot initialize, expecting controllerConfig, got: %s", reflect.TypeOf(c).Name())
+	}
+
+	if err := porchv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+	if err := porchconfig.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+
+	r.Client = mgr.GetClient()
+	r.apiReader = mgr.GetAPIReader()
+	r.porchRESTClient = cfg.PorchRESTClient
+	r.recorder = mgr.GetEventRecorderFor("rollback-controller")
+	r.requeueDuration = time.Duration(cfg.RollbackRequeueDuration) * time.Second
+
+	return nil, ctrl.NewControllerManagedBy(mgr).
+		Named("RollbackController").
+		For(&porchv1alpha1.PackageRevision{}).
+		Complete(r)
+}
+
+// Indexes returns the indexes resolving the workload clusters by name, and
+// the index listing the revisions of a package.
+func (r *reconciler) Indexes() []reconcilerinterface.Index {
+	return append(approval.Indexes(), cluster.Indexes()...)
+}
+
+// reconciler rolls back a published PackageRevision to the previous
+// published revision if the target cluster becomes unhealthy within the
+// rollback window after publishing.
+type reconciler struct {
+	client.Client
+	apiReader       client.Reader
+	porchRESTClient rest.Interface
+	recorder        record.EventRecorder
+	requeueDuration time.Duration
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("req", req)
+
+	pr := &porchv1alpha1.PackageRevision{}
+	if err := r.apiReader.Get(ctx, req.NamespacedName, pr); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		if resource.IgnoreNotFound(err) != nil {
+			log.Error(err, "cannot get resource")
+			return ctrl.Result{}, errors.Wrap(resource.IgnoreNotFound(err), "cannot get resource")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// rollback revisions created by this controller are taken to Published
+	// by this controller as well
+	if _, ok := pr.GetAnnotations()[RollbackOfAnnotationName]; ok {
+		return r.promote(ctx, pr)
+	}
+
+	if !porchv1alpha1.LifecycleIsPublished(pr.Spec.Lifecycle) {
+		return ctrl.Result{}, nil
+	}
+	window, ok := pr.GetAnnotations()[WindowAnnotationName]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "invalid %q annotation value: %q", WindowAnnotationName, window)
+		return ctrl.Result{}, nil
+	}
+	remaining := time.Until(pr.Status.PublishedAt.Add(d))
+	if remaining <= 0 {
+		// the rollback window has passed
+		return ctrl.Result{}, nil
+	}
+
+	previous, done, err := r.findPrevious(ctx, pr)
+	if err != nil {
+		log.Error(err, "cannot list package revisions")
+		return ctrl.Result{}, errors.Wrap(err, "cannot list package revisions")
+	}
+	if done {
+		// superseded by a newer revision or already rolled back
+		return ctrl.Result{}, nil
+	}
+
+	requeue := r.requeueDuration
+	if remaining < requeue {
+		requeue = remaining
+	}
+
+	healthy, reason, err := r.checkHealth(ctx, pr)
+	if errors.Is(err, errClusterUnavailable) {
+		log.Info("cannot check cluster health", "reason", err.Error())
+		r.recorder.Eventf(pr, corev1.EventTypeNormal,
+			"HealthUnknown", "%s, retrying", err.Error())
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+	if err != nil {
+		log.Error(err, "cannot check cluster health")
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "cannot check cluster health: %s", err.Error())
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+	if healthy {
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+
+	if previous == nil {
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"RollbackFailed", "%s, but there is no previous published revision of %s", reason, pr.Spec.PackageName)
+		return ctrl.Result{}, nil
+	}
+
+	draft := newRollbackDraft(pr, previous)
+	if err := r.Create(ctx, draft); err != nil {
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "error creating rollback revision: %s", err.Error())
+		return ctrl.Result{}, err
+	}
+	r.recorder.Eventf(pr, corev1.EventTypeWarning,
+		"RollingBack", "%s, rolling back to %s", reason, previous.Name)
+
+	return ctrl.Result{}, nil
+}
+
+// findPrevious returns the latest published revision of the package before
+// the given one. done is true if the package revision is no longer the latest
+// published revision, or if it was already rolled back.
+func (r *reconciler) findPrevious(ctx context.Context, pr *porchv1alpha1.PackageRevision) (*porchv1alpha1.PackageRevision, bool, error) {
+	var prList porchv1alpha1.PackageRevisionList
+	if err := r.List(ctx, &prList, client.InNamespace(pr.Namespace),
+		client.MatchingFields{approval.PackageIndexKey: approval.PackageKey(pr)}); err != nil {
+		return nil, false, err
+	}
+
+	var previous *porchv1alpha1.PackageRevision
+	for i := range prList.Items {
+		pr2 := &prList.Items[i]
+		if pr2.Spec.RepositoryName != pr.Spec.RepositoryName ||
+			pr2.Spec.PackageName != pr.Spec.PackageName {
+			continue
+		}
+		if pr2.GetAnnotations()[RollbackOfAnnotationName] == pr.Name {
+			return nil, true, nil
+		}
+		if !porchv1alpha1.LifecycleIsPublished(pr2.Spec.Lifecycle) {
+			continue
+		}
+		if pr2.Spec.Revision > pr.Spec.Revision {
+			return nil, true, nil
+		}
+		if pr2.Spec.Revision < pr.Spec.Revision &&
+			(previous == nil || pr2.Spec.Revision > previous.Spec.Revision) {
+			previous = pr2
+		}
+	}
+	return previous, false, nil
+}
+
+// newRollbackDraft builds a new draft of the package that copies the
+// resources of the previous revision.
+func newRollbackDraft(pr, previous *porchv1alpha1.PackageRevision) *porchv1alpha1.PackageRevision {
+	return &porchv1alpha1.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: porchv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "PackageRevision",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pr.Namespace,
+			Annotations: map[string]string{
+				RollbackOfAnnotationName: pr.Name,
+			},
+		},
+		Spec: porchv1alpha1.PackageRevisionSpec{
+			PackageName:    pr.Spec.PackageName,
+			RepositoryName: pr.Spec.RepositoryName,
+			WorkspaceName:  fmt.Sprintf("rollback-%s", pr.Spec.WorkspaceName),
+			Lifecycle:      porchv1alpha1.PackageRevisionLifecycleDraft,
+			Tasks: []porchv1alpha1.Task{
+				{
+					Type: porchv1alpha1.TaskTypeEdit,
+					Edit: &porchv1alpha1.PackageEditTaskSpec{
+						Source: &porchv1alpha1.PackageRevisionRef{Name: previous.Name},
+					},
+				},
+			},
+		},
+	}
+}
+
+// promote takes a rollback revision from Draft to Proposed to Published
+func (r *reconciler) promote(ctx context.Context, pr *porchv1alpha1.PackageRevision) (ctrl.Result, error) {
+	var err error
+	switch pr.Spec.Lifecycle {
+	case porchv1alpha1.PackageRevisionLifecycleDraft:
+		pr.Spec.Lifecycle = porchv1alpha1.PackageRevisionLifecycleProposed
+		err = r.Update(ctx, pr)
+	case porchv1alpha1.PackageRevisionLifecycleProposed:
+		err = porchclient.UpdatePackageRevisionApproval(ctx, r.porchRESTClient, client.ObjectKey{
+			Namespace: pr.Namespace,
+			Name:      pr.Name,
+		}, porchv1alpha1.PackageRevisionLifecyclePublished)
+		if err == nil {
+			r.recorder.Eventf(pr, corev1.EventTypeNormal,
+				"RolledBack", "rolled back %s", pr.GetAnnotations()[RollbackOfAnnotationName])
+		}
+	default:
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		r.recorder.Eventf(pr, corev1.EventTypeWarning,
+			"Error", "error promoting rollback revision: %s", err.Error())
+	}
+	return ctrl.Result{}, err
+}
+
+// checkHealth checks the health of the cluster the package revision is
+// deployed to. The cluster is named after the repository, unless the
+// repository has a nephio.org/cluster-name label. It returns
+// errClusterUnavailable if the cluster is not ready or has no client.
+func (r *reconciler) checkHealth(ctx context.Context, pr *porchv1alpha1.PackageRevision) (bool, string, error) {
+	clusterName := pr.Spec.RepositoryName
+	repo := &porchconfig.Repository{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: pr.Namespace, Name: pr.Spec.RepositoryName}, repo); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return false, "", err
+		}
+	}
+	if name, ok := repo.GetLabels()[clusterNameKey]; ok {
+		clusterName = name
+	}
+
+	checks, err := parseHealthChecks(pr.GetAnnotations()[HealthChecksAnnotationName])
+	if err != nil {
+		return false, "", err
+	}
+
+	clusterClient, ok, err := cluster.Cluster{Client: r.Client}.ResolveClusterClient(ctx, clusterName)
+	if err != nil {
+		return false, "", err
+	}
+	if !ok {
+		return false, "", errors.Wrapf(errClusterUnavailable, "cluster client not found for cluster %s", clusterName)
+	}
+	cl, ready, err := clusterClient.GetClusterClient(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	if !ready {
+		readiness := cluster.GetReadiness(ctx, clusterClient)
+		return false, "", errors.Wrapf(errClusterUnavailable, "cluster %s is not ready: %s: %s", clusterName, readiness.Reason, readiness.Message)
+	}
+	return checkClusterHealth(ctx, cl, checks)
+}
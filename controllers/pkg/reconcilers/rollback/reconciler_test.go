@@ -0,0 +1,195 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollback
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nephio-project/nephio/controllers/pkg/cluster"
+	"github.com/nephio-project/nephio/controllers/pkg/reconcilers/approval"
+	porchapi "github.com/nephio-project/porch/api/porch/v1alpha1"
+	porchconfig "github.com/nephio-project/porch/api/porchconfig/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func packageRevision(name string, revision int, lifecycle porchapi.PackageRevisionLifecycle) porchapi.PackageRevision {
+	return porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: "edge01",
+			PackageName:    "upf",
+			WorkspaceName:  name,
+			Revision:       revision,
+			Lifecycle:      lifecycle,
+		},
+	}
+}
+
+func TestFindPrevious(t *testing.T) {
+	rollback := packageRevision("rollback", 0, porchapi.PackageRevisionLifecycleDraft)
+	rollback.Annotations = map[string]string{RollbackOfAnnotationName: "v3"}
+
+	testCases := map[string]struct {
+		pr               porchapi.PackageRevision
+		items            []porchapi.PackageRevision
+		expectedPrevious string
+		expectedDone     bool
+	}{
+		"latest of several previous revisions": {
+			pr: packageRevision("v3", 3, porchapi.PackageRevisionLifecyclePublished),
+			items: []porchapi.PackageRevision{
+				packageRevision("v1", 1, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("v2", 2, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("v3", 3, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("draft", 0, porchapi.PackageRevisionLifecycleDraft),
+			},
+			expectedPrevious: "v2",
+		},
+		"no previous revision": {
+			pr: packageRevision("v1", 1, porchapi.PackageRevisionLifecyclePublished),
+			items: []porchapi.PackageRevision{
+				packageRevision("v1", 1, porchapi.PackageRevisionLifecyclePublished),
+			},
+		},
+		"superseded": {
+			pr: packageRevision("v2", 2, porchapi.PackageRevisionLifecyclePublished),
+			items: []porchapi.PackageRevision{
+				packageRevision("v1", 1, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("v2", 2, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("v3", 3, porchapi.PackageRevisionLifecyclePublished),
+			},
+			expectedDone: true,
+		},
+		"already rolled back": {
+			pr: packageRevision("v3", 3, porchapi.PackageRevisionLifecyclePublished),
+			items: []porchapi.PackageRevision{
+				packageRevision("v2", 2, porchapi.PackageRevisionLifecyclePublished),
+				packageRevision("v3", 3, porchapi.PackageRevisionLifecyclePublished),
+				rollback,
+			},
+			expectedDone: true,
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, porchapi.AddToScheme(scheme))
+	for tn, tc := range testCases {
+		// a revision of another package in the same repository
+		other := packageRevision("other", 9, porchapi.PackageRevisionLifecyclePublished)
+		other.Spec.PackageName = "smf"
+		objects := []client.Object{&other}
+		for i := range tc.items {
+			objects = append(objects, &tc.items[i])
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithIndex(&porchapi.PackageRevision{}, approval.PackageIndexKey, func(o client.Object) []string {
+				return []string{approval.PackageKey(o.(*porchapi.PackageRevision))}
+			}).
+			WithObjects(objects...).Build()
+		r := reconciler{Client: c}
+		t.Run(tn, func(t *testing.T) {
+			previous, done, err := r.findPrevious(context.TODO(), &tc.pr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDone, done)
+			if tc.expectedPrevious == "" {
+				require.Nil(t, previous)
+				return
+			}
+			require.Equal(t, tc.expectedPrevious, previous.Name)
+
+			draft := newRollbackDraft(&tc.pr, previous)
+			require.Equal(t, tc.pr.Name, draft.Annotations[RollbackOfAnnotationName])
+			require.Equal(t, porchapi.PackageRevisionLifecycleDraft, draft.Spec.Lifecycle)
+			require.Equal(t, previous.Name, draft.Spec.Tasks[0].Edit.Source.Name)
+		})
+	}
+}
+
+func TestReconcileClusterUnavailable(t *testing.T) {
+	notReady := &capiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge01"},
+		Status: capiv1beta1.ClusterStatus{
+			Conditions: capiv1beta1.Conditions{{Type: capiv1beta1.ReadyCondition, Status: corev1.ConditionFalse}},
+		},
+	}
+	kubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge01-kubeconfig"},
+		Type:       corev1.SecretType("cluster.x-k8s.io/secret"),
+	}
+
+	testCases := map[string]struct {
+		objects         []client.Object
+		expectedMessage string
+	}{
+		"cluster not ready": {
+			objects:         []client.Object{notReady, kubeconfig},
+			expectedMessage: "cluster edge01 is not ready",
+		},
+		"no cluster client": {
+			expectedMessage: "cluster client not found for cluster edge01",
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, capiv1beta1.AddToScheme(scheme))
+	require.NoError(t, porchapi.AddToScheme(scheme))
+	require.NoError(t, porchconfig.AddToScheme(scheme))
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			v1 := packageRevision("v1", 1, porchapi.PackageRevisionLifecyclePublished)
+			v2 := packageRevision("v2", 2, porchapi.PackageRevisionLifecyclePublished)
+			v2.Annotations = map[string]string{WindowAnnotationName: "30m"}
+			v2.Status.PublishedAt = metav1.Now()
+			objects := append([]client.Object{&v1, &v2}, tc.objects...)
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&porchapi.PackageRevision{}, approval.PackageIndexKey, func(o client.Object) []string {
+					return []string{approval.PackageKey(o.(*porchapi.PackageRevision))}
+				}).
+				WithIndex(&capiv1beta1.Cluster{}, cluster.ClusterNameIndex, cluster.IndexClusterByName).
+				WithIndex(&corev1.Secret{}, cluster.SecretClusterNameIndex, cluster.IndexSecretByClusterName).
+				WithObjects(objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := reconciler{Client: c, apiReader: c, recorder: recorder, requeueDuration: time.Minute}
+
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "v2"}})
+			require.NoError(t, err)
+			require.Equal(t, time.Minute, result.RequeueAfter)
+
+			// no rollback revision is created
+			prs := &porchapi.PackageRevisionList{}
+			require.NoError(t, c.List(context.TODO(), prs))
+			require.Len(t, prs.Items, 2)
+
+			event := <-recorder.Events
+			require.True(t, strings.HasPrefix(event, corev1.EventTypeNormal+" HealthUnknown "), event)
+			require.Contains(t, event, tc.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spirebootstrap
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// agentConfigName is the configMap in the spire namespace configuring
+	// the SPIRE agents of the clusters, under the agentConfigKey
+	agentConfigName = "spire-agent-config"
+	agentConfigKey  = "config.yaml"
+
+	psatNodeAttestor = "k8s_psat"
+)
+
+var (
+	logLevels   = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+	trustDomain = regexp.MustCompile(`^[a-z0-9._-]+$`)
+)
+
+// plugin is a SPIRE agent plugin and its data
+type plugin struct {
+	Name       string         `json:"name"`
+	PluginData map[string]any `json:"pluginData,omitempty"`
+}
+
+// agentConfig configures the SPIRE agent of a cluster, the fields that are
+// not set keep their default
+type agentConfig struct {
+	TrustDomain string `json:"trustDomain,omitempty"`
+	LogLevel    string `json:"logLevel,omitempty"`
+	// NodeAttestor defaults to k8s_psat, which gets the name of the cluster
+	// unless its plugin data sets it
+	NodeAttestor *plugin `json:"nodeAttestor,omitempty"`
+	KeyManager   *plugin `json:"keyManager,omitempty"`
+	// SkipKubeletVerification skips the verification of the kubelet
+	// certificate by the k8s workload attestor
+	SkipKubeletVerification *bool `json:"skipKubeletVerification,omitempty"`
+}
+
+// spireBootstrapConfig is the content of the agent configMap: the
+// configuration of the agents of all the clusters, and the overrides per
+// cluster name
+type spireBootstrapConfig struct {
+	agentConfig `json:",inline"`
+	Clusters    map[string]agentConfig `json:"clusters,omitempty"`
+}
+
+// defaultAgentConfig is the configuration of the agents without agent
+// configMap
+func defaultAgentConfig() agentConfig {
+	return agentConfig{
+		TrustDomain:             "example.org",
+		LogLevel:                "DEBUG",
+		NodeAttestor:            &plugin{Name: psatNodeAttestor},
+		KeyManager:              &plugin{Name: "memory"},
+		SkipKubeletVerification: ptr.To(true),
+	}
+}
+
+// merge returns the configuration with the fields set by the override
+// replaced, a plugin is replaced with its data
+func (r agentConfig) merge(override agentConfig) agentConfig {
+	if override.TrustDomain != "" {
+		r.TrustDomain = override.TrustDomain
+	}
+	if override.LogLevel != "" {
+		r.LogLevel = override.LogLevel
+	}
+	if override.NodeAttestor != nil {
+		r.NodeAttestor = override.NodeAttestor
+	}
+	if override.KeyManager != nil {
+		r.KeyManager = override.KeyManager
+	}
+	if override.SkipKubeletVerification != nil {
+		r.SkipKubeletVerification = override.SkipKubeletVerification
+	}
+	return r
+}
+
+func (r agentConfig) validate() error {
+	if r.TrustDomain != "" && !trustDomain.MatchString(r.TrustDomain) {
+		return fmt.Errorf("invalid trust domain %q", r.TrustDomain)
+	}
+	if r.LogLevel != "" && !slices.Contains(logLevels, r.LogLevel) {
+		return fmt.Errorf("invalid log level %q, expecting one of %v", r.LogLevel, logLevels)
+	}
+	for kind, p := range map[string]*plugin{"node attestor": r.NodeAttestor, "key manager": r.KeyManager} {
+		if p != nil && p.Name == "" {
+			return fmt.Errorf("%s without name", kind)
+		}
+	}
+	return nil
+}
+
+// parseSpireBootstrapConfig parses and validates the content of the agent
+// configMap
+func parseSpireBootstrapConfig(data string) (*spireBootstrapConfig, error) {
+	cfg := &spireBootstrapConfig{}
+	if err := yaml.UnmarshalStrict([]byte(data), cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", agentConfigKey, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", agentConfigKey, err)
+	}
+	for name, override := range cfg.Clusters {
+		if err := override.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s of cluster %s: %w", agentConfigKey, name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// getAgentConfig returns the configuration of the agent of the cluster: the
+// defaults, overridden by the agent configMap and by the overrides of the
+// cluster in the configMap
+func (r *reconciler) getAgentConfig(ctx context.Context, clusterName string) (agentConfig, error) {
+	cfg := defaultAgentConfig()
+	cm := &v1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "spire", Name: agentConfigName}, cm); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return agentConfig{}, fmt.Errorf("cannot get %s configMap: %w", agentConfigName, err)
+		}
+		return cfg, nil
+	}
+	sbc, err := parseSpireBootstrapConfig(cm.Data[agentConfigKey])
+	if err != nil {
+		return agentConfig{}, err
+	}
+	return cfg.merge(sbc.agentConfig).merge(sbc.Clusters[clusterName]), nil
+}
+
+// renderAgentConfig returns the agent.conf of the SPIRE agent of the cluster
+func renderAgentConfig(cfg agentConfig, cluster, serverAddress, serverPort string) (string, error) {
+	doc := &hclBody{}
+	agent := doc.block("agent")
+	agent.attribute("data_dir", "/run/spire")
+	agent.attribute("log_level", cfg.LogLevel)
+	agent.attribute("server_address", serverAddress)
+	agent.attribute("server_port", serverPort)
+	agent.attribute("socket_path", "/run/spire/sockets/spire-agent.sock")
+	agent.attribute("trust_bundle_path", "/run/spire/bundle/bundle.crt")
+	agent.attribute("trust_domain", cfg.TrustDomain)
+
+	plugins := doc.block("plugins")
+	nodeAttestorData := maps.Clone(cfg.NodeAttestor.PluginData)
+	if cfg.NodeAttestor.Name == psatNodeAttestor {
+		if nodeAttestorData == nil {
+			nodeAttestorData = map[string]any{}
+		}
+		if _, ok := nodeAttestorData["cluster"]; !ok {
+			nodeAttestorData["cluster"] = cluster
+		}
+	}
+	pluginBlock(plugins, "NodeAttestor", cfg.NodeAttestor.Name, nodeAttestorData)
+	pluginBlock(plugins, "KeyManager", cfg.KeyManager.Name, cfg.KeyManager.PluginData)
+	pluginBlock(plugins, "WorkloadAttestor", "k8s", map[string]any{
+		"skip_kubelet_verification": ptr.Deref(cfg.SkipKubeletVerification, false),
+	})
+	return doc.render()
+}
+
+// pluginBlock adds the block of the plugin, its data sorted by name
+func pluginBlock(plugins *hclBody, kind, name string, data map[string]any) {
+	pluginData := plugins.block(kind, name).block("plugin_data")
+	pluginData.items = mapBody(data).items
+}
+
+// isAgentConfig returns true for the agent configMap
+func isAgentConfig(obj client.Object) bool {
+	return obj.GetNamespace() == "spire" && obj.GetName() == agentConfigName
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spirebootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/hcl"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRenderAgentConfig(t *testing.T) {
+	// the defaults render the agent.conf of the previous releases
+	got, err := renderAgentConfig(defaultAgentConfig(), "edge01", "10.0.0.1", "8081")
+	require.NoError(t, err)
+	require.Equal(t, `agent {
+  data_dir = "/run/spire"
+  log_level = "DEBUG"
+  server_address = "10.0.0.1"
+  server_port = "8081"
+  socket_path = "/run/spire/sockets/spire-agent.sock"
+  trust_bundle_path = "/run/spire/bundle/bundle.crt"
+  trust_domain = "example.org"
+}
+
+plugins {
+  NodeAttestor "k8s_psat" {
+    plugin_data {
+      cluster = "edge01"
+    }
+  }
+  KeyManager "memory" {
+    plugin_data {
+    }
+  }
+  WorkloadAttestor "k8s" {
+    plugin_data {
+      skip_kubelet_verification = true
+    }
+  }
+}
+`, got)
+
+	cfg := defaultAgentConfig()
+	cfg.KeyManager = &plugin{Name: "disk", PluginData: map[string]any{
+		"directory": "/run/spire/${data}",
+		"keys":      []any{"a", float64(1), true},
+		"nested":    map[string]any{"quoted": "a \"b\"\n"},
+	}}
+	got, err = renderAgentConfig(cfg, "edge01", "10.0.0.1", "8081")
+	require.NoError(t, err)
+	require.Contains(t, got, `  KeyManager "disk" {
+    plugin_data {
+      directory = "/run/spire/${data}"
+      keys = ["a", 1, true]
+      nested {
+        quoted = "a \"b\"\n"
+      }
+    }
+  }
+`)
+
+	// SPIRE parses the agent.conf with HCL v1
+	parsed := map[string]any{}
+	require.NoError(t, hcl.Decode(&parsed, got))
+	keyManager := parsed["plugins"].([]map[string]any)[0]["KeyManager"].([]map[string]any)[0]["disk"].([]map[string]any)[0]
+	require.Equal(t, []map[string]any{{
+		"directory": "/run/spire/${data}",
+		"keys":      []any{"a", 1, true},
+		"nested":    []map[string]any{{"quoted": "a \"b\"\n"}},
+	}}, keyManager["plugin_data"])
+
+	// the plugin data cannot inject attributes
+	cfg.KeyManager = &plugin{Name: "disk", PluginData: map[string]any{"a = 1\nb": "c"}}
+	_, err = renderAgentConfig(cfg, "edge01", "10.0.0.1", "8081")
+	require.Error(t, err)
+}
+
+func TestGetAgentConfig(t *testing.T) {
+	ctx := context.Background()
+	newConfigMap := func(data string) client.Object {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "spire", Name: agentConfigName},
+			Data:       map[string]string{agentConfigKey: data},
+		}
+	}
+	cases := map[string]struct {
+		objects []client.Object
+		cluster string
+		want    func(cfg *agentConfig)
+		wantErr bool
+	}{
+		"NoConfigMap": {
+			cluster: "edge01",
+			want:    func(cfg *agentConfig) {},
+		},
+		"Defaults": {
+			objects: []client.Object{newConfigMap(`
+trustDomain: nephio.org
+logLevel: INFO
+skipKubeletVerification: false
+clusters:
+  edge02:
+    logLevel: WARN
+`)},
+			cluster: "edge01",
+			want: func(cfg *agentConfig) {
+				cfg.TrustDomain = "nephio.org"
+				cfg.LogLevel = "INFO"
+				cfg.SkipKubeletVerification = new(bool)
+			},
+		},
+		"ClusterOverride": {
+			objects: []client.Object{newConfigMap(`
+trustDomain: nephio.org
+logLevel: INFO
+keyManager:
+  name: disk
+  pluginData:
+    directory: /run/spire/data
+clusters:
+  edge02:
+    logLevel: WARN
+    nodeAttestor:
+      name: join_token
+`)},
+			cluster: "edge02",
+			want: func(cfg *agentConfig) {
+				cfg.TrustDomain = "nephio.org"
+				cfg.LogLevel = "WARN"
+				cfg.NodeAttestor = &plugin{Name: "join_token"}
+				cfg.KeyManager = &plugin{Name: "disk", PluginData: map[string]any{"directory": "/run/spire/data"}}
+			},
+		},
+		"UnknownField": {
+			objects: []client.Object{newConfigMap(`trustDomian: nephio.org`)},
+			cluster: "edge01",
+			wantErr: true,
+		},
+		"InvalidLogLevel": {
+			objects: []client.Object{newConfigMap(`logLevel: VERBOSE`)},
+			cluster: "edge01",
+			wantErr: true,
+		},
+		"InvalidClusterOverride": {
+			objects: []client.Object{newConfigMap(`
+clusters:
+  edge01:
+    trustDomain: "Nephio Org"
+`)},
+			cluster: "edge02",
+			wantErr: true,
+		},
+		"PluginWithoutName": {
+			objects: []client.Object{newConfigMap(`
+keyManager:
+  pluginData:
+    directory: /run/spire/data
+`)},
+			cluster: "edge01",
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &reconciler{Client: fake.NewClientBuilder().WithObjects(tc.objects...).Build()}
+			got, err := r.getAgentConfig(ctx, tc.cluster)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			want := defaultAgentConfig()
+			tc.want(&want)
+			require.Equal(t, want, got)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spirebootstrap
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// hclBody is the body of an HCL block, its attributes and nested blocks are
+// written in the order they are added
+type hclBody struct {
+	items []hclItem
+}
+
+// hclItem is an attribute, or a block when it has a body
+type hclItem struct {
+	name   string
+	labels []string
+	value  any
+	body   *hclBody
+}
+
+// attribute adds an attribute, a map value is written as a nested block
+func (b *hclBody) attribute(name string, value any) {
+	b.items = append(b.items, hclItem{name: name, value: value})
+}
+
+// block adds a nested block and returns its body
+func (b *hclBody) block(name string, labels ...string) *hclBody {
+	body := &hclBody{}
+	b.items = append(b.items, hclItem{name: name, labels: labels, body: body})
+	return body
+}
+
+// render returns the body as an HCL document, the top-level blocks separated
+// by an empty line
+func (b *hclBody) render() (string, error) {
+	sb := &strings.Builder{}
+	for i, item := range b.items {
+		if i > 0 && item.body != nil {
+			sb.WriteString("\n")
+		}
+		if err := item.write(sb, 0); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+func (r hclItem) write(sb *strings.Builder, depth int) error {
+	if !identifier.MatchString(r.name) {
+		return fmt.Errorf("invalid HCL identifier %q", r.name)
+	}
+	indent := strings.Repeat("  ", depth)
+	body := r.body
+	if body == nil {
+		m, ok := r.value.(map[string]any)
+		if !ok {
+			value, err := hclValue(r.value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", r.name, err)
+			}
+			fmt.Fprintf(sb, "%s%s = %s\n", indent, r.name, value)
+			return nil
+		}
+		body = mapBody(m)
+	}
+	sb.WriteString(indent + r.name)
+	for _, label := range r.labels {
+		sb.WriteString(" " + hclString(label))
+	}
+	sb.WriteString(" {\n")
+	for _, item := range body.items {
+		if err := item.write(sb, depth+1); err != nil {
+			return fmt.Errorf("%s: %w", r.name, err)
+		}
+	}
+	sb.WriteString(indent + "}\n")
+	return nil
+}
+
+// mapBody returns the body of the attributes of the map, sorted by name
+func mapBody(m map[string]any) *hclBody {
+	body := &hclBody{}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		body.attribute(k, m[k])
+	}
+	return body
+}
+
+// hclValue returns the HCL literal of a scalar or a list of scalars
+func hclValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return hclString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []string:
+		values := make([]any, 0, len(v))
+		for _, s := range v {
+			values = append(values, s)
+		}
+		return hclValue(values)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			if _, ok := e.([]any); ok {
+				return "", fmt.Errorf("nested lists are not supported")
+			}
+			value, err := hclValue(e)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		return "[" + strings.Join(values, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("unsupported value %v of type %T", value, value)
+}
+
+// hclString returns the quoted HCL string, with the escapes of HCL v1 that
+// SPIRE parses its configuration with. HCL v1 has no template escape, ${ is
+// kept as is and SPIRE does not interpolate it.
+func hclString(s string) string {
+	sb := &strings.Builder{}
+	sb.WriteString(`"`)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteString(`"`)
+	return sb.String()
+}
@@ -19,7 +19,6 @@ package spirebootstrap
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/nephio-project/nephio/controllers/pkg/cluster"
@@ -27,20 +26,26 @@ import (
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const spirekubeconfig = "spire-kubeconfig"
+const (
+	spirekubeconfig = "spire-kubeconfig"
+	// fieldManager is the field manager of the configMaps applied to the
+	// clusters, the controller owns their content
+	fieldManager = "nephio-spire-bootstrap"
+)
 
 func init() {
 	reconcilerinterface.Register("workloadidentity", &reconciler{})
@@ -48,6 +53,10 @@ func init() {
 
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list
+//+kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get
+//+kubebuilder:rbac:groups=focom.nephio.org,resources=focomprovisioningrequests,verbs=get
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c any) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
@@ -56,6 +65,8 @@ func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c a
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("BootstrapSpireController").
 		For(&capiv1beta1.Cluster{}).
+		Watches(&v1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.allClusters),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isAgentConfig))).
 		Complete(r)
 }
 
@@ -63,6 +74,21 @@ type reconciler struct {
 	client.Client
 }
 
+// allClusters returns the clusters whose agent configuration changes with
+// the agent configMap
+func (r *reconciler) allClusters(ctx context.Context, _ client.Object) []reconcile.Request {
+	clusters := &capiv1beta1.ClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		log.FromContext(ctx).Error(err, "cannot list clusters")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i])})
+	}
+	return requests
+}
+
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
@@ -87,13 +113,6 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reconcile.Result{}, err
 	}
 
-	secrets := &v1.SecretList{}
-	if err := r.List(ctx, secrets); err != nil {
-		msg := "cannot list secrets"
-		log.Error(err, msg)
-		return ctrl.Result{}, errors.Wrap(err, msg)
-	}
-
 	// Get the spire-server service
 	spireService := &v1.Service{}
 	err = r.Get(ctx, types.NamespacedName{Name: "spire-server", Namespace: "spire"}, spireService)
@@ -117,104 +136,112 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		port = fmt.Sprint(spireService.Spec.Ports[0].Port)
 	}
 
+	spireAgentConfig, err := r.getAgentConfig(ctx, cl.Name)
+	if err != nil {
+		msg := "invalid spire agent configuration"
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
+
 	// Construct the service address
-	spireAgentCM, err := createSpireAgentConfigMap("spire-agent", "spire", cl.Name, clusterIP, port)
+	spireAgentCM, err := createSpireAgentConfigMap("spire-agent", "spire", cl.Name, clusterIP, port, spireAgentConfig)
 	if err != nil {
 		msg := "failed to create spireAgent ConfigMap"
 		log.Error(err, msg)
 		return ctrl.Result{}, errors.Wrap(err, msg)
 	}
 
-	for _, secret := range secrets.Items {
-		if strings.Contains(secret.GetName(), cl.Name) {
-			secret := secret // required to prevent gosec warning: G601 (CWE-118): Implicit memory aliasing in for loop
-			clusterClient, ok := cluster.Cluster{Client: r.Client}.GetClusterClient(&secret)
-			if ok {
-				client, ready, err := clusterClient.GetClusterClient(ctx)
-				if err != nil {
-					msg := "cannot get clusterClient"
-					log.Error(err, msg)
-					return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
-				}
-				if !ready {
-					log.Info("cluster not ready")
-					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-				}
-				kubeconfig := secret.Data["value"]
-				config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
-				if err != nil {
-					msg := "failed to get kubeconfig"
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-
-				}
-				clientset, err := kubernetes.NewForConfig(config)
-				if err != nil {
-					msg := "failed to create rest config"
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-
-				kubeconfigCM, err := r.createKubeconfigConfigMap(ctx, clientset, cl.Name)
-				if err != nil {
-					msg := "Error creating Kubeconfig configmap"
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-
-				err = r.Update(ctx, kubeconfigCM)
-				if err != nil {
-					msg := "failed to Update Kubeconfig list configmap"
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-
-				err = r.updateClusterListConfigMap(ctx, cl.Name)
-				if err != nil {
-					msg := "Cluster List could not be updated"
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-
-				remoteNamespace := configMap.Namespace
-				ns := &v1.Namespace{}
-				if err = client.Get(ctx, types.NamespacedName{Name: remoteNamespace}, ns); err != nil {
-					if resource.IgnoreNotFound(err) != nil {
-						msg := fmt.Sprintf("cannot get namespace: %s", remoteNamespace)
-						log.Error(err, msg)
-						return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
-					}
-					msg := fmt.Sprintf("namespace: %s, does not exist, retry...", remoteNamespace)
-					log.Error(err, msg)
-					return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-				}
-
-				newcr := configMap.DeepCopy()
-
-				newcr.ResourceVersion = ""
-				newcr.UID = ""
-				newcr.Namespace = remoteNamespace
-
-				newAgentConf := spireAgentCM.DeepCopy()
-				newAgentConf.ResourceVersion = ""
-				newAgentConf.UID = ""
-				newAgentConf.Namespace = remoteNamespace
-				log.Info("secret info", "secret", newcr.Annotations)
-				log.Info("configMap info", "configMap", newAgentConf.Annotations)
-				if err := client.Apply(ctx, newcr); err != nil {
-					msg := fmt.Sprintf("cannot apply spire-bundle configMap to cluster %s", cl.Name)
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-				if err := client.Apply(ctx, newAgentConf); err != nil {
-					msg := fmt.Sprintf("cannot apply spire-agent configMap to cluster %s", cl.Name)
-					log.Error(err, msg)
-					return ctrl.Result{}, errors.Wrap(err, msg)
-				}
-			}
-		}
+	clusterClient, ok, err := cluster.Cluster{Client: r.Client}.GetClusterClientFor(ctx, client.ObjectKeyFromObject(cl))
+	if err != nil {
+		msg := "cannot get kubeconfig secret"
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
+	if !ok {
+		log.Info("kubeconfig secret not found, cluster not provisioned yet")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	client, ready, err := clusterClient.GetClusterClient(ctx)
+	if err != nil {
+		msg := "cannot get clusterClient"
+		log.Error(err, msg)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
+	}
+	if !ready {
+		readiness := cluster.GetReadiness(ctx, clusterClient)
+		log.Info("cluster not ready", "reason", readiness.Reason, "message", readiness.Message)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	client = client.WithServerSideApply(fieldManager, true)
+	host, ok := apiServerURL(cl)
+	if !ok {
+		log.Info("control plane endpoint not set, retry...")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	kubeconfigCM, err := r.createKubeconfigConfigMap(ctx, client, host, cl.Name)
+	if err != nil {
+		msg := "Error creating Kubeconfig configmap"
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
+
+	err = r.Update(ctx, kubeconfigCM)
+	if err != nil {
+		msg := "failed to Update Kubeconfig list configmap"
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
+
+	err = r.updateClusterListConfigMap(ctx, cl.Name)
+	if err != nil {
+		msg := "Cluster List could not be updated"
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
 
+	remoteNamespace := configMap.Namespace
+	ns := &v1.Namespace{}
+	if err = client.Get(ctx, types.NamespacedName{Name: remoteNamespace}, ns); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			msg := fmt.Sprintf("cannot get namespace: %s", remoteNamespace)
+			log.Error(err, msg)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, errors.Wrap(err, msg)
+		}
+		msg := fmt.Sprintf("namespace: %s, does not exist, retry...", remoteNamespace)
+		log.Error(err, msg)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	newcr := configMap.DeepCopy()
+
+	newcr.ResourceVersion = ""
+	newcr.UID = ""
+	newcr.Namespace = remoteNamespace
+
+	newAgentConf := spireAgentCM.DeepCopy()
+	newAgentConf.ResourceVersion = ""
+	newAgentConf.UID = ""
+	newAgentConf.Namespace = remoteNamespace
+	log.Info("secret info", "secret", newcr.Annotations)
+	log.Info("configMap info", "configMap", newAgentConf.Annotations)
+	if err := client.Apply(ctx, newcr); err != nil {
+		msg := fmt.Sprintf("cannot apply spire-bundle configMap to cluster %s", cl.Name)
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
+	if err := client.Apply(ctx, newAgentConf); err != nil {
+		msg := fmt.Sprintf("cannot apply spire-agent configMap to cluster %s", cl.Name)
+		log.Error(err, msg)
+		return ctrl.Result{}, errors.Wrap(err, msg)
+	}
 	return reconcile.Result{}, nil
 }
+
+// apiServerURL returns the URL of the API server of the cluster, the endpoint
+// of its control plane that CAPI writes in the kubeconfig of the cluster
+func apiServerURL(cl *capiv1beta1.Cluster) (string, bool) {
+	if !cl.Spec.ControlPlaneEndpoint.IsValid() {
+		return "", false
+	}
+	return "https://" + cl.Spec.ControlPlaneEndpoint.String(), true
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // Tests
@@ -52,7 +53,7 @@ func TestCreateSpireAgentConfigMap(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			configMap, err := createSpireAgentConfigMap(tc.name, tc.namespace, tc.cluster, tc.serverAddress, tc.serverPort)
+			configMap, err := createSpireAgentConfigMap(tc.name, tc.namespace, tc.cluster, tc.serverAddress, tc.serverPort, defaultAgentConfig())
 
 			if tc.expectError && err == nil {
 				t.Error("expected error but got none")
@@ -147,3 +148,42 @@ func TestGetServiceExternalIP(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIServerURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		endpoint    capiv1beta1.APIEndpoint
+		expectedURL string
+		expectOK    bool
+	}{
+		{
+			name:        "hostname",
+			endpoint:    capiv1beta1.APIEndpoint{Host: "edge01.example.com", Port: 6443},
+			expectedURL: "https://edge01.example.com:6443",
+			expectOK:    true,
+		},
+		{
+			name:        "ipv6",
+			endpoint:    capiv1beta1.APIEndpoint{Host: "fd00::1", Port: 6443},
+			expectedURL: "https://[fd00::1]:6443",
+			expectOK:    true,
+		},
+		{
+			name:     "not-set",
+			endpoint: capiv1beta1.APIEndpoint{},
+			expectOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, ok := apiServerURL(&capiv1beta1.Cluster{Spec: capiv1beta1.ClusterSpec{ControlPlaneEndpoint: tc.endpoint}})
+			if ok != tc.expectOK {
+				t.Errorf("expected ok %t, got %t", tc.expectOK, ok)
+			}
+			if url != tc.expectedURL {
+				t.Errorf("expected URL %s, got %s", tc.expectedURL, url)
+			}
+		})
+	}
+}
@@ -27,42 +27,16 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func createSpireAgentConfigMap(name string, namespace string, cluster string, serverAddress string, serverPort string) (*v1.ConfigMap, error) {
-	configMapData := map[string]string{
-		"agent.conf": `
-agent {
-  data_dir = "/run/spire"
-  log_level = "DEBUG"
-  server_address = "` + serverAddress + `"
-  server_port = "` + serverPort + `"
-  socket_path = "/run/spire/sockets/spire-agent.sock"
-  trust_bundle_path = "/run/spire/bundle/bundle.crt"
-  trust_domain = "example.org"
-}
-
-plugins {
-  NodeAttestor "k8s_psat" {
-    plugin_data {
-      cluster = "` + cluster + `"
-    }
-  }
-
-  KeyManager "memory" {
-    plugin_data {
-    }
-  }
-
-  WorkloadAttestor "k8s" {
-    plugin_data {
-      skip_kubelet_verification = true
-    }
-  }
-}
-`,
+// createSpireAgentConfigMap returns the configMap with the agent.conf of the
+// SPIRE agent of the cluster
+func createSpireAgentConfigMap(name string, namespace string, cluster string, serverAddress string, serverPort string, cfg agentConfig) (*v1.ConfigMap, error) {
+	agentConf, err := renderAgentConfig(cfg, cluster, serverAddress, serverPort)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot render agent.conf")
 	}
 
 	configMap := &v1.ConfigMap{
@@ -70,7 +44,9 @@ plugins {
 			Name:      name,
 			Namespace: namespace,
 		},
-		Data: configMapData,
+		Data: map[string]string{
+			"agent.conf": agentConf,
+		},
 	}
 
 	return configMap, nil
@@ -96,7 +72,10 @@ func getServiceExternalIP(service *v1.Service) (string, error) {
 	return "", nil
 }
 
-func (r *reconciler) createKubeconfigConfigMap(ctx context.Context, clientset *kubernetes.Clientset, clustername string) (*v1.ConfigMap, error) {
+// createKubeconfigConfigMap adds the kubeconfig of the spire agent service
+// account of the cluster, read with the cached client of the cluster, to the
+// kubeconfigs configMap of the spire server
+func (r *reconciler) createKubeconfigConfigMap(ctx context.Context, remoteClient client.Client, server string, clustername string) (*v1.ConfigMap, error) {
 	log := log.FromContext(ctx)
 
 	log.Info("Creating Kubeconfig ConfigMap for the cluster", "clusterName", clustername)
@@ -111,8 +90,8 @@ func (r *reconciler) createKubeconfigConfigMap(ctx context.Context, clientset *k
 	}
 
 	// Retrieve the ServiceAccount token
-	secret, err := clientset.CoreV1().Secrets("spire").Get(ctx, "agent-sa-secret", metav1.GetOptions{})
-	if err != nil {
+	secret := &v1.Secret{}
+	if err := remoteClient.Get(ctx, types.NamespacedName{Namespace: "spire", Name: "agent-sa-secret"}, secret); err != nil {
 		msg := "failed to get Service Account token"
 		log.Error(err, msg)
 		return nil, errors.Wrap(err, msg)
@@ -120,8 +99,8 @@ func (r *reconciler) createKubeconfigConfigMap(ctx context.Context, clientset *k
 	token := string(secret.Data["token"])
 
 	// Retrieve the cluster's CA certificate
-	configMap, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "kube-root-ca.crt", metav1.GetOptions{})
-	if err != nil {
+	configMap := &v1.ConfigMap{}
+	if err := remoteClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "kube-root-ca.crt"}, configMap); err != nil {
 		msg := "failed to get cluster CA"
 		log.Error(err, msg)
 		return nil, errors.Wrap(err, msg)
@@ -137,7 +116,7 @@ func (r *reconciler) createKubeconfigConfigMap(ctx context.Context, clientset *k
 				Name: clustername,
 				Cluster: ClusterDetail{
 					CertificateAuthorityData: caCertEncoded,
-					Server:                   clientset.RESTClient().Get().URL().String(),
+					Server:                   server,
 				},
 			},
 		},
@@ -0,0 +1,284 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScopeAnnotation sets the access the credential grants to repositories,
+	// read or write; it defaults to write
+	ScopeAnnotation = "infra.nephio.org/token-scope"
+	// CredentialAnnotation sets the kind of credential, an access token
+	// (token, the default) or an SSH deploy key (ssh)
+	CredentialAnnotation = "infra.nephio.org/credential"
+	// RepositoryAnnotation sets the repository, as [<owner>/]<name>, an SSH
+	// deploy key grants access to and the argocd secret format points to
+	RepositoryAnnotation = "infra.nephio.org/repository"
+
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+
+	CredentialToken = "token"
+	CredentialSSH   = "ssh"
+
+	// keys of the Secret of an SSH deploy key
+	sshPrivateKey = corev1.SSHAuthPrivateKey
+	sshPublicKey  = "ssh-publickey"
+)
+
+// credentialOptions holds the credential settings of a Token
+type credentialOptions struct {
+	ReadOnly   bool
+	Credential string
+	// RepoOwner is empty for a repository owned by the user
+	RepoOwner string
+	RepoName  string
+	Formats   []string
+}
+
+func parseCredentialOptions(cr *infrav1alpha1.Token) (credentialOptions, error) {
+	annotations := cr.GetAnnotations()
+	opts := credentialOptions{Credential: CredentialToken}
+	switch scope := annotations[ScopeAnnotation]; scope {
+	case "", ScopeWrite:
+	case ScopeRead:
+		opts.ReadOnly = true
+	default:
+		return opts, fmt.Errorf("invalid %s annotation %q, expected %s or %s", ScopeAnnotation, scope, ScopeRead, ScopeWrite)
+	}
+	switch credential := annotations[CredentialAnnotation]; credential {
+	case "", CredentialToken:
+	case CredentialSSH:
+		opts.Credential = CredentialSSH
+	default:
+		return opts, fmt.Errorf("invalid %s annotation %q, expected %s or %s", CredentialAnnotation, credential, CredentialToken, CredentialSSH)
+	}
+	if repo := annotations[RepositoryAnnotation]; repo != "" {
+		opts.RepoName = repo
+		if owner, name, found := strings.Cut(repo, "/"); found {
+			opts.RepoOwner, opts.RepoName = owner, name
+		}
+	}
+	formats, err := parseSecretFormats(annotations)
+	if err != nil {
+		return opts, err
+	}
+	opts.Formats = formats
+	if opts.RepoName == "" && (opts.Credential == CredentialSSH || slices.Contains(opts.Formats, SecretFormatArgoCD)) {
+		return opts, fmt.Errorf("%s is required by ssh credentials and the %s secret format", RepositoryAnnotation, SecretFormatArgoCD)
+	}
+	return opts, nil
+}
+
+// repoOwner returns the owner of the repository of the options
+func (o credentialOptions) repoOwner(giteaClient gitprovider.GitProvider) (string, error) {
+	if o.RepoOwner != "" {
+		return o.RepoOwner, nil
+	}
+	u, err := giteaClient.GetMyUserInfo()
+	if err != nil {
+		return "", err
+	}
+	return u.UserName, nil
+}
+
+// issuedCredential is an existing credential on the git server
+type issuedCredential struct {
+	Name     string
+	ReadOnly bool
+}
+
+func credentialNames(creds []issuedCredential) []string {
+	names := make([]string, 0, len(creds))
+	for _, c := range creds {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// credential issues the credentials of a Token on the git server
+type credential interface {
+	// list lists the existing credentials
+	list() ([]issuedCredential, error)
+	// issue creates a credential and returns the data of its Secret
+	issue(name string, u *gitprovider.User) (map[string][]byte, error)
+	delete(name string) error
+	// matches returns true if the Secret data holds a credential of the user
+	matches(data map[string][]byte, u *gitprovider.User) bool
+	secretType() corev1.SecretType
+}
+
+func newCredential(giteaClient gitprovider.GitProvider, opts credentialOptions) credential {
+	if opts.Credential == CredentialSSH {
+		return &deployKey{giteaClient: giteaClient, opts: opts}
+	}
+	scope := gitprovider.AccessTokenScopeRepo
+	if opts.ReadOnly {
+		scope = gitprovider.AccessTokenScopeRepoRead
+	}
+	return &accessToken{giteaClient: giteaClient, scope: scope}
+}
+
+// accessToken is an access token of the user
+type accessToken struct {
+	giteaClient gitprovider.GitProvider
+	scope       gitprovider.AccessTokenScope
+}
+
+func (r *accessToken) list() ([]issuedCredential, error) {
+	tokens, err := r.giteaClient.ListAccessTokens()
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]issuedCredential, 0, len(tokens))
+	for _, token := range tokens {
+		creds = append(creds, issuedCredential{
+			Name: token.Name,
+			ReadOnly: slices.Contains(token.Scopes, gitprovider.AccessTokenScopeRepoRead) &&
+				!slices.Contains(token.Scopes, gitprovider.AccessTokenScopeRepo),
+		})
+	}
+	return creds, nil
+}
+
+func (r *accessToken) issue(name string, u *gitprovider.User) (map[string][]byte, error) {
+	token, err := r.giteaClient.CreateAccessToken(gitprovider.CreateAccessTokenOption{
+		Name:   name,
+		Scopes: []gitprovider.AccessTokenScope{r.scope},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"username": []byte(u.UserName),
+		"password": []byte(token.Token), // needed for porch
+		"token":    []byte(token.Token), // needed for configsync
+	}, nil
+}
+
+func (r *accessToken) delete(name string) error {
+	return r.giteaClient.DeleteAccessToken(name)
+}
+
+func (r *accessToken) matches(data map[string][]byte, u *gitprovider.User) bool {
+	return len(data["token"]) > 0 && string(data["username"]) == u.UserName
+}
+
+func (r *accessToken) secretType() corev1.SecretType {
+	return corev1.SecretTypeBasicAuth
+}
+
+// deployKey is an SSH deploy key of a repository, titled with the name of
+// the credential
+type deployKey struct {
+	giteaClient gitprovider.GitProvider
+	opts        credentialOptions
+}
+
+func (r *deployKey) keys() (string, []*gitprovider.DeployKey, error) {
+	owner, err := r.opts.repoOwner(r.giteaClient)
+	if err != nil {
+		return "", nil, err
+	}
+	keys, err := r.giteaClient.ListDeployKeys(owner, r.opts.RepoName)
+	return owner, keys, err
+}
+
+func (r *deployKey) list() ([]issuedCredential, error) {
+	_, keys, err := r.keys()
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]issuedCredential, 0, len(keys))
+	for _, key := range keys {
+		creds = append(creds, issuedCredential{Name: key.Title, ReadOnly: key.ReadOnly})
+	}
+	return creds, nil
+}
+
+func (r *deployKey) issue(name string, u *gitprovider.User) (map[string][]byte, error) {
+	owner, err := r.opts.repoOwner(r.giteaClient)
+	if err != nil {
+		return nil, err
+	}
+	private, public, err := newSSHKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.giteaClient.CreateDeployKey(owner, r.opts.RepoName, gitprovider.DeployKey{
+		Title:    name,
+		Key:      string(public),
+		ReadOnly: r.opts.ReadOnly,
+	}); err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		sshPrivateKey: private,
+		sshPublicKey:  public,
+	}, nil
+}
+
+func (r *deployKey) delete(name string) error {
+	owner, keys, err := r.keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if key.Title == name {
+			return r.giteaClient.DeleteDeployKey(owner, r.opts.RepoName, key.ID)
+		}
+	}
+	return fmt.Errorf("%w: deploy key %s", gitprovider.ErrNotFound, name)
+}
+
+func (r *deployKey) matches(data map[string][]byte, u *gitprovider.User) bool {
+	return len(data[sshPrivateKey]) > 0
+}
+
+func (r *deployKey) secretType() corev1.SecretType {
+	return corev1.SecretTypeSSHAuth
+}
+
+// newSSHKey generates an ed25519 key pair, returning the private key in
+// OpenSSH PEM format and the public key in authorized_keys format
+func newSSHKey(comment string) ([]byte, []byte, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	sshPublic, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(block), ssh.MarshalAuthorizedKey(sshPublic), nil
+}
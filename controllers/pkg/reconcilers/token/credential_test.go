@@ -0,0 +1,305 @@
+// Copyright 2026 The Nephio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"testing"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCredentialOptions(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        credentialOptions
+		wantErr     bool
+	}{
+		"Defaults": {
+			want: credentialOptions{Credential: CredentialToken},
+		},
+		"Read only token with formats": {
+			annotations: map[string]string{
+				ScopeAnnotation:         ScopeRead,
+				RepositoryAnnotation:    "nephio/mgmt",
+				SecretFormatsAnnotation: "argocd, flux,argocd",
+			},
+			want: credentialOptions{
+				ReadOnly:   true,
+				Credential: CredentialToken,
+				RepoOwner:  "nephio",
+				RepoName:   "mgmt",
+				Formats:    []string{SecretFormatArgoCD, SecretFormatFlux},
+			},
+		},
+		"SSH deploy key of a repository of the user": {
+			annotations: map[string]string{CredentialAnnotation: CredentialSSH, RepositoryAnnotation: "mgmt"},
+			want:        credentialOptions{Credential: CredentialSSH, RepoName: "mgmt"},
+		},
+		"SSH deploy key without repository": {
+			annotations: map[string]string{CredentialAnnotation: CredentialSSH},
+			wantErr:     true,
+		},
+		"Argo CD format without repository": {
+			annotations: map[string]string{SecretFormatsAnnotation: SecretFormatArgoCD},
+			wantErr:     true,
+		},
+		"Unknown scope": {
+			annotations: map[string]string{ScopeAnnotation: "admin"},
+			wantErr:     true,
+		},
+		"Unknown format": {
+			annotations: map[string]string{SecretFormatsAnnotation: "helm"},
+			wantErr:     true,
+		},
+		"Format template": {
+			annotations: map[string]string{
+				SecretFormatsAnnotation:               "helm",
+				SecretFormatAnnotationPrefix + "helm": "stringData:\n  token: {{ quote .Token }}\n",
+			},
+			want: credentialOptions{Credential: CredentialToken, Formats: []string{"helm"}},
+		},
+		"Invalid format template": {
+			annotations: map[string]string{
+				SecretFormatsAnnotation:               "helm",
+				SecretFormatAnnotationPrefix + "helm": "stringData:\n  token: {{ .Token\n",
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &infrav1alpha1.Token{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got, err := parseCredentialOptions(cr)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadOnlyTokenWithSecretFormats(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "config-sync",
+			Annotations: map[string]string{
+				ScopeAnnotation:         ScopeRead,
+				RepositoryAnnotation:    "mgmt",
+				SecretFormatsAnnotation: "argocd,flux",
+			},
+		},
+	}
+	provider := gitprovider.NewFake("nephio")
+	repo, err := provider.CreateRepo(gitprovider.CreateRepoOption{Name: "mgmt"})
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, []gitprovider.AccessTokenScope{gitprovider.AccessTokenScopeRepoRead}, tokens[0].Scopes)
+	secret := getSecret(t, c, cr)
+	require.Equal(t, corev1.SecretTypeBasicAuth, secret.Type)
+
+	argocd := getFormatSecret(t, r, cr, SecretFormatArgoCD)
+	require.Equal(t, "repository", argocd.Labels[argoCDSecretTypeLabel])
+	require.Equal(t, map[string][]byte{
+		"type":     []byte("git"),
+		"url":      []byte(repo.CloneURL),
+		"username": []byte("nephio"),
+		"password": secret.Data["token"],
+	}, argocd.Data)
+	flux := getFormatSecret(t, r, cr, SecretFormatFlux)
+	require.Equal(t, map[string][]byte{
+		"username": []byte("nephio"),
+		"password": secret.Data["token"],
+	}, flux.Data)
+
+	// the formats follow the token when it is issued again
+	require.NoError(t, c.Delete(context.Background(), secret))
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	secret = getSecret(t, c, cr)
+	require.Equal(t, secret.Data["token"], getFormatSecret(t, r, cr, SecretFormatArgoCD).Data["password"])
+	require.Equal(t, secret.Data["token"], getFormatSecret(t, r, cr, SecretFormatFlux).Data["password"])
+}
+
+func TestSecretFormatTemplate(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "porch",
+			Annotations: map[string]string{
+				RepositoryAnnotation:    "mgmt",
+				SecretFormatsAnnotation: "porch,argocd",
+				SecretFormatAnnotationPrefix + "porch": `
+type: kubernetes.io/basic-auth
+labels:
+  app: porch
+stringData:
+  username: {{ quote .Username }}
+  password: {{ quote .Token }}
+  url: {{ quote .RepoURL }}
+`,
+				// a template overrides the built-in format
+				SecretFormatAnnotationPrefix + SecretFormatArgoCD: `
+stringData:
+  url: {{ quote .RepoURL }}
+`,
+			},
+		},
+	}
+	provider := gitprovider.NewFake("nephio")
+	repo, err := provider.CreateRepo(gitprovider.CreateRepoOption{Name: "mgmt"})
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	secret := getSecret(t, c, cr)
+	porch := getFormatSecret(t, r, cr, "porch")
+	require.Equal(t, corev1.SecretTypeBasicAuth, porch.Type)
+	require.Equal(t, map[string]string{"app": "porch"}, porch.Labels)
+	require.Equal(t, map[string][]byte{
+		"username": []byte("nephio"),
+		"password": secret.Data["token"],
+		"url":      []byte(repo.CloneURL),
+	}, porch.Data)
+	argocd := getFormatSecret(t, r, cr, SecretFormatArgoCD)
+	require.Equal(t, corev1.SecretTypeOpaque, argocd.Type)
+	require.Equal(t, map[string][]byte{"url": []byte(repo.CloneURL)}, argocd.Data)
+
+	// a template that does not render a secret fails the Token
+	cr.Annotations[SecretFormatAnnotationPrefix+"porch"] = "data: {{ quote .Token }}"
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.ErrorContains(t, err, "does not render a secret")
+}
+
+func TestSSHDeployKey(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "argocd",
+			Annotations: map[string]string{
+				CredentialAnnotation:    CredentialSSH,
+				ScopeAnnotation:         ScopeRead,
+				RepositoryAnnotation:    "nephio/mgmt",
+				SecretFormatsAnnotation: SecretFormatArgoCD,
+			},
+		},
+	}
+	provider := gitprovider.NewFake("nephio")
+	repo, err := provider.CreateRepo(gitprovider.CreateRepoOption{Name: "mgmt"})
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+	keys, err := provider.ListDeployKeys("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, cr.GetTokenName(), keys[0].Title)
+	require.True(t, keys[0].ReadOnly)
+
+	secret := getSecret(t, c, cr)
+	require.Equal(t, corev1.SecretTypeSSHAuth, secret.Type)
+	signer, err := ssh.ParsePrivateKey(secret.Data[corev1.SSHAuthPrivateKey])
+	require.NoError(t, err)
+	require.Equal(t, keys[0].Key, string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	argocd := getFormatSecret(t, r, cr, SecretFormatArgoCD)
+	require.Equal(t, repo.SSHURL, string(argocd.Data["url"]))
+	require.Equal(t, secret.Data[corev1.SSHAuthPrivateKey], argocd.Data["sshPrivateKey"])
+
+	// an existing key is not created again
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.Equal(t, secret.Data, getSecret(t, c, cr).Data)
+
+	require.NoError(t, r.deleteToken(context.Background(), provider, cr))
+	keys, err = provider.ListDeployKeys("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestCredentialKindChange(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "test-ns",
+			Name:        "argocd",
+			Annotations: map[string]string{RepositoryAnnotation: "nephio/mgmt"},
+		},
+	}
+	provider := gitprovider.NewFake("nephio")
+	_, err := provider.CreateRepo(gitprovider.CreateRepoOption{Name: "mgmt"})
+	require.NoError(t, err)
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.Equal(t, corev1.SecretTypeBasicAuth, getSecret(t, c, cr).Type)
+
+	// the secret is recreated with the type of the new credential kind and
+	// the credentials of the previous kind are deleted
+	cr.Annotations[CredentialAnnotation] = CredentialSSH
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	secret := getSecret(t, c, cr)
+	require.Equal(t, corev1.SecretTypeSSHAuth, secret.Type)
+	require.NotEmpty(t, secret.Data[corev1.SSHAuthPrivateKey])
+	require.Empty(t, secret.Data["token"])
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+	keys, err := provider.ListDeployKeys("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	cr.Annotations[CredentialAnnotation] = CredentialToken
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	secret = getSecret(t, c, cr)
+	require.Equal(t, corev1.SecretTypeBasicAuth, secret.Type)
+	require.NotEmpty(t, secret.Data["token"])
+	keys, err = provider.ListDeployKeys("nephio", "mgmt")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func getFormatSecret(t *testing.T, r *reconciler, cr *infrav1alpha1.Token, format string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName() + "-" + format}, secret))
+	return secret
+}
@@ -0,0 +1,200 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SecretFormatsAnnotation lists, comma separated, the formats of the
+	// Secrets written next to the Secret of the Token. Each format writes a
+	// Secret named <token>-<format>.
+	SecretFormatsAnnotation = "infra.nephio.org/secret-formats"
+	// SecretFormatAnnotationPrefix prefixes the annotations holding the
+	// template of a format, infra.nephio.org/secret-format.<format>. A
+	// template overrides the built-in format of the same name.
+	SecretFormatAnnotationPrefix = "infra.nephio.org/secret-format."
+
+	// SecretFormatArgoCD writes an Argo CD repository Secret
+	SecretFormatArgoCD = "argocd"
+	// SecretFormatFlux writes a Secret for a Flux GitRepository
+	SecretFormatFlux = "flux"
+
+	argoCDSecretTypeLabel = "argocd.argoproj.io/secret-type"
+)
+
+// builtinFormats are the templates of the formats that need no annotation
+var builtinFormats = map[string]string{
+	SecretFormatArgoCD: `
+labels:
+  ` + argoCDSecretTypeLabel + `: repository
+stringData:
+  type: git
+  url: {{ quote .RepoURL }}
+{{- if .SSHPrivateKey }}
+  sshPrivateKey: {{ quote .SSHPrivateKey }}
+{{- else }}
+  username: {{ quote .Username }}
+  password: {{ quote .Password }}
+{{- end }}
+`,
+	// flux also needs the known_hosts of the git server for ssh, they are
+	// not known to the controller
+	SecretFormatFlux: `
+stringData:
+{{- if .SSHPrivateKey }}
+  identity: {{ quote .SSHPrivateKey }}
+  identity.pub: {{ quote .SSHPublicKey }}
+{{- else }}
+  username: {{ quote .Username }}
+  password: {{ quote .Password }}
+{{- end }}
+`,
+}
+
+// secretTemplate is the YAML a format template renders to
+type secretTemplate struct {
+	Type        corev1.SecretType `json:"type,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StringData  map[string]string `json:"stringData,omitempty"`
+}
+
+// templateData is the credential a format template is rendered with
+type templateData struct {
+	Username      string
+	Password      string
+	Token         string
+	SSHPrivateKey string
+	SSHPublicKey  string
+	// RepoURL is the URL of the repository of the infra.nephio.org/repository
+	// annotation, the SSH URL for ssh credentials
+	RepoURL string
+}
+
+var templateFuncs = template.FuncMap{
+	// quote returns the value as a double quoted YAML string
+	"quote": func(s string) (string, error) {
+		b, err := json.Marshal(s)
+		return string(b), err
+	},
+}
+
+func parseSecretFormats(annotations map[string]string) ([]string, error) {
+	var formats []string
+	for _, format := range strings.Split(annotations[SecretFormatsAnnotation], ",") {
+		format = strings.TrimSpace(format)
+		if format == "" || slices.Contains(formats, format) {
+			continue
+		}
+		if _, err := formatTemplate(annotations, format); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", SecretFormatsAnnotation, err)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// formatTemplate returns the template of the format, defined by an annotation
+// of the Token or built in
+func formatTemplate(annotations map[string]string, format string) (*template.Template, error) {
+	text, ok := annotations[SecretFormatAnnotationPrefix+format]
+	if !ok {
+		if text, ok = builtinFormats[format]; !ok {
+			return nil, fmt.Errorf("unknown format %q, expected %s, %s or a format defined by a %s%s annotation",
+				format, SecretFormatArgoCD, SecretFormatFlux, SecretFormatAnnotationPrefix, format)
+		}
+	}
+	tmpl, err := template.New(format).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template of format %q: %w", format, err)
+	}
+	return tmpl, nil
+}
+
+// formatSecret returns the Secret of the format holding the credential of the
+// Secret data of the Token. repoURL is the URL of the repository of the
+// credential.
+func formatSecret(cr *infrav1alpha1.Token, format string, data map[string][]byte, repoURL string) (*corev1.Secret, error) {
+	tmpl, err := formatTemplate(cr.GetAnnotations(), format)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData{
+		Username:      string(data["username"]),
+		Password:      string(data["password"]),
+		Token:         string(data["token"]),
+		SSHPrivateKey: string(data[sshPrivateKey]),
+		SSHPublicKey:  string(data[sshPublicKey]),
+		RepoURL:       repoURL,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot render format %q: %w", format, err)
+	}
+	rendered := secretTemplate{}
+	if err := yaml.UnmarshalStrict(buf.Bytes(), &rendered); err != nil {
+		return nil, fmt.Errorf("format %q does not render a secret: %w", format, err)
+	}
+	if rendered.Type == "" {
+		rendered.Type = corev1.SecretTypeOpaque
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+			Kind:       reflect.TypeFor[corev1.Secret]().Name(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cr.GetNamespace(),
+			Name:            fmt.Sprintf("%s-%s", cr.GetName(), format),
+			Labels:          rendered.Labels,
+			Annotations:     rendered.Annotations,
+			OwnerReferences: ownerReferences(cr),
+		},
+		Type: rendered.Type,
+		Data: map[string][]byte{},
+	}
+	for k, v := range rendered.StringData {
+		secret.Data[k] = []byte(v)
+	}
+	return secret, nil
+}
+
+func ownerReferences(cr *infrav1alpha1.Token) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: cr.APIVersion,
+			Kind:       cr.Kind,
+			Name:       cr.Name,
+			UID:        cr.UID,
+			Controller: ptr.To(true),
+		},
+	}
+}
@@ -19,12 +19,16 @@ package token
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"time"
 
-	"code.gitea.io/sdk/gitea"
 	commonv1alpha1 "github.com/nephio-project/api/common/v1alpha1"
 	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	gitv1alpha1 "github.com/nephio-project/nephio/controllers/pkg/apis/git/v1alpha1"
 	"github.com/nephio-project/nephio/controllers/pkg/giteaclient"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 	ctrlconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
 	reconcilerinterface "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
@@ -32,10 +36,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/utils/ptr"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 func init() {
@@ -50,17 +57,15 @@ const (
 
 //+kubebuilder:rbac:groups=infra.nephio.org,resources=tokens,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infra.nephio.org,resources=tokens/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=git.nephio.org,resources=gitservers,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c interface{}) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
 	cfg, ok := c.(*ctrlconfig.ControllerConfig)
 	// Sending the porchclient to gitea, this will be used to get
-	// the secret objects for gitea client authentication. The client
-	// of the manager of this controller cannot be used at this point.
-	// Should this be conditional ? Only if we have repo/token reconciler
+	// the secret objects for gitea client authentication.
 
-	var e error
-	r.giteaClient, e = giteaclient.GetClient(ctx, resource.NewAPIPatchingApplicator(cfg.PorchClient))
+	defaultClient, e := giteaclient.GetClient(ctx, resource.NewAPIPatchingApplicator(cfg.PorchClient))
 	if e != nil {
 		return nil, e
 	}
@@ -72,20 +77,38 @@ func (r *reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, c i
 	if err := infrav1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return nil, err
 	}
+	if err := gitv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+	if err := defaultClient.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
 
 	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
 	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
+	r.gitClients = giteaclient.NewRegistry(mgr.GetClient(), defaultClient)
+	// reconcile the Tokens of a GitServer with its rebuilt client
+	// when the GitServer or its credentials change
+	newList := func() client.ObjectList { return &infrav1alpha1.TokenList{} }
+	gitServerRequests := giteaclient.EnqueueSelecting(mgr.GetClient(), newList)
 
 	return nil, ctrl.NewControllerManagedBy(mgr).
 		Named("TokenController").
 		For(&infrav1alpha1.Token{}).
+		Watches(&gitv1alpha1.GitServer{}, handler.EnqueueRequestsFromMapFunc(gitServerRequests)).
+		Watches(&gitv1alpha1.GitServer{}, r.gitClients.EvictDeleted()).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(gitServerRequests)).
+		// reconcile the objects waiting for the default client when it
+		// becomes ready
+		WatchesRawSource(source.Channel(defaultClient.Subscribe(),
+			handler.EnqueueRequestsFromMapFunc(giteaclient.EnqueueDefault(mgr.GetClient(), newList)))).
 		Complete(r)
 }
 
 type reconciler struct {
 	resource.APIPatchingApplicator
-	giteaClient giteaclient.GiteaClient
-	finalizer   *resource.APIFinalizer
+	gitClients *giteaclient.Registry
+	finalizer  *resource.APIFinalizer
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -102,24 +125,33 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
-	// check if client exists otherwise retry
-	if !r.giteaClient.IsInitialized() {
-		err := fmt.Errorf("gitea server unreachable")
-		log.Error(err, "cannot connect to git server")
+	// get the client of the git server of the CR and check it exists
+	// otherwise retry
+	giteaClient, err := r.gitClients.ClientFor(ctx, cr)
+	if err != nil {
+		log.Error(err, "cannot get git client")
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
+	if !giteaClient.IsInitialized() {
+		// the CR is reconciled again when the client becomes ready
+		err := fmt.Errorf("git server unreachable")
+		log.Error(err, "cannot connect to git server")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
 
 	if resource.WasDeleted(cr) {
-		// TODO DELETION POLICY: "orphan" deletion policy
 		// token being deleted
-		// Delete the token from the git server
+		// Delete the token from the git server unless it is orphaned
 		// when successful remove the finalizer
 		if cr.Spec.Lifecycle.DeletionPolicy == commonv1alpha1.DeletionDelete {
-			if err := r.deleteToken(ctx, r.giteaClient, cr); err != nil {
+			if err := r.deleteToken(ctx, giteaClient, cr); err != nil {
 				log.Error(err, "cannot delete token in git server")
 				return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 			}
+		} else {
+			log.Info("token orphaned", "name", cr.GetTokenName())
 		}
 
 		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
@@ -139,92 +171,306 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 
-	// create token and secret
-	if err := r.createToken(ctx, r.giteaClient, cr); err != nil {
+	// create or rotate token and secret
+	requeueAfter, err := r.createToken(ctx, giteaClient, cr)
+	if err != nil {
+		if errors.Is(err, gitprovider.ErrNotSupported) {
+			// retrying does not help, the Token is reconciled again when
+			// it changes
+			return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
 		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 	cr.SetConditions(infrav1alpha1.Ready())
-	return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	return ctrl.Result{RequeueAfter: requeueAfter}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 }
 
-func (r *reconciler) createToken(ctx context.Context, giteaClient giteaclient.GiteaClient, cr *infrav1alpha1.Token) error {
+// createToken ensures the Secret of the Token holds a credential of the git
+// server, an access token or an SSH deploy key. A credential is issued when
+// the Secret is missing or does not match a credential of the user, and when
+// the credential is older than its max age. The credential replaced by a
+// rotation is deleted once the overlap has passed. The Secrets of the secret
+// formats of the Token are written from the Secret. It returns when the Token
+// is to be reconciled again.
+func (r *reconciler) createToken(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Token) (time.Duration, error) {
 	log := log.FromContext(ctx)
-	tokens, _, err := giteaClient.ListAccessTokens(gitea.ListAccessTokensOptions{})
+	opts, err := parseCredentialOptions(cr)
+	if err != nil {
+		log.Error(err, "invalid credential options")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return 0, err
+	}
+	cred := newCredential(giteaClient, opts)
+	creds, err := cred.list()
 	if err != nil {
 		log.Error(err, "cannot list tokens")
+		if errors.Is(err, gitprovider.ErrNotSupported) && opts.Credential == CredentialToken {
+			// e.g. GitHub has no API to issue personal access tokens
+			err = fmt.Errorf("%w, set the %s annotation to %s to issue an SSH deploy key instead",
+				err, CredentialAnnotation, CredentialSSH)
+		}
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
-		return err
+		return 0, err
+	}
+	names := credentialNames(creds)
+	policy, err := parseRotationPolicy(cr)
+	if err != nil {
+		log.Error(err, "invalid rotation policy")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return 0, err
 	}
-	tokenFound := false
-	for _, token := range tokens {
-		if token.Name == cr.GetTokenName() {
-			tokenFound = true
-			break
+	u, err := giteaClient.GetMyUserInfo()
+	if err != nil {
+		log.Error(err, "cannot get user info")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return 0, err
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}, secret); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			log.Error(err, "cannot get secret")
+			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+			return 0, err
 		}
+		secret = nil
 	}
-	if !tokenFound {
-		u, _, err := giteaClient.GetMyUserInfo()
-		if err != nil {
-			log.Error(err, "cannot get user info")
+	if secret != nil && secret.Type != cred.secretType() {
+		// the type of a Secret is immutable, the Secret of another kind of
+		// credential is deleted and a credential of the new kind is issued
+		log.Info("credential kind changed, recreating secret", "name", cr.GetName(), "type", cred.secretType())
+		if err := r.replaceCredentialKind(ctx, giteaClient, cr, opts, secret); err != nil {
 			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
-			return err
+			return 0, err
 		}
+		secret = nil
+	}
 
-		token, _, err := giteaClient.CreateAccessToken(gitea.CreateAccessTokenOption{
-			Name: cr.GetTokenName(),
-			Scopes: []gitea.AccessTokenScope{
-				gitea.AccessTokenScopeRepo,
-			},
-		})
-		if err != nil {
-			log.Error(err, "cannot create token")
+	now := time.Now()
+	issued := issuedToken{}
+	if secret != nil {
+		issued = issuedTokenFromSecret(secret, cr)
+	}
+	changed := false
+	if issued.PreviousName != "" && !now.Before(issued.PreviousExpiresAt) {
+		issued.PreviousName = ""
+		changed = true
+	}
+
+	var data map[string][]byte
+	matches := secret != nil && cred.matches(secret.Data, u) && slices.Contains(names, issued.Name)
+	// a credential issued with another scope than the desired one is
+	// replaced right away rather than at its next rotation
+	scopeChanged := matches && !slices.Contains(creds, issuedCredential{Name: issued.Name, ReadOnly: opts.ReadOnly})
+	if !matches || scopeChanged || (policy.MaxAge > 0 && !now.Before(policy.nextRotation(issued))) {
+		switch {
+		case scopeChanged:
+			log.Info("token scope changed, issuing a new token", "name", issued.Name, "readOnly", opts.ReadOnly)
+		case matches:
+			log.Info("rotating token", "name", issued.Name)
+		case secret != nil:
+			log.Info("secret does not match a token, issuing a new token", "name", cr.GetName())
+		}
+		name := policy.tokenName(cr, now, names)
+		if data, err = r.issueToken(ctx, cred, u, name, names); err != nil {
 			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
-			return err
+			return 0, err
 		}
-		log.Info("token created", "name", cr.GetName())
-		secret := &corev1.Secret{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: corev1.SchemeGroupVersion.Identifier(),
-				Kind:       reflect.TypeFor[corev1.Secret]().Name(),
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace:   cr.GetNamespace(),
-				Name:        cr.GetName(),
-				Annotations: cr.GetAnnotations(),
-				OwnerReferences: []metav1.OwnerReference{
-					{
-						APIVersion: cr.APIVersion,
-						Kind:       cr.Kind,
-						Name:       cr.Name,
-						UID:        cr.UID,
-						Controller: ptr.To(true),
-					},
-				},
-			},
-			Data: map[string][]byte{
-				"username": []byte(u.UserName),
-				"password": []byte(token.Token), // needed for porch
-				"token":    []byte(token.Token), // needed for configsync
-			},
-			Type: corev1.SecretTypeBasicAuth,
+		// the replaced token keeps working during the overlap, unless it
+		// was replaced by a token with the same name
+		issued.PreviousName = ""
+		if secret != nil && issued.Name != name && slices.Contains(names, issued.Name) {
+			issued.PreviousName = issued.Name
+			issued.PreviousExpiresAt = now.Add(policy.Overlap)
 		}
-		if err := r.Apply(ctx, secret); err != nil {
+		issued.Name = name
+		issued.IssuedAt = now
+		changed = true
+	}
+
+	// tokens of the Token the Secret no longer holds are of no use
+	for _, name := range names {
+		if ownsToken(cr, name) && name != issued.Name && name != issued.PreviousName {
+			if err := r.retireToken(ctx, cred, name); err != nil {
+				cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+				return 0, err
+			}
+		}
+	}
+	cr.SetConditions(issuedCondition(policy, issued))
+
+	if changed {
+		if err := r.Apply(ctx, tokenSecret(cr, cred, data, issued)); err != nil {
 			cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 			log.Error(err, "cannot create secret")
+			return 0, err
+		}
+		log.Info("secret for token updated", "name", cr.GetName(), "token", issued.Name)
+	}
+	if data == nil && secret != nil {
+		data = secret.Data
+	}
+	if err := r.applySecretFormats(ctx, giteaClient, cr, opts, u, data); err != nil {
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return 0, err
+	}
+	return requeueAfter(policy, issued, now), nil
+}
+
+// issueToken issues a credential, replacing any credential with the same
+// name whose value is lost
+func (r *reconciler) issueToken(ctx context.Context, cred credential, u *gitprovider.User, name string, names []string) (map[string][]byte, error) {
+	log := log.FromContext(ctx)
+	if slices.Contains(names, name) {
+		if err := cred.delete(name); err != nil {
+			log.Error(err, "cannot delete token")
+			return nil, err
+		}
+	}
+	data, err := cred.issue(name, u)
+	if err != nil {
+		log.Error(err, "cannot create token")
+		return nil, err
+	}
+	log.Info("token created", "name", name)
+	return data, nil
+}
+
+// retireToken deletes a credential that is no longer held by the Secret
+func (r *reconciler) retireToken(ctx context.Context, cred credential, name string) error {
+	if err := cred.delete(name); err != nil && !gitprovider.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "cannot delete stale token")
+		return err
+	}
+	log.FromContext(ctx).Info("stale token deleted", "name", name)
+	return nil
+}
+
+// replaceCredentialKind deletes the Secret holding a credential of the other
+// kind, and the credentials of that kind issued for the Token
+func (r *reconciler) replaceCredentialKind(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Token, opts credentialOptions, secret *corev1.Secret) error {
+	previous := opts
+	previous.Credential = CredentialSSH
+	if opts.Credential == CredentialSSH {
+		previous.Credential = CredentialToken
+	}
+	if previous.Credential == CredentialSSH && previous.RepoName == "" {
+		// the repository of the deploy keys is no longer known
+		log.FromContext(ctx).Info("cannot delete the deploy keys of the previous credential, no repository", "name", cr.GetName())
+	} else if err := r.deleteCredentials(ctx, newCredential(giteaClient, previous), cr); err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, secret); resource.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "cannot delete secret")
+		return err
+	}
+	return nil
+}
+
+// applySecretFormats writes the Secrets of the secret formats of the Token
+// from the data of its Secret
+func (r *reconciler) applySecretFormats(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Token, opts credentialOptions, u *gitprovider.User, data map[string][]byte) error {
+	if len(opts.Formats) == 0 {
+		return nil
+	}
+	repoURL := ""
+	if opts.RepoName != "" {
+		owner := opts.RepoOwner
+		if owner == "" {
+			owner = u.UserName
+		}
+		repo, err := giteaClient.GetRepo(owner, opts.RepoName)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "cannot get repository of secret formats")
+			return err
+		}
+		repoURL = repo.CloneURL
+		if opts.Credential == CredentialSSH {
+			repoURL = repo.SSHURL
+		}
+	}
+	for _, format := range opts.Formats {
+		secret, err := formatSecret(cr, format, data, repoURL)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "cannot render secret", "format", format)
+			return err
+		}
+		// the type of a Secret is immutable, a format whose type changed
+		// is written again from scratch
+		existing := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing); err == nil && existing.Type != secret.Type {
+			if err := r.Delete(ctx, existing); resource.IgnoreNotFound(err) != nil {
+				log.FromContext(ctx).Error(err, "cannot delete secret", "format", format)
+				return err
+			}
+		}
+		if err := r.Apply(ctx, secret); err != nil {
+			log.FromContext(ctx).Error(err, "cannot apply secret", "format", format)
 			return err
 		}
-		log.Info("secret for token created", "name", cr.GetName())
 	}
 	return nil
 }
 
-func (r *reconciler) deleteToken(ctx context.Context, giteaClient giteaclient.GiteaClient, cr *infrav1alpha1.Token) error {
-	_, err := giteaClient.DeleteAccessToken(cr.GetTokenName())
+// tokenSecret returns the Secret holding the credential. Without data only the
+// annotations recording the credentials are updated.
+func tokenSecret(cr *infrav1alpha1.Token, cred credential, data map[string][]byte, issued issuedToken) *corev1.Secret {
+	annotations := maps.Clone(cr.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	maps.Copy(annotations, issued.annotations())
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.Identifier(),
+			Kind:       reflect.TypeFor[corev1.Secret]().Name(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cr.GetNamespace(),
+			Name:            cr.GetName(),
+			Annotations:     annotations,
+			OwnerReferences: ownerReferences(cr),
+		},
+		Type: cred.secretType(),
+		Data: data,
+	}
+}
+
+// deleteToken deletes the credentials issued for the Token, including the
+// credentials of rotations
+func (r *reconciler) deleteToken(ctx context.Context, giteaClient gitprovider.GitProvider, cr *infrav1alpha1.Token) error {
+	opts, err := parseCredentialOptions(cr)
 	if err != nil {
-		log.FromContext(ctx).Error(err, "cannot delete token")
+		log.FromContext(ctx).Error(err, "invalid credential options")
+		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
+		return err
+	}
+	if err := r.deleteCredentials(ctx, newCredential(giteaClient, opts), cr); err != nil {
 		cr.SetConditions(infrav1alpha1.Failed(err.Error()))
 		return err
 	}
-	log.FromContext(ctx).Info("token deleted", "name", cr.GetTokenName())
+	return nil
+}
+
+// deleteCredentials deletes the credentials of a kind issued for the Token
+func (r *reconciler) deleteCredentials(ctx context.Context, cred credential, cr *infrav1alpha1.Token) error {
+	creds, err := cred.list()
+	if errors.Is(err, gitprovider.ErrNotSupported) {
+		// no credential of this kind was ever issued
+		return nil
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "cannot list tokens")
+		return err
+	}
+	for _, name := range credentialNames(creds) {
+		if !ownsToken(cr, name) {
+			continue
+		}
+		if err := cred.delete(name); err != nil {
+			log.FromContext(ctx).Error(err, "cannot delete token")
+			return err
+		}
+		log.FromContext(ctx).Info("token deleted", "name", name)
+	}
 	return nil
 }
@@ -18,17 +18,21 @@ import (
 	"context"
 	"fmt"
 	"testing"
-
-	"code.gitea.io/sdk/gitea"
+	"time"
 
 	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
 	"github.com/nephio-project/nephio/controllers/pkg/giteaclient"
+	"github.com/nephio-project/nephio/controllers/pkg/gitprovider"
 	"github.com/nephio-project/nephio/controllers/pkg/mocks/external/client"
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	"github.com/nephio-project/nephio/testing/mockeryutils"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 type fields struct {
@@ -56,8 +60,22 @@ func TestDeleteToken(t *testing.T) {
 			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil},
 			args:   args{nil, nil, &infrav1alpha1.Token{}},
 			mocks: []mockeryutils.MockHelper{
+				{MethodName: "ListAccessTokens",
+					ArgType:    []string{},
+					RetArgList: []interface{}{[]*gitprovider.AccessToken{{ID: 123, Name: ""}}, nil}},
 				{MethodName: "DeleteAccessToken",
 					ArgType:    []string{"string"},
+					RetArgList: []interface{}{fmt.Errorf("\"username\" not set: only BasicAuth allowed")}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "List Access tokens reports error",
+			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil},
+			args:   args{nil, nil, &infrav1alpha1.Token{}},
+			mocks: []mockeryutils.MockHelper{
+				{MethodName: "ListAccessTokens",
+					ArgType:    []string{},
 					RetArgList: []interface{}{nil, fmt.Errorf("\"username\" not set: only BasicAuth allowed")}},
 			},
 			wantErr: true,
@@ -67,9 +85,12 @@ func TestDeleteToken(t *testing.T) {
 			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil},
 			args:   args{nil, nil, &infrav1alpha1.Token{}},
 			mocks: []mockeryutils.MockHelper{
+				{MethodName: "ListAccessTokens",
+					ArgType:    []string{},
+					RetArgList: []interface{}{[]*gitprovider.AccessToken{{ID: 123, Name: ""}}, nil}},
 				{MethodName: "DeleteAccessToken",
 					ArgType:    []string{"string"},
-					RetArgList: []interface{}{nil, nil}},
+					RetArgList: []interface{}{nil}},
 			},
 			wantErr: false,
 		},
@@ -78,7 +99,7 @@ func TestDeleteToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &reconciler{
 				APIPatchingApplicator: tt.fields.APIPatchingApplicator,
-				giteaClient:           tt.fields.giteaClient,
+				gitClients:            giteaclient.NewRegistry(nil, tt.fields.giteaClient),
 				finalizer:             tt.fields.finalizer,
 			}
 
@@ -94,8 +115,16 @@ func TestDeleteToken(t *testing.T) {
 func TestCreateToken(t *testing.T) {
 
 	clientMock := new(mocks.MockClient)
-	clientMock.On("Get", nil, mock.AnythingOfType("types.NamespacedName"), mock.AnythingOfType("*v1.Secret")).Return(nil).Run(func(args mock.Arguments) {})
+	clientMock.On("Get", nil, mock.AnythingOfType("types.NamespacedName"), mock.AnythingOfType("*v1.Secret")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(*corev1.Secret).Type = corev1.SecretTypeBasicAuth
+	})
 	clientMock.On("Patch", nil, mock.AnythingOfType("*v1.Secret"), mock.AnythingOfType("*resource.patch")).Return(nil).Run(func(args mock.Arguments) {})
+	// the secret holds a token of the user
+	secretMock := new(mocks.MockClient)
+	secretMock.On("Get", nil, mock.AnythingOfType("types.NamespacedName"), mock.AnythingOfType("*v1.Secret")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(*corev1.Secret).Type = corev1.SecretTypeBasicAuth
+		args.Get(2).(*corev1.Secret).Data = map[string][]byte{"username": []byte("gitea"), "token": []byte("secret")}
+	})
 
 	tests := []tokenTests{
 		{
@@ -104,14 +133,14 @@ func TestCreateToken(t *testing.T) {
 			args:   args{nil, nil, &infrav1alpha1.Token{}},
 			mocks: []mockeryutils.MockHelper{
 				{MethodName: "ListAccessTokens",
-					ArgType:    []string{"gitea.ListAccessTokensOptions"},
-					RetArgList: []interface{}{nil, nil, fmt.Errorf("\"username\" not set: only BasicAuth allowed")}},
+					ArgType:    []string{},
+					RetArgList: []interface{}{nil, fmt.Errorf("\"username\" not set: only BasicAuth allowed")}},
 			},
 			wantErr: true,
 		},
 		{
 			name:   "Create Access token already exists",
-			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil},
+			fields: fields{resource.NewAPIPatchingApplicator(secretMock), nil, nil},
 			args: args{nil, nil, &infrav1alpha1.Token{
 				TypeMeta: metav1.TypeMeta{
 					APIVersion: corev1.SchemeGroupVersion.Identifier(),
@@ -122,11 +151,12 @@ func TestCreateToken(t *testing.T) {
 				}}},
 			mocks: []mockeryutils.MockHelper{
 				{MethodName: "ListAccessTokens",
-					ArgType: []string{"gitea.ListAccessTokensOptions"},
-					RetArgList: []interface{}{[]*gitea.AccessToken{
+					ArgType: []string{},
+					RetArgList: []interface{}{[]*gitprovider.AccessToken{
 						{ID: 123,
 							Name: "test-token-test-ns"},
-					}, nil, nil}},
+					}, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
 			},
 			wantErr: false,
 		},
@@ -136,30 +166,30 @@ func TestCreateToken(t *testing.T) {
 			args:   args{nil, nil, &infrav1alpha1.Token{}},
 			mocks: []mockeryutils.MockHelper{
 				{MethodName: "ListAccessTokens",
-					ArgType: []string{"gitea.ListAccessTokensOptions"},
-					RetArgList: []interface{}{[]*gitea.AccessToken{
+					ArgType: []string{},
+					RetArgList: []interface{}{[]*gitprovider.AccessToken{
 						{ID: 123,
 							Name: "test-token-test-ns"},
-					}, nil, nil}},
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{nil, nil, fmt.Errorf("error getting User Information")}},
+					}, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{nil, fmt.Errorf("error getting User Information")}},
 			},
 			wantErr: true,
 		},
 		{
 			name:   "Create Access token reports failed to create",
-			fields: fields{resource.NewAPIPatchingApplicator(nil), nil, nil},
+			fields: fields{resource.NewAPIPatchingApplicator(clientMock), nil, nil},
 			args:   args{nil, nil, &infrav1alpha1.Token{}},
 			mocks: []mockeryutils.MockHelper{
 				{MethodName: "ListAccessTokens",
-					ArgType: []string{"gitea.ListAccessTokensOptions"},
-					RetArgList: []interface{}{[]*gitea.AccessToken{
+					ArgType: []string{},
+					RetArgList: []interface{}{[]*gitprovider.AccessToken{
 						{ID: 123,
 							Name: "test-token-test-ns"},
-					}, nil, nil}},
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
+					}, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
 				{MethodName: "CreateAccessToken",
-					ArgType:    []string{"gitea.CreateAccessTokenOption"},
-					RetArgList: []interface{}{&gitea.AccessToken{}, nil, fmt.Errorf("failed to create token")}},
+					ArgType:    []string{"gitprovider.CreateAccessTokenOption"},
+					RetArgList: []interface{}{&gitprovider.AccessToken{}, fmt.Errorf("failed to create token")}},
 			},
 			wantErr: true,
 		},
@@ -175,12 +205,12 @@ func TestCreateToken(t *testing.T) {
 					Name:      "test-token",
 				}}},
 			mocks: []mockeryutils.MockHelper{
-				{MethodName: "ListAccessTokens", ArgType: []string{"gitea.ListAccessTokensOptions"}, RetArgList: []interface{}{[]*gitea.AccessToken{}, nil, nil}},
-				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitea.User{UserName: "gitea"}, nil, nil}},
+				{MethodName: "ListAccessTokens", ArgType: []string{}, RetArgList: []interface{}{[]*gitprovider.AccessToken{}, nil}},
+				{MethodName: "GetMyUserInfo", ArgType: []string{}, RetArgList: []interface{}{&gitprovider.User{UserName: "gitea"}, nil}},
 				{MethodName: "CreateAccessToken",
-					ArgType: []string{"gitea.CreateAccessTokenOption"},
-					RetArgList: []interface{}{&gitea.AccessToken{ID: 123,
-						Name: "test-token-test-ns"}, nil, nil}},
+					ArgType: []string{"gitprovider.CreateAccessTokenOption"},
+					RetArgList: []interface{}{&gitprovider.AccessToken{ID: 123,
+						Name: "test-token-test-ns"}, nil}},
 			},
 			wantErr: false,
 		},
@@ -189,19 +219,182 @@ func TestCreateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &reconciler{
 				APIPatchingApplicator: tt.fields.APIPatchingApplicator,
-				giteaClient:           tt.fields.giteaClient,
+				gitClients:            giteaclient.NewRegistry(nil, tt.fields.giteaClient),
 				finalizer:             tt.fields.finalizer,
 			}
 
 			initMockeryMocks(&tt)
 
-			if err := r.createToken(tt.args.ctx, tt.args.giteaClient, tt.args.cr); (err != nil) != tt.wantErr {
+			if _, err := r.createToken(tt.args.ctx, tt.args.giteaClient, tt.args.cr); (err != nil) != tt.wantErr {
 				t.Errorf("createToken() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestTokenLifecycleWithFakeProvider(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-token"},
+	}
+	provider := gitprovider.NewFake("nephio")
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	requeue, err := r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.Zero(t, requeue)
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, cr.GetTokenName(), tokens[0].Name)
+	require.Equal(t, []gitprovider.AccessTokenScope{gitprovider.AccessTokenScopeRepo}, tokens[0].Scopes)
+	secret := getSecret(t, c, cr)
+	require.Equal(t, "nephio", string(secret.Data["username"]))
+	require.NotEmpty(t, secret.Data["token"])
+	require.Equal(t, "Issued", cr.GetCondition(ConditionTypeIssued).Reason)
+
+	// an existing token is not created again
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.Equal(t, secret.Data, getSecret(t, c, cr).Data)
+
+	// a token whose secret was deleted is issued again
+	require.NoError(t, c.Delete(context.Background(), secret))
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	tokens, err = provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.NotEqual(t, secret.Data["token"], getSecret(t, c, cr).Data["token"])
+
+	require.NoError(t, r.deleteToken(context.Background(), provider, cr))
+	tokens, err = provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+}
+
+// noTokensProvider cannot issue access tokens, like GitHub
+type noTokensProvider struct {
+	*gitprovider.Fake
+}
+
+func (noTokensProvider) ListAccessTokens() ([]*gitprovider.AccessToken, error) {
+	return nil, fmt.Errorf("%w: cannot list access tokens", gitprovider.ErrNotSupported)
+}
+
+func TestTokenNotSupported(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-token"},
+	}
+	provider := noTokensProvider{Fake: gitprovider.NewFake("nephio")}
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err := r.createToken(context.Background(), provider, cr)
+	require.ErrorIs(t, err, gitprovider.ErrNotSupported)
+	require.Contains(t, cr.GetCondition(infrav1alpha1.ConditionTypeReady).Message, CredentialAnnotation)
+
+	// nothing was issued, so there is nothing to delete
+	require.NoError(t, r.deleteToken(context.Background(), provider, cr))
+}
+
+func TestTokenRotation(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "test-token",
+			Annotations: map[string]string{
+				MaxAgeAnnotation:  "24h",
+				OverlapAnnotation: "1h",
+			},
+		},
+	}
+	provider := gitprovider.NewFake("nephio")
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	requeue, err := r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.InDelta(t, (24 * time.Hour).Seconds(), requeue.Seconds(), 5)
+	first := getSecret(t, c, cr)
+	firstName := first.Annotations[tokenNameAnnotation]
+	require.True(t, ownsToken(cr, firstName))
+	require.Contains(t, cr.GetCondition(ConditionTypeIssued).Message, "is rotated at")
+
+	// once the token is older than its max age a new token is issued and the
+	// previous token keeps working during the overlap
+	first.Annotations[issuedAtAnnotation] = time.Now().Add(-25 * time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(context.Background(), first))
+	requeue, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.InDelta(t, time.Hour.Seconds(), requeue.Seconds(), 5)
+	second := getSecret(t, c, cr)
+	require.NotEqual(t, first.Data["token"], second.Data["token"])
+	require.Equal(t, firstName, second.Annotations[previousNameAnnotation])
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+
+	// the previous token is deleted after the overlap
+	second.Annotations[previousExpiresAtAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(context.Background(), second))
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	tokens, err = provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, second.Annotations[tokenNameAnnotation], tokens[0].Name)
+	require.Empty(t, getSecret(t, c, cr).Annotations[previousNameAnnotation])
+
+	cr.Annotations[MaxAgeAnnotation] = "-1h"
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.ErrorContains(t, err, MaxAgeAnnotation)
+}
+
+func TestTokenScopeChange(t *testing.T) {
+	cr := &infrav1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-token", Annotations: map[string]string{}},
+	}
+	provider := gitprovider.NewFake("nephio")
+	c := fake.NewClientBuilder().Build()
+	r := &reconciler{APIPatchingApplicator: resource.NewAPIPatchingApplicator(c)}
+
+	_, err := r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	first := getSecret(t, c, cr)
+
+	// the token is issued again with the new scope, without waiting for a
+	// rotation
+	cr.Annotations[ScopeAnnotation] = ScopeRead
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	tokens, err := provider.ListAccessTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, []gitprovider.AccessTokenScope{gitprovider.AccessTokenScopeRepoRead}, tokens[0].Scopes)
+	second := getSecret(t, c, cr)
+	require.NotEqual(t, first.Data["token"], second.Data["token"])
+
+	// a token with the desired scope is kept
+	_, err = r.createToken(context.Background(), provider, cr)
+	require.NoError(t, err)
+	require.Equal(t, second.Data, getSecret(t, c, cr).Data)
+}
+
+func TestOwnsToken(t *testing.T) {
+	cr := &infrav1alpha1.Token{ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "edge"}}
+	require.True(t, ownsToken(cr, "edge-test-ns"))
+	require.True(t, ownsToken(cr, "edge-test-ns-rotated-1700000000"))
+	require.False(t, ownsToken(cr, "edge-test-ns-1"))
+	require.False(t, ownsToken(cr, "edge-test-ns-rotated-x"))
+}
+
+func getSecret(t *testing.T, c client.Client, cr *infrav1alpha1.Token) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}, secret))
+	return secret
+}
+
 func initMockeryMocks(tt *tokenTests) {
 	mockGiteaClient := new(giteaclient.MockGiteaClient)
 	tt.args.giteaClient = mockGiteaClient
@@ -0,0 +1,191 @@
+/*
+ Copyright 2026 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package token
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	infrav1alpha1 "github.com/nephio-project/api/infra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MaxAgeAnnotation sets the age, as a duration, after which the token is
+	// rotated; the token is never rotated if not set
+	MaxAgeAnnotation = "infra.nephio.org/token-max-age"
+	// OverlapAnnotation sets how long, as a duration, the previous token keeps
+	// working after a rotation so consumers can pick up the new Secret
+	OverlapAnnotation = "infra.nephio.org/token-overlap"
+
+	// annotations recording the tokens held by the Secret
+	tokenNameAnnotation         = "infra.nephio.org/token-name"
+	issuedAtAnnotation          = "infra.nephio.org/token-issued-at"
+	previousNameAnnotation      = "infra.nephio.org/previous-token-name"
+	previousExpiresAtAnnotation = "infra.nephio.org/previous-token-expires-at"
+
+	// rotatedTokenInfix separates the name of the Token from the issue time
+	// in the names of rotated tokens
+	rotatedTokenInfix = "-rotated-"
+
+	defaultOverlap = time.Hour
+
+	// ConditionTypeIssued reports the token held by the Secret: its
+	// lastTransitionTime is the time the token was issued and its message
+	// holds the next rotation
+	ConditionTypeIssued = "Issued"
+)
+
+// rotationPolicy holds the rotation settings of a Token
+type rotationPolicy struct {
+	MaxAge  time.Duration
+	Overlap time.Duration
+}
+
+func parseRotationPolicy(cr *infrav1alpha1.Token) (rotationPolicy, error) {
+	policy := rotationPolicy{Overlap: defaultOverlap}
+	if v := cr.GetAnnotations()[MaxAgeAnnotation]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return policy, fmt.Errorf("invalid %s annotation %q, expected a positive duration", MaxAgeAnnotation, v)
+		}
+		policy.MaxAge = d
+	}
+	if v := cr.GetAnnotations()[OverlapAnnotation]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return policy, fmt.Errorf("invalid %s annotation %q, expected a duration", OverlapAnnotation, v)
+		}
+		policy.Overlap = d
+	}
+	return policy, nil
+}
+
+// tokenName returns the name of a token issued at the given time. Tokens that
+// are rotated get a name not used by the existing tokens so the previous token
+// can live on during the overlap.
+func (p rotationPolicy) tokenName(cr *infrav1alpha1.Token, now time.Time, names []string) string {
+	if p.MaxAge == 0 {
+		return cr.GetTokenName()
+	}
+	for ts := now.Unix(); ; ts++ {
+		if name := fmt.Sprintf("%s%s%d", cr.GetTokenName(), rotatedTokenInfix, ts); !slices.Contains(names, name) {
+			return name
+		}
+	}
+}
+
+// nextRotation returns when the token is to be rotated, the zero time if it
+// is never rotated
+func (p rotationPolicy) nextRotation(issued issuedToken) time.Time {
+	if p.MaxAge == 0 {
+		return time.Time{}
+	}
+	return issued.IssuedAt.Add(p.MaxAge)
+}
+
+// issuedToken records the tokens held by the Secret of a Token: the current
+// token, and the token it replaced until that one expires
+type issuedToken struct {
+	Name              string
+	IssuedAt          time.Time
+	PreviousName      string
+	PreviousExpiresAt time.Time
+}
+
+// issuedTokenFromSecret reads the tokens recorded in the Secret. Secrets
+// written before tokens were recorded hold the token named after the Token,
+// issued when the Secret was created.
+func issuedTokenFromSecret(secret *corev1.Secret, cr *infrav1alpha1.Token) issuedToken {
+	annotations := secret.GetAnnotations()
+	issued := issuedToken{
+		Name:         annotations[tokenNameAnnotation],
+		PreviousName: annotations[previousNameAnnotation],
+	}
+	if issued.Name == "" {
+		issued.Name = cr.GetTokenName()
+	}
+	issued.IssuedAt = secret.GetCreationTimestamp().Time
+	if t, err := time.Parse(time.RFC3339, annotations[issuedAtAnnotation]); err == nil {
+		issued.IssuedAt = t
+	}
+	if t, err := time.Parse(time.RFC3339, annotations[previousExpiresAtAnnotation]); err == nil {
+		issued.PreviousExpiresAt = t
+	}
+	return issued
+}
+
+func (t issuedToken) annotations() map[string]string {
+	annotations := map[string]string{
+		tokenNameAnnotation:         t.Name,
+		issuedAtAnnotation:          t.IssuedAt.UTC().Format(time.RFC3339),
+		previousNameAnnotation:      t.PreviousName,
+		previousExpiresAtAnnotation: "",
+	}
+	if t.PreviousName != "" {
+		annotations[previousExpiresAtAnnotation] = t.PreviousExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return annotations
+}
+
+// ownsToken returns true if the token was issued for the Token, either with
+// the name of the Token or with the unique name of a rotated token
+func ownsToken(cr *infrav1alpha1.Token, name string) bool {
+	if name == cr.GetTokenName() {
+		return true
+	}
+	suffix, found := strings.CutPrefix(name, cr.GetTokenName()+rotatedTokenInfix)
+	if !found {
+		return false
+	}
+	_, err := strconv.ParseInt(suffix, 10, 64)
+	return err == nil
+}
+
+// requeueAfter returns the time until the next rotation or until the previous
+// token expires, whichever comes first, or 0 if neither is pending
+func requeueAfter(policy rotationPolicy, issued issuedToken, now time.Time) time.Duration {
+	var pending []time.Time
+	if next := policy.nextRotation(issued); !next.IsZero() {
+		pending = append(pending, next)
+	}
+	if issued.PreviousName != "" {
+		pending = append(pending, issued.PreviousExpiresAt)
+	}
+	if len(pending) == 0 {
+		return 0
+	}
+	return max(slices.MinFunc(pending, time.Time.Compare).Sub(now), time.Second)
+}
+
+func issuedCondition(policy rotationPolicy, issued issuedToken) infrav1alpha1.Condition {
+	message := fmt.Sprintf("token %s is not rotated", issued.Name)
+	if next := policy.nextRotation(issued); !next.IsZero() {
+		message = fmt.Sprintf("token %s is rotated at %s", issued.Name, next.UTC().Format(time.RFC3339))
+	}
+	return infrav1alpha1.Condition{Condition: metav1.Condition{
+		Type:               ConditionTypeIssued,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(issued.IssuedAt),
+		Reason:             "Issued",
+		Message:            message,
+	}}
+}
@@ -25,18 +25,39 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // An APIPatchingApplicator applies changes to an object by either creating or
 // patching it in a Kubernetes API server.
 type APIPatchingApplicator struct {
 	client.Client
+	// serverSideApply is set when the objects are applied with server-side
+	// apply instead of merge patches
+	serverSideApply *serverSideApply
+}
+
+type serverSideApply struct {
+	fieldManager string
+	force        bool
 }
 
 // NewAPIPatchingApplicator returns an Applicator that applies changes to an
 // object by either creating or patching it in a Kubernetes API server.
 func NewAPIPatchingApplicator(c client.Client) APIPatchingApplicator {
-	return APIPatchingApplicator{c}
+	return APIPatchingApplicator{Client: c}
+}
+
+// WithServerSideApply returns a copy of the applicator that applies the
+// objects with server-side apply as the field manager. Fields owned by other
+// managers are taken over if force is set, otherwise the conflict is returned
+// as an error for which k8s.io/apimachinery/pkg/api/errors.IsConflict is true.
+// Fields the field manager applied before and are no longer in the object are
+// removed.
+func (a *APIPatchingApplicator) WithServerSideApply(fieldManager string, force bool) APIPatchingApplicator {
+	applicator := *a
+	applicator.serverSideApply = &serverSideApply{fieldManager: fieldManager, force: force}
+	return applicator
 }
 
 // Apply changes to the supplied object. The object will be created if it does
@@ -56,6 +77,13 @@ func (a *APIPatchingApplicator) Apply(ctx context.Context, o client.Object, ao .
 		return errors.Wrap(a.Create(ctx, o), "cannot create object")
 	}
 
+	if a.serverSideApply != nil {
+		if len(ao) > 0 {
+			return errors.New("apply options are not supported with server-side apply")
+		}
+		return a.applyServerSide(ctx, o)
+	}
+
 	desired := o.DeepCopyObject()
 
 	err := a.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, o)
@@ -81,3 +109,21 @@ type patch struct{ from client.Object }
 
 func (p *patch) Type() types.PatchType                { return types.MergePatchType }
 func (p *patch) Data(_ client.Object) ([]byte, error) { return json.Marshal(p.from) }
+
+// applyServerSide applies the object with server-side apply, the object is
+// updated with the result
+func (a *APIPatchingApplicator) applyServerSide(ctx context.Context, o client.Object) error {
+	gvk, err := apiutil.GVKForObject(o, a.Scheme())
+	if err != nil {
+		return errors.Wrap(err, "cannot get object kind")
+	}
+	// the apply configuration has its kind and no managed fields
+	o.GetObjectKind().SetGroupVersionKind(gvk)
+	o.SetManagedFields(nil)
+
+	opts := []client.PatchOption{client.FieldOwner(a.serverSideApply.fieldManager)}
+	if a.serverSideApply.force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return errors.Wrap(a.Patch(ctx, o, client.Apply, opts...), "cannot apply object")
+}
@@ -22,10 +22,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -158,3 +161,100 @@ func TestAPIPatchingApplicator(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIServerSideApplicator(t *testing.T) {
+	errConflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm", errors.New("conflict with \"kubectl\""))
+
+	type want struct {
+		opts []client.PatchOption
+		err  bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		force    bool
+		patchErr error
+		ao       []ApplyOption
+		want     want
+	}{
+		"Report": {
+			reason: "The object should be applied as the field manager",
+			want: want{
+				opts: []client.PatchOption{client.FieldOwner("nephio")},
+			},
+		},
+		"Force": {
+			reason: "The object should be applied taking the ownership of the fields",
+			force:  true,
+			want: want{
+				opts: []client.PatchOption{client.FieldOwner("nephio"), client.ForceOwnership},
+			},
+		},
+		"Conflict": {
+			reason:   "A conflict should be returned as an error",
+			patchErr: errConflict,
+			want: want{
+				opts: []client.PatchOption{client.FieldOwner("nephio")},
+				err:  true,
+			},
+		},
+		"ApplyOptions": {
+			reason: "Apply options should be rejected",
+			ao:     []ApplyOption{UpdateFn(func(_, _ runtime.Object) {})},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched []byte
+			var opts []client.PatchOption
+			c := &MockClient{
+				MockScheme: NewMockSchemeFn(clientgoscheme.Scheme),
+				MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, o ...client.PatchOption) error {
+					if patch.Type() != types.ApplyPatchType {
+						t.Errorf("\n%s\nApply(...): want apply patch, got %s", tc.reason, patch.Type())
+					}
+					data, err := patch.Data(obj)
+					if err != nil {
+						return err
+					}
+					patched, opts = data, o
+					return tc.patchErr
+				},
+			}
+			a := NewAPIPatchingApplicator(c)
+			a = a.WithServerSideApply("nephio", tc.force)
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:     "default",
+					Name:          "cm",
+					ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+				},
+				Data: map[string]string{"a": "b"},
+			}
+			err := a.Apply(context.Background(), cm, tc.ao...)
+			if tc.want.err {
+				if err == nil {
+					t.Errorf("\n%s\nApply(...): want error, got nil", tc.reason)
+				}
+			} else if err != nil {
+				t.Errorf("\n%s\nApply(...): want no error, got %v", tc.reason, err)
+			}
+			if tc.patchErr != nil && !kerrors.IsConflict(err) {
+				t.Errorf("\n%s\nApply(...): want conflict, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.opts, opts); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want options, +got options\n%s\n", tc.reason, diff)
+			}
+			if len(tc.ao) == 0 {
+				want := `{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm","namespace":"default","creationTimestamp":null},"data":{"a":"b"}}`
+				if diff := cmp.Diff(want, string(patched)); diff != "" {
+					t.Errorf("\n%s\nApply(...): -want patch, +got patch\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
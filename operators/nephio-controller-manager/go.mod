@@ -23,6 +23,7 @@ require (
 
 require (
 	code.gitea.io/sdk/gitea v0.22.0 // indirect
+	filippo.io/age v1.2.1 // indirect
 	github.com/42wim/httpsig v1.2.3 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
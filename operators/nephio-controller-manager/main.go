@@ -22,7 +22,10 @@ import (
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"strings"
+	"time"
 
+	clustercache "github.com/nephio-project/nephio/controllers/pkg/cluster/cache"
+	"github.com/nephio-project/nephio/controllers/pkg/cluster/capi"
 	porchclient "github.com/nephio-project/nephio/controllers/pkg/porch/client"
 	ctrlrconfig "github.com/nephio-project/nephio/controllers/pkg/reconcilers/config"
 	reconciler "github.com/nephio-project/nephio/controllers/pkg/reconcilers/reconciler-interface"
@@ -54,6 +57,7 @@ import (
 	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/generic-specializer"
 	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/network"
 	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/repository"
+	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/rollback"
 	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/spire-bootstrap"
 	_ "github.com/nephio-project/nephio/controllers/pkg/reconcilers/token"
 )
@@ -68,6 +72,10 @@ func main() {
 	var probeAddr string
 	var enabledReconcilersString string
 	var approvalRequeueDuration int64
+	var approvalRecordRetention time.Duration
+	var rollbackRequeueDuration int64
+	var remoteClusterInformers bool
+	var capiReadinessCriteria string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -76,6 +84,11 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&enabledReconcilersString, "reconcilers", "", "reconcilers that should be enabled; use * to mean 'enable all'")
 	flag.Int64Var(&approvalRequeueDuration, "approval-requeue-duration", 15, "Interval to allow before requeue of the approval controller reconcile key")
+	flag.DurationVar(&approvalRecordRetention, "approval-record-retention", 90*24*time.Hour, "How long the approval records are kept; 0 keeps them forever")
+	flag.Int64Var(&rollbackRequeueDuration, "rollback-requeue-duration", 15, "Interval to allow before requeue of the rollback controller reconcile key")
+	flag.BoolVar(&remoteClusterInformers, "remote-cluster-informers", false, "Serve the reads of the remote cluster clients from informer caches")
+	flag.StringVar(&capiReadinessCriteria, "capi-readiness-criteria", capi.CriterionReady,
+		"Comma-separated criteria a CAPI cluster must meet to be ready: Ready, ControlPlaneReady, InfrastructureReady, APIServerReachable, MinReadyReplicas=<n>")
 
 	opts := zap.Options{
 		Development: true,
@@ -86,12 +99,19 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	readinessCriteria, err := capi.ParseReadinessCriteria(capiReadinessCriteria)
+	if err != nil {
+		setupLog.Error(err, "invalid capi-readiness-criteria")
+		os.Exit(1)
+	}
+	capi.SetDefaultReadinessCriteria(readinessCriteria)
+
 	scheme := runtime.NewScheme()
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
 		setupLog.Error(err, "cannot initializer schema")
 		os.Exit(1)
 	}
-	err := porchclient.AddToScheme(scheme)
+	err = porchclient.AddToScheme(scheme)
 	if err != nil {
 		setupLog.Error(err, "cannot initializer schema with porch API(s)")
 		os.Exit(1)
@@ -133,6 +153,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// the clients of the remote clusters are shared by all reconcilers
+	if err := mgr.Add(clustercache.Default().WithInformers(remoteClusterInformers, scheme)); err != nil {
+		setupLog.Error(err, "cannot add remote cluster client cache")
+		os.Exit(1)
+	}
+
 	// Prepare configuration for reconcilers
 	backendAddress := "127.0.0.1:9999"
 	if address, ok := os.LookupEnv("CLIENT_PROXY_ADDRESS"); ok {
@@ -150,10 +176,13 @@ func main() {
 			Address: backendAddress,
 		}),
 		ApprovalRequeueDuration: approvalRequeueDuration,
+		ApprovalRecordRetention: approvalRecordRetention,
+		RollbackRequeueDuration: rollbackRequeueDuration,
 	}
 
 	enabledReconcilers := parseReconcilers(enabledReconcilersString)
 	var enabled []string
+	var setUp []reconciler.Reconciler
 	for name, r := range reconciler.Reconcilers {
 		if !reconcilerIsEnabled(enabledReconcilers, name) {
 			continue
@@ -163,6 +192,14 @@ func main() {
 			os.Exit(1)
 		}
 		enabled = append(enabled, name)
+		setUp = append(setUp, r)
+	}
+
+	// the reconcilers share the field indexes of the manager cache, they are
+	// registered once the reconcilers have added their types to the scheme
+	if err := reconciler.SetupIndexes(ctx, mgr.GetFieldIndexer(), setUp); err != nil {
+		setupLog.Error(err, "cannot setup field indexes")
+		os.Exit(1)
 	}
 
 	if len(enabled) == 0 {